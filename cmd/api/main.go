@@ -1,11 +1,14 @@
 package main
 
 import (
+	"crypto/rand"
+
 	"github.com/LikhithMar14/gopher-chat/internal/api"
 	"github.com/LikhithMar14/gopher-chat/internal/config"
 	"github.com/LikhithMar14/gopher-chat/internal/migrations"
 	"github.com/LikhithMar14/gopher-chat/internal/store"
 	db "github.com/LikhithMar14/gopher-chat/internal/store/database"
+	"github.com/LikhithMar14/gopher-chat/internal/utils/blob"
 	"github.com/LikhithMar14/gopher-chat/internal/utils/mailer"
 	"go.uber.org/zap"
 )
@@ -39,6 +42,10 @@ func main() {
 	logger := zap.Must(zap.NewProduction()).Sugar()
 	defer logger.Sync()
 
+	if err := cfg.Validate(); err != nil {
+		logger.Fatalw("Invalid configuration", "error", err)
+	}
+
 	database, err := db.Open(cfg.DB.Addr, cfg.DB.MaxOpenConns, cfg.DB.MaxIdleConns, cfg.DB.MaxLifetime)
 	if err != nil {
 		logger.Fatalw("Failed to open database connection", "error", err)
@@ -59,7 +66,21 @@ func main() {
 	}
 	storage := store.NewStorage(database)
 
-	app := api.NewApplication(cfg, storage, Version, logger, mailClient)
+	blobs, err := blob.New(cfg.Media)
+	if err != nil {
+		logger.Fatalw("Failed to create blob store", "error", err)
+	}
+	signingSecret := cfg.Media.SigningSecret
+	if signingSecret == "" {
+		if cfg.Env == "prod" {
+			logger.Fatal("MEDIA_SIGNING_SECRET must be set in production")
+		}
+		logger.Warn("MEDIA_SIGNING_SECRET is not set, download URLs will not survive a restart")
+		signingSecret = rand.Text()
+	}
+	signer := blob.NewURLSigner(signingSecret, cfg.APIURL, cfg.Media.URLExpiry)
+
+	app := api.NewApplication(cfg, storage, Version, logger, mailClient, blobs, signer)
 
 	mux := app.Routes()
 
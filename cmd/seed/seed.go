@@ -5,8 +5,12 @@ import (
 	"github.com/LikhithMar14/gopher-chat/internal/service"
 	"github.com/LikhithMar14/gopher-chat/internal/store"
 	db "github.com/LikhithMar14/gopher-chat/internal/store/database"
+	"github.com/LikhithMar14/gopher-chat/internal/utils/blob"
 	"github.com/LikhithMar14/gopher-chat/internal/utils/env"
 	mailer "github.com/LikhithMar14/gopher-chat/internal/utils/mailer"
+	"github.com/LikhithMar14/gopher-chat/pkg/activitypub"
+	"github.com/LikhithMar14/gopher-chat/pkg/linkpreview"
+	"github.com/LikhithMar14/gopher-chat/pkg/webhook"
 	"go.uber.org/zap"
 )
 
@@ -38,10 +42,31 @@ func main() {
 
 	storage := store.NewStorage(database)
 
-	postService := service.NewPostService(storage)
-	commentService := service.NewCommentService(storage)
+	blobs, err := blob.New(cfg.Media)
+	if err != nil {
+		logger.Fatal("Failed to create blob store", zap.Error(err))
+	}
+	signer := blob.NewURLSigner(cfg.Media.SigningSecret, cfg.APIURL, cfg.Media.URLExpiry)
+	mediaProcessor := service.NewMediaProcessor(storage, blobs, logger, cfg.Media.Workers)
+	mediaService := service.NewMediaService(storage, blobs, signer, mediaProcessor, cfg.Media.MaxUploadSize)
+
+	linkPreviewFetcher := linkpreview.NewFetcher(cfg.LinkPreview.Timeout, cfg.LinkPreview.MaxBytes)
+	linkPreviewWorker := service.NewLinkPreviewWorker(storage, linkPreviewFetcher, cfg.LinkPreview.TTL, logger, cfg.LinkPreview.Workers)
+
+	timelineWorker := service.NewTimelineWorker(storage, cfg.Timeline.MaxFanOut, cfg.Timeline.BackfillSize, cfg.Timeline.Workers, logger)
+
+	federationClient := activitypub.NewClient(cfg.Federation.Timeout, cfg.Federation.AllowPrivateAddresses)
+	deliveryWorker := service.NewDeliveryWorker(storage, federationClient, cfg.APIURL, cfg.Federation.DeliveryWorkers, logger)
+	notificationService := service.NewNotificationService(storage, mediaService, logger)
+	webhookWorker := service.NewWebhookWorker(storage, webhook.NewClient(cfg.Webhooks.Timeout, cfg.Webhooks.AllowPrivateAddresses), cfg.Webhooks.Workers, logger)
 	mailer := mailer.NewSendgrid(cfg.Mail.Sendgrid.APIKey, cfg.FromEmail)
-	authService := service.NewAuthService(storage, cfg.Mail.Exp, mailer, cfg, logger)
+	outboxDispatcher := service.NewOutboxDispatcher(storage, mailer, cfg.Env != "prod", webhookWorker, cfg.Outbox.Workers, logger)
+	webhookService := service.NewWebhookService(storage, webhookWorker, outboxDispatcher)
+	federationService := service.NewFederationService(storage, federationClient, timelineWorker, deliveryWorker, notificationService, cfg.APIURL, cfg.FrontendURL, logger)
+
+	postService := service.NewPostService(storage, mediaService, linkPreviewWorker, timelineWorker, federationService, notificationService, webhookService, logger)
+	commentService := service.NewCommentService(storage, mediaService, notificationService, webhookService)
+	authService := service.NewAuthService(storage, cfg.Mail.Exp, cfg, outboxDispatcher, logger)
 
 	err = db.Seed(database, authService, postService, commentService, logger)
 	if err != nil {
@@ -1,56 +1,137 @@
 package api
 
 import (
+	"context"
 	"net/http"
 	"time"
 
 	"github.com/LikhithMar14/gopher-chat/docs"
 	"github.com/LikhithMar14/gopher-chat/internal/config"
+	"github.com/LikhithMar14/gopher-chat/internal/models"
 	"github.com/LikhithMar14/gopher-chat/internal/service"
 	"github.com/LikhithMar14/gopher-chat/internal/store"
+	"github.com/LikhithMar14/gopher-chat/internal/utils/blob"
 	"github.com/LikhithMar14/gopher-chat/internal/utils/mailer"
+	"github.com/LikhithMar14/gopher-chat/pkg/activitypub"
+	"github.com/LikhithMar14/gopher-chat/pkg/linkpreview"
+	"github.com/LikhithMar14/gopher-chat/pkg/webhook"
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 )
 
 type Application struct {
-	Config         config.Config
-	Store          store.Storage
-	UserService    *service.UserService
-	PostService    *service.PostService
-	CommentService *service.CommentService
-	FollowService  *service.FollowService
-	FeedService    *service.FeedService
-	AuthService    *service.AuthService	
-	Version        string
-	Logger         *zap.SugaredLogger
-	Mailer         mailer.Client
+	Config                  config.Config
+	Store                   store.Storage
+	UserService             *service.UserService
+	PostService             *service.PostService
+	CommentService          *service.CommentService
+	FollowService           *service.FollowService
+	FeedService             *service.FeedService
+	AuthService             *service.AuthService
+	EngagementService       *service.EngagementService
+	RepostService           *service.RepostService
+	MentionService          *service.MentionService
+	MediaService            *service.MediaService
+	MediaProcessor          *service.MediaProcessor
+	LinkPreviewWorker       *service.LinkPreviewWorker
+	TimelineWorker          *service.TimelineWorker
+	PollService             *service.PollService
+	BlockService            *service.BlockService
+	SuggestionService       *service.SuggestionService
+	ExploreService          *service.ExploreService
+	SyndicationService      *service.SyndicationService
+	TrendingWorker          *service.TrendingWorker
+	FederationService       *service.FederationService
+	DeliveryWorker          *service.DeliveryWorker
+	NotificationService     *service.NotificationService
+	NotificationWorker      *service.NotificationWorker
+	NotificationEmailWorker *service.NotificationEmailWorker
+	WebhookService          *service.WebhookService
+	WebhookWorker           *service.WebhookWorker
+	OutboxDispatcher        *service.OutboxDispatcher
+	OutboxService           *service.OutboxService
+	Version                 string
+	Logger                  *zap.SugaredLogger
+	Mailer                  mailer.Client
 }
 
-func NewApplication(cfg config.Config, store store.Storage, version string, logger *zap.SugaredLogger, mailer mailer.Client) *Application {
-	userService := service.NewUserService(store)
-	postService := service.NewPostService(store)
-	commentService := service.NewCommentService(store)
-	followService := service.NewFollowService(store)
-	feedService := service.NewFeedService(store)
-	authService := service.NewAuthService(store, cfg.Mail.Exp, mailer, cfg, logger)
+func NewApplication(cfg config.Config, store store.Storage, version string, logger *zap.SugaredLogger, mailer mailer.Client, blobs blob.Store, signer *blob.URLSigner) *Application {
+	mediaProcessor := service.NewMediaProcessor(store, blobs, logger, cfg.Media.Workers)
+	mediaService := service.NewMediaService(store, blobs, signer, mediaProcessor, cfg.Media.MaxUploadSize)
+	linkPreviewFetcher := linkpreview.NewFetcher(cfg.LinkPreview.Timeout, cfg.LinkPreview.MaxBytes)
+	linkPreviewWorker := service.NewLinkPreviewWorker(store, linkPreviewFetcher, cfg.LinkPreview.TTL, logger, cfg.LinkPreview.Workers)
+	timelineWorker := service.NewTimelineWorker(store, cfg.Timeline.MaxFanOut, cfg.Timeline.BackfillSize, cfg.Timeline.Workers, logger)
+	federationClient := activitypub.NewClient(cfg.Federation.Timeout, cfg.Federation.AllowPrivateAddresses)
+	deliveryWorker := service.NewDeliveryWorker(store, federationClient, cfg.APIURL, cfg.Federation.DeliveryWorkers, logger)
+	notificationService := service.NewNotificationService(store, mediaService, logger)
+	notificationWorker := service.NewNotificationWorker(store, notificationService, logger)
+	notificationEmailWorker := service.NewNotificationEmailWorker(store, notificationService, mailer, cfg.FrontendURL, cfg.Env != "prod", cfg.NotificationEmail.Workers, cfg.NotificationEmail.PollInterval, logger)
+	webhookWorker := service.NewWebhookWorker(store, webhook.NewClient(cfg.Webhooks.Timeout, cfg.Webhooks.AllowPrivateAddresses), cfg.Webhooks.Workers, logger)
+	outboxDispatcher := service.NewOutboxDispatcher(store, mailer, cfg.Env != "prod", webhookWorker, cfg.Outbox.Workers, logger)
+	webhookService := service.NewWebhookService(store, webhookWorker, outboxDispatcher)
+	outboxService := service.NewOutboxService(store, outboxDispatcher)
+	federationService := service.NewFederationService(store, federationClient, timelineWorker, deliveryWorker, notificationService, cfg.APIURL, cfg.FrontendURL, logger)
+	postService := service.NewPostService(store, mediaService, linkPreviewWorker, timelineWorker, federationService, notificationService, webhookService, logger)
+	commentService := service.NewCommentService(store, mediaService, notificationService, webhookService)
+	followService := service.NewFollowService(store, mediaService, timelineWorker, federationService, notificationService)
+	userService := service.NewUserService(store, followService, mediaService)
+	feedService := service.NewFeedService(store, mediaService, models.FeedRanking{
+		Window:           cfg.FeedRanking.Window,
+		HalfLife:         cfg.FeedRanking.HalfLife,
+		RecencyWeight:    cfg.FeedRanking.RecencyWeight,
+		EngagementWeight: cfg.FeedRanking.EngagementWeight,
+		AuthorWeight:     cfg.FeedRanking.AuthorWeight,
+		TagWeight:        cfg.FeedRanking.TagWeight,
+	})
+	authService := service.NewAuthService(store, cfg.Mail.Exp, cfg, outboxDispatcher, logger)
+	engagementService := service.NewEngagementService(store, mediaService, notificationService)
+	repostService := service.NewRepostService(store, timelineWorker)
+	mentionService := service.NewMentionService(store, mediaService)
+	pollService := service.NewPollService(store)
+	blockService := service.NewBlockService(store, mediaService)
+	suggestionService := service.NewSuggestionService(store, mediaService, cfg.Suggestions.TTL, cfg.Suggestions.Size)
+	exploreService := service.NewExploreService(store, mediaService)
+	syndicationService := service.NewSyndicationService(store, mediaService, cfg.APIURL, cfg.FrontendURL)
+	trendingWorker := service.NewTrendingWorker(store, cfg.Trending.Interval, cfg.Trending.Size, logger)
 
 	return &Application{
-		Config:         cfg,
-		Store:          store,
-		UserService:    userService,
-		PostService:    postService,
-		CommentService: commentService,
-		FollowService:  followService,
-		FeedService:    feedService,
-		AuthService:    authService,
-		Version:        version,
-		Logger:         logger,
+		Config:                  cfg,
+		Store:                   store,
+		UserService:             userService,
+		PostService:             postService,
+		CommentService:          commentService,
+		FollowService:           followService,
+		FeedService:             feedService,
+		AuthService:             authService,
+		EngagementService:       engagementService,
+		RepostService:           repostService,
+		MentionService:          mentionService,
+		MediaService:            mediaService,
+		MediaProcessor:          mediaProcessor,
+		LinkPreviewWorker:       linkPreviewWorker,
+		TimelineWorker:          timelineWorker,
+		PollService:             pollService,
+		BlockService:            blockService,
+		SuggestionService:       suggestionService,
+		ExploreService:          exploreService,
+		SyndicationService:      syndicationService,
+		TrendingWorker:          trendingWorker,
+		FederationService:       federationService,
+		DeliveryWorker:          deliveryWorker,
+		NotificationService:     notificationService,
+		NotificationWorker:      notificationWorker,
+		NotificationEmailWorker: notificationEmailWorker,
+		WebhookService:          webhookService,
+		WebhookWorker:           webhookWorker,
+		OutboxDispatcher:        outboxDispatcher,
+		OutboxService:           outboxService,
+		Version:                 version,
+		Logger:                  logger,
 	}
 }
 
 func (app *Application) Serve(mux *chi.Mux) error {
-	//	
+	//
 	docs.SwaggerInfo.Title = "Gopher Chat API"
 	docs.SwaggerInfo.Version = app.Version
 	docs.SwaggerInfo.Host = "localhost:8080"
@@ -63,6 +144,18 @@ func (app *Application) Serve(mux *chi.Mux) error {
 		IdleTimeout:  time.Minute,
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go app.MediaProcessor.Run(ctx)
+	go app.LinkPreviewWorker.Run(ctx)
+	go app.TimelineWorker.Run(ctx)
+	go app.TrendingWorker.Run(ctx)
+	go app.DeliveryWorker.Run(ctx)
+	go app.NotificationWorker.Run(ctx)
+	go app.NotificationEmailWorker.Run(ctx)
+	go app.WebhookWorker.Run(ctx)
+	go app.OutboxDispatcher.Run(ctx)
+
 	app.Logger.Infow("Server has started", "addr", app.Config.Addr, "env", app.Config.Env, "version", app.Version)
 
 	if err := srv.ListenAndServe(); err != nil {
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/LikhithMar14/gopher-chat/internal/service"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	"github.com/LikhithMar14/gopher-chat/internal/utils/blob"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"go.uber.org/zap"
+)
+
+// multipartOverhead is the allowance on top of the maximum file size for the
+// multipart boundaries and headers of an upload request.
+const multipartOverhead = 1 << 20
+
+type AttachmentHandler struct {
+	mediaService  *service.MediaService
+	maxUploadSize int64
+	logger        *zap.SugaredLogger
+}
+
+func NewAttachmentHandler(mediaService *service.MediaService, maxUploadSize int64, logger *zap.SugaredLogger) *AttachmentHandler {
+	return &AttachmentHandler{
+		mediaService:  mediaService,
+		maxUploadSize: maxUploadSize,
+		logger:        logger,
+	}
+}
+
+// UploadAttachment godoc
+//
+//	@Summary		Upload an attachment
+//	@Description	Upload an image or file. The returned attachment id can be passed in attachment_ids when creating a post or comment. Images are processed in the background and have no URL until their status is ready
+//	@Tags			attachments
+//	@Accept			mpfd
+//	@Produce		json
+//	@Param			file	formData	file					true	"File to upload"
+//	@Success		201		{object}	models.Attachment		"Attachment uploaded successfully"
+//	@Failure		400		{object}	utils.StandardResponse	"Missing or malformed file"
+//	@Failure		401		{object}	utils.StandardResponse	"Unauthorized"
+//	@Failure		413		{object}	utils.StandardResponse	"File too large"
+//	@Failure		415		{object}	utils.StandardResponse	"Unsupported media type"
+//	@Failure		500		{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/attachments [post]
+func (h *AttachmentHandler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxUploadSize+multipartOverhead)
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			utils.WriteErrorResponse(w, http.StatusRequestEntityTooLarge, apperrors.ErrFileTooLarge.Error())
+			return
+		}
+		utils.HandleValidationError(w, errors.New("a file must be uploaded in the \"file\" form field"))
+		return
+	}
+	defer file.Close()
+
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx := utils.SetUserID(r.Context(), int64(688))
+
+	attachment, err := h.mediaService.Upload(ctx, header.Filename, file, header.Size)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrFileTooLarge):
+			utils.WriteErrorResponse(w, http.StatusRequestEntityTooLarge, err.Error())
+		case errors.Is(err, apperrors.ErrUnsupportedMediaType):
+			utils.WriteErrorResponse(w, http.StatusUnsupportedMediaType, err.Error())
+		case errors.Is(err, apperrors.ErrUserIDNotFound):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+		default:
+			utils.HandleInternalError(w, err)
+		}
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusCreated, attachment)
+}
+
+// DownloadAttachment godoc
+//
+//	@Summary		Download an attachment
+//	@Description	Stream the contents of an attachment or one of its resized variants. Only reachable through the signed, expiring URLs returned with the attachment
+//	@Tags			attachments
+//	@Produce		octet-stream
+//	@Param			id			path	int		true	"Attachment ID"
+//	@Param			variant		query	string	false	"Resized variant (small, medium or large)"
+//	@Param			expires		query	int		true	"Expiry of the URL as a Unix timestamp"
+//	@Param			signature	query	string	true	"URL signature"
+//	@Success		200
+//	@Failure		403	{object}	utils.StandardResponse	"Invalid or expired signature"
+//	@Failure		404	{object}	utils.StandardResponse	"Attachment not found"
+//	@Failure		409	{object}	utils.StandardResponse	"Attachment is still being processed"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Router			/attachments/{id}/download [get]
+func (h *AttachmentHandler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ReadIDParam(r, "id")
+	if err != nil {
+		utils.HandleValidationError(w, errors.New("invalid input format"))
+		return
+	}
+
+	q := r.URL.Query()
+	download, err := h.mediaService.Open(r.Context(), id, q.Get("variant"), q.Get("expires"), q.Get("signature"))
+	if err != nil {
+		switch {
+		case errors.Is(err, blob.ErrInvalidSignature), errors.Is(err, blob.ErrURLExpired):
+			utils.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, apperrors.ErrAttachmentNotFound):
+			utils.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, apperrors.ErrAttachmentNotReady):
+			utils.WriteErrorResponse(w, http.StatusConflict, err.Error())
+		default:
+			utils.HandleInternalError(w, err)
+		}
+		return
+	}
+	defer download.Body.Close()
+
+	w.Header().Set("Content-Type", download.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(download.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": download.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, download.Body); err != nil {
+		h.logger.Warnw("Failed to stream attachment", "attachment_id", id, "error", err)
+	}
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/service"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+)
+
+type BlockHandler struct {
+	blockService *service.BlockService
+	userService  *service.UserService
+}
+
+func NewBlockHandler(blockService *service.BlockService, userService *service.UserService) *BlockHandler {
+	return &BlockHandler{
+		blockService: blockService,
+		userService:  userService,
+	}
+}
+
+// Block godoc
+//
+//	@Summary		Block a user
+//	@Description	Block a user. Blocking removes follows in both directions and hides each user's posts, comments and mentions from the other.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int						true	"User ID to block"
+//	@Success		200	{object}	utils.StandardResponse	"User blocked successfully"
+//	@Failure		400	{object}	utils.StandardResponse	"Cannot block yourself"
+//	@Failure		404	{object}	utils.StandardResponse	"User not found"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/users/{id}/block [post]
+func (h *BlockHandler) Block(w http.ResponseWriter, r *http.Request) {
+	h.apply(w, r, h.blockService.Block, "User blocked successfully")
+}
+
+// Unblock godoc
+//
+//	@Summary		Unblock a user
+//	@Description	Remove a block. Follows removed by the block are not restored.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int						true	"User ID to unblock"
+//	@Success		200	{object}	utils.StandardResponse	"User unblocked successfully"
+//	@Failure		404	{object}	utils.StandardResponse	"User is not blocked"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/users/{id}/block [delete]
+func (h *BlockHandler) Unblock(w http.ResponseWriter, r *http.Request) {
+	h.apply(w, r, h.blockService.Unblock, "User unblocked successfully")
+}
+
+// Mute godoc
+//
+//	@Summary		Mute a user
+//	@Description	Hide the posts and reposts of a user from your feed without unfollowing them
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int						true	"User ID to mute"
+//	@Success		200	{object}	utils.StandardResponse	"User muted successfully"
+//	@Failure		400	{object}	utils.StandardResponse	"Cannot mute yourself"
+//	@Failure		404	{object}	utils.StandardResponse	"User not found"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/users/{id}/mute [post]
+func (h *BlockHandler) Mute(w http.ResponseWriter, r *http.Request) {
+	h.apply(w, r, h.blockService.Mute, "User muted successfully")
+}
+
+// Unmute godoc
+//
+//	@Summary		Unmute a user
+//	@Description	Show the posts and reposts of a muted user in your feed again
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int						true	"User ID to unmute"
+//	@Success		200	{object}	utils.StandardResponse	"User unmuted successfully"
+//	@Failure		404	{object}	utils.StandardResponse	"User is not muted"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/users/{id}/mute [delete]
+func (h *BlockHandler) Unmute(w http.ResponseWriter, r *http.Request) {
+	h.apply(w, r, h.blockService.Unmute, "User unmuted successfully")
+}
+
+func (h *BlockHandler) apply(w http.ResponseWriter, r *http.Request, action func(context.Context, int64) error, message string) {
+	target, ok := h.userService.GetUserFromContext(r.Context())
+	if !ok {
+		utils.WriteErrorResponse(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx := utils.SetUserID(r.Context(), int64(688))
+
+	if err := action(ctx, target.ID); err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrSelfRelation):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, apperrors.ErrUserNotFound),
+			errors.Is(err, apperrors.ErrBlockNotFound),
+			errors.Is(err, apperrors.ErrMuteNotFound):
+			utils.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, apperrors.ErrUserIDNotFound):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+		default:
+			utils.HandleInternalError(w, err)
+		}
+		return
+	}
+
+	data := map[string]interface{}{
+		"message": message,
+	}
+	utils.WriteSuccessResponse(w, http.StatusOK, data)
+}
+
+// GetBlocked godoc
+//
+//	@Summary		List blocked users
+//	@Description	Retrieve the users blocked by the current user, most recent first
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			cursor	query		string					false	"Cursor returned by the previous page"
+//	@Param			limit	query		int						false	"Items per page (default: 20, max: 50)"
+//	@Success		200		{object}	models.UserListResponse	"Blocked users retrieved successfully"
+//	@Failure		400		{object}	utils.StandardResponse	"Invalid cursor"
+//	@Failure		401		{object}	utils.StandardResponse	"Unauthorized"
+//	@Failure		500		{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/users/me/blocks [get]
+func (h *BlockHandler) GetBlocked(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.blockService.GetBlocked)
+}
+
+// GetMuted godoc
+//
+//	@Summary		List muted users
+//	@Description	Retrieve the users muted by the current user, most recent first
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			cursor	query		string					false	"Cursor returned by the previous page"
+//	@Param			limit	query		int						false	"Items per page (default: 20, max: 50)"
+//	@Success		200		{object}	models.UserListResponse	"Muted users retrieved successfully"
+//	@Failure		400		{object}	utils.StandardResponse	"Invalid cursor"
+//	@Failure		401		{object}	utils.StandardResponse	"Unauthorized"
+//	@Failure		500		{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/users/me/mutes [get]
+func (h *BlockHandler) GetMuted(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.blockService.GetMuted)
+}
+
+func (h *BlockHandler) list(w http.ResponseWriter, r *http.Request, fetch func(context.Context, models.CursorRequest) (*models.UserListResponse, error)) {
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx := utils.SetUserID(r.Context(), int64(688))
+
+	res, err := fetch(ctx, utils.ReadCursorRequest(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidCursor):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, apperrors.ErrUserIDNotFound):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+		default:
+			utils.HandleInternalError(w, err)
+		}
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, res)
+}
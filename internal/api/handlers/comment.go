@@ -62,6 +62,8 @@ func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
 			utils.WriteErrorResponse(w, http.StatusNotFound, err.Error())
 		case errors.Is(err, apperrors.ErrUserIDNotFound):
 			utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+		case errors.Is(err, apperrors.ErrAttachmentNotFound):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
 		default:
 			utils.HandleInternalError(w, err)
 		}
@@ -98,7 +100,12 @@ func (h *CommentHandler) GetCommentsByPostID(w http.ResponseWriter, r *http.Requ
 
 	comments, err := h.commentService.GetCommentsByPostID(ctx, post.ID)
 	if err != nil {
-		utils.HandleInternalError(w, err)
+		switch {
+		case errors.Is(err, apperrors.ErrPostNotFound):
+			utils.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		default:
+			utils.HandleInternalError(w, err)
+		}
 		return
 	}
 
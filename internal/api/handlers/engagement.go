@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/LikhithMar14/gopher-chat/internal/service"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+)
+
+type EngagementHandler struct {
+	engagementService *service.EngagementService
+	postService       *service.PostService
+}
+
+func NewEngagementHandler(engagementService *service.EngagementService, postService *service.PostService) *EngagementHandler {
+	return &EngagementHandler{
+		engagementService: engagementService,
+		postService:       postService,
+	}
+}
+
+// ToggleLike godoc
+//
+//	@Summary		Like or unlike a post
+//	@Description	Toggle the current user's like on a post and return the updated like count
+//	@Tags			posts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int						true	"Post ID"
+//	@Success		200	{object}	models.LikeResponse		"Like toggled successfully"
+//	@Failure		401	{object}	utils.StandardResponse	"Unauthorized"
+//	@Failure		404	{object}	utils.StandardResponse	"Post not found"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/posts/{id}/like [post]
+func (h *EngagementHandler) ToggleLike(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	post, ok := h.postService.GetPostFromContext(ctx)
+	if !ok {
+		utils.WriteErrorResponse(w, http.StatusNotFound, "Post not found")
+		return
+	}
+
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx = utils.SetUserID(ctx, int64(688))
+
+	res, err := h.engagementService.ToggleLike(ctx, post)
+	if err != nil {
+		h.handleEngagementError(w, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, res)
+}
+
+// ToggleBookmark godoc
+//
+//	@Summary		Bookmark or un-bookmark a post
+//	@Description	Toggle a private bookmark on a post for the current user
+//	@Tags			posts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int						true	"Post ID"
+//	@Success		200	{object}	models.BookmarkResponse	"Bookmark toggled successfully"
+//	@Failure		401	{object}	utils.StandardResponse	"Unauthorized"
+//	@Failure		404	{object}	utils.StandardResponse	"Post not found"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/posts/{id}/bookmark [post]
+func (h *EngagementHandler) ToggleBookmark(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	post, ok := h.postService.GetPostFromContext(ctx)
+	if !ok {
+		utils.WriteErrorResponse(w, http.StatusNotFound, "Post not found")
+		return
+	}
+
+	ctx = utils.SetUserID(ctx, int64(688))
+
+	res, err := h.engagementService.ToggleBookmark(ctx, post)
+	if err != nil {
+		h.handleEngagementError(w, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, res)
+}
+
+// GetBookmarks godoc
+//
+//	@Summary		List bookmarked posts
+//	@Description	Retrieve the current user's bookmarked posts, newest bookmark first
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			cursor	query		string						false	"Cursor returned by the previous page"
+//	@Param			limit	query		int							false	"Items per page (default: 20, max: 50)"
+//	@Success		200		{object}	models.BookmarksResponse	"Bookmarks retrieved successfully"
+//	@Failure		400		{object}	utils.StandardResponse		"Invalid cursor"
+//	@Failure		401		{object}	utils.StandardResponse		"Unauthorized"
+//	@Failure		500		{object}	utils.StandardResponse		"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/users/me/bookmarks [get]
+func (h *EngagementHandler) GetBookmarks(w http.ResponseWriter, r *http.Request) {
+	ctx := utils.SetUserID(r.Context(), int64(688))
+
+	res, err := h.engagementService.GetBookmarks(ctx, utils.ReadCursorRequest(r))
+	if err != nil {
+		h.handleEngagementError(w, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, res)
+}
+
+func (h *EngagementHandler) handleEngagementError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperrors.ErrPostNotFound):
+		utils.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, apperrors.ErrUserIDNotFound):
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, apperrors.ErrInvalidCursor):
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+	default:
+		utils.HandleInternalError(w, err)
+	}
+}
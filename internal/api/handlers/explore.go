@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/service"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+)
+
+type ExploreHandler struct {
+	exploreService *service.ExploreService
+}
+
+func NewExploreHandler(exploreService *service.ExploreService) *ExploreHandler {
+	return &ExploreHandler{
+		exploreService: exploreService,
+	}
+}
+
+// GetExplore godoc
+//
+//	@Summary		Explore trending posts and tags
+//	@Description	Retrieve the posts and tags trending over a sliding window, as last aggregated in the background
+//	@Tags			explore
+//	@Accept			json
+//	@Produce		json
+//	@Param			window	query		string					false	"Time window (default: 24h)"	Enums(24h, 7d)
+//	@Param			limit	query		int						false	"Number of posts and tags (default: 20, max: 50)"
+//	@Success		200		{object}	models.ExploreResponse	"Trends retrieved successfully"
+//	@Failure		400		{object}	utils.StandardResponse	"Invalid window"
+//	@Failure		500		{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/explore [get]
+func (h *ExploreHandler) GetExplore(w http.ResponseWriter, r *http.Request) {
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx := utils.SetUserID(r.Context(), int64(688))
+
+	window := models.TrendWindow(r.URL.Query().Get("window"))
+	res, err := h.exploreService.GetExplore(ctx, window, utils.ReadCursorRequest(r).Limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidTrendWindow):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		default:
+			utils.HandleInternalError(w, err)
+		}
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, res)
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/LikhithMar14/gopher-chat/internal/service"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"github.com/LikhithMar14/gopher-chat/pkg/activitypub"
+	"go.uber.org/zap"
+)
+
+// maxActivityBytes caps the size of activities delivered to inboxes.
+const maxActivityBytes = 1 << 20
+
+type FederationHandler struct {
+	federationService *service.FederationService
+	userService       *service.UserService
+	logger            *zap.SugaredLogger
+}
+
+func NewFederationHandler(federationService *service.FederationService, userService *service.UserService, logger *zap.SugaredLogger) *FederationHandler {
+	return &FederationHandler{
+		federationService: federationService,
+		userService:       userService,
+		logger:            logger,
+	}
+}
+
+// WebFinger godoc
+//
+//	@Summary		Resolve an account
+//	@Description	Resolve an acct: resource of a local account to its ActivityPub actor (RFC 7033)
+//	@Tags			federation
+//	@Produce		json
+//	@Param			resource	query		string					true	"Account, as acct:username@host"
+//	@Success		200			{object}	activitypub.JRD
+//	@Failure		400			{object}	utils.StandardResponse	"Invalid resource"
+//	@Failure		404			{object}	utils.StandardResponse	"User not found"
+//	@Failure		500			{object}	utils.StandardResponse	"Internal server error"
+//	@Router			/.well-known/webfinger [get]
+func (h *FederationHandler) WebFinger(w http.ResponseWriter, r *http.Request) {
+	jrd, err := h.federationService.WebFinger(r.Context(), r.URL.Query().Get("resource"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	h.writeDocument(w, activitypub.WebFingerContentType, jrd)
+}
+
+// GetActor godoc
+//
+//	@Summary		ActivityPub actor
+//	@Description	Retrieve the ActivityPub actor of a local account, with the public key that signs its activities
+//	@Tags			federation
+//	@Produce		json
+//	@Param			id	path		int	true	"User ID"
+//	@Success		200	{object}	activitypub.Actor
+//	@Failure		404	{object}	utils.StandardResponse	"User not found"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Router			/ap/users/{id} [get]
+func (h *FederationHandler) GetActor(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.userService.GetUserFromContext(r.Context())
+	if !ok {
+		utils.WriteErrorResponse(w, http.StatusNotFound, apperrors.ErrUserNotFound.Error())
+		return
+	}
+
+	actor, err := h.federationService.GetActor(r.Context(), user)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	h.writeDocument(w, activitypub.ContentType, actor)
+}
+
+// GetOutbox godoc
+//
+//	@Summary		ActivityPub outbox
+//	@Description	Retrieve the latest public posts of a local account as Create activities
+//	@Tags			federation
+//	@Produce		json
+//	@Param			id	path		int	true	"User ID"
+//	@Success		200	{object}	activitypub.OrderedCollection
+//	@Failure		404	{object}	utils.StandardResponse	"User not found"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Router			/ap/users/{id}/outbox [get]
+func (h *FederationHandler) GetOutbox(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.userService.GetUserFromContext(r.Context())
+	if !ok {
+		utils.WriteErrorResponse(w, http.StatusNotFound, apperrors.ErrUserNotFound.Error())
+		return
+	}
+
+	outbox, err := h.federationService.GetOutbox(r.Context(), user)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	h.writeDocument(w, activitypub.ContentType, outbox)
+}
+
+// GetFollowers godoc
+//
+//	@Summary		ActivityPub followers
+//	@Description	Retrieve the size of the followers collection of a local account
+//	@Tags			federation
+//	@Produce		json
+//	@Param			id	path		int	true	"User ID"
+//	@Success		200	{object}	activitypub.OrderedCollection
+//	@Failure		404	{object}	utils.StandardResponse	"User not found"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Router			/ap/users/{id}/followers [get]
+func (h *FederationHandler) GetFollowers(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.userService.GetUserFromContext(r.Context())
+	if !ok {
+		utils.WriteErrorResponse(w, http.StatusNotFound, apperrors.ErrUserNotFound.Error())
+		return
+	}
+
+	followers, err := h.federationService.GetFollowers(r.Context(), user)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	h.writeDocument(w, activitypub.ContentType, followers)
+}
+
+// GetNote godoc
+//
+//	@Summary		ActivityPub note
+//	@Description	Retrieve a post visible to anonymous viewers as an ActivityPub Note
+//	@Tags			federation
+//	@Produce		json
+//	@Param			id	path		int	true	"Post ID"
+//	@Success		200	{object}	activitypub.Note
+//	@Failure		400	{object}	utils.StandardResponse	"Invalid post ID"
+//	@Failure		404	{object}	utils.StandardResponse	"Post not found"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Router			/ap/posts/{id} [get]
+func (h *FederationHandler) GetNote(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ReadIDParam(r, "id")
+	if err != nil {
+		utils.HandleValidationError(w, errors.New("invalid input format"))
+		return
+	}
+
+	note, err := h.federationService.GetNote(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	h.writeDocument(w, activitypub.ContentType, note)
+}
+
+// Inbox godoc
+//
+//	@Summary		ActivityPub inbox
+//	@Description	Receive an activity from another server. The request must carry an HTTP signature of the actor of the activity. Serves both the inboxes of local accounts and the shared inbox.
+//	@Tags			federation
+//	@Accept			json
+//	@Param			id	path	int	false	"User ID"
+//	@Success		202	"Activity accepted"
+//	@Failure		400	{object}	utils.StandardResponse	"Invalid activity"
+//	@Failure		401	{object}	utils.StandardResponse	"Invalid signature"
+//	@Failure		413	{object}	utils.StandardResponse	"Activity too large"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Router			/ap/users/{id}/inbox [post]
+//	@Router			/ap/inbox [post]
+func (h *FederationHandler) Inbox(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxActivityBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			utils.WriteErrorResponse(w, http.StatusRequestEntityTooLarge, "activity too large")
+			return
+		}
+		utils.WriteErrorResponse(w, http.StatusBadRequest, apperrors.ErrInvalidActivity.Error())
+		return
+	}
+
+	if err := h.federationService.HandleInbox(r.Context(), r, body); err != nil {
+		h.handleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// LookupAccount godoc
+//
+//	@Summary		Look up an account
+//	@Description	Resolve an account such as alice@example.com, on this or another server, to a user that can be followed
+//	@Tags			users
+//	@Produce		json
+//	@Param			acct	query		string	true	"Account, as username@host"
+//	@Success		200		{object}	utils.StandardResponse
+//	@Failure		400		{object}	utils.StandardResponse	"Invalid account"
+//	@Failure		404		{object}	utils.StandardResponse	"Account not found"
+//	@Failure		500		{object}	utils.StandardResponse	"Internal server error"
+//	@Router			/users/lookup [get]
+func (h *FederationHandler) LookupAccount(w http.ResponseWriter, r *http.Request) {
+	user, err := h.federationService.LookupAccount(r.Context(), r.URL.Query().Get("acct"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]interface{}{
+		"user": user,
+	})
+}
+
+func (h *FederationHandler) handleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperrors.ErrInvalidInput), errors.Is(err, apperrors.ErrInvalidActivity):
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, activitypub.ErrMissingSignature),
+		errors.Is(err, activitypub.ErrInvalidSignature),
+		errors.Is(err, apperrors.ErrActorMismatch):
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+	case errors.Is(err, apperrors.ErrUserNotFound),
+		errors.Is(err, apperrors.ErrPostNotFound),
+		errors.Is(err, apperrors.ErrRemoteAccountNotFound):
+		utils.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+	default:
+		h.logger.Errorw("Federation request failed", "error", err)
+		utils.HandleInternalError(w, err)
+	}
+}
+
+// writeDocument writes an ActivityPub or WebFinger document as is, without
+// the response envelope of the API, since other servers expect it bare.
+func (h *FederationHandler) writeDocument(w http.ResponseWriter, contentType string, doc any) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "max-age=60")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(doc); err != nil {
+		h.logger.Warnw("Failed to write federation document", "error", err)
+	}
+}
@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/LikhithMar14/gopher-chat/internal/models"
 	"github.com/LikhithMar14/gopher-chat/internal/service"
 	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
 )
 
 type FeedHandler struct {
@@ -26,23 +28,25 @@ func NewFeedHandler(userService *service.UserService, postService *service.PostS
 // GetFeed godoc
 //
 //	@Summary		Get user feed
-//	@Description	Retrieve a paginated feed of posts from followed users
+//	@Description	Retrieve a paginated feed of your posts and those of followed users, newest first or ranked by score
 //	@Tags			feed
 //	@Accept			json
 //	@Produce		json
 //	@Param			page		query		int						false	"Page number (default: 1)"
 //	@Param			page_size	query		int						false	"Items per page (default: 10, max: 50)"
+//	@Param			mode		query		string					false	"Feed order (default: chronological)"	Enums(chronological, ranked)
+//	@Param			since_id	query		string					false	"Only return items newer than this item ID (chronological mode only)"
+//	@Param			max_id		query		string					false	"Only return items older than this item ID (chronological mode only)"
+//	@Param			cursor		query		string					false	"Cursor of the next page, from next_cursor (ranked mode only)"
 //	@Success		200			{object}	models.FeedResponse		"Feed retrieved successfully"
+//	@Failure		400			{object}	utils.StandardResponse	"Invalid feed mode, item ID or cursor"
+//	@Failure		401			{object}	utils.StandardResponse	"Unauthorized"
 //	@Failure		500			{object}	utils.StandardResponse	"Internal server error"
 //	@Security		ApiKeyAuth
-//	@Router			/users/me/feed [get]
+//	@Router			/users/feed [get]
 func (h *FeedHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	// if _, ok := h.userService.GetUserFromContext(ctx); !ok {
-	// 	utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
-	// 	return
-	// }
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx := utils.SetUserID(r.Context(), int64(688))
 
 	pageStr := r.URL.Query().Get("page")
 	pageSizeStr := r.URL.Query().Get("page_size")
@@ -62,16 +66,75 @@ func (h *FeedHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	mode := models.FeedMode(r.URL.Query().Get("mode"))
+	switch mode {
+	case "", models.FeedModeChronological, models.FeedModeRanked:
+	default:
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid feed mode")
+		return
+	}
+
 	feedRequest := models.FeedRequest{
 		Page:     page,
 		PageSize: pageSize,
+		Mode:     mode,
+		SinceID:  r.URL.Query().Get("since_id"),
+		MaxID:    r.URL.Query().Get("max_id"),
+		Cursor:   r.URL.Query().Get("cursor"),
 	}
 
 	feedResponse, err := h.feedService.GetUserFeed(ctx, feedRequest)
 	if err != nil {
-		utils.WriteErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve feed")
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidCursor):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid feed item ID or cursor")
+		case errors.Is(err, apperrors.ErrUserIDNotFound):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+		default:
+			utils.WriteErrorResponse(w, http.StatusInternalServerError, "Failed to retrieve feed")
+		}
 		return
 	}
 
 	utils.WriteSuccessResponse(w, http.StatusOK, feedResponse)
 }
+
+// CountNewItems godoc
+//
+//	@Summary		Count new feed items
+//	@Description	Count the items of the chronological feed newer than the given item, for pull-to-refresh
+//	@Tags			feed
+//	@Accept			json
+//	@Produce		json
+//	@Param			since_id	query		string						true	"ID of the newest item the client has"
+//	@Success		200			{object}	models.FeedNewItemsResponse	"New items counted successfully"
+//	@Failure		400			{object}	utils.StandardResponse		"Invalid feed item ID"
+//	@Failure		401			{object}	utils.StandardResponse		"Unauthorized"
+//	@Failure		500			{object}	utils.StandardResponse		"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/users/feed/new [get]
+func (h *FeedHandler) CountNewItems(w http.ResponseWriter, r *http.Request) {
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx := utils.SetUserID(r.Context(), int64(688))
+
+	sinceID := r.URL.Query().Get("since_id")
+	if sinceID == "" {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "since_id is required")
+		return
+	}
+
+	res, err := h.feedService.CountNewItems(ctx, sinceID)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidCursor):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid feed item ID")
+		case errors.Is(err, apperrors.ErrUserIDNotFound):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+		default:
+			utils.HandleInternalError(w, err)
+		}
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, res)
+}
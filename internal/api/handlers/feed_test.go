@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/service"
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+)
+
+// feedPosts serves a home feed of one post of the viewer. The other methods
+// of the embedded repository are not used by the feed.
+type feedPosts struct {
+	store.PostRepository
+	viewerID int64
+}
+
+func (p *feedPosts) items(userID int64) []*models.FeedItem {
+	p.viewerID = userID
+	return []*models.FeedItem{{
+		Post:   &models.Post{ID: 7, UserID: userID, Content: "hello", CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+		Author: &models.PublicUser{ID: userID, Username: "gopher"},
+	}}
+}
+
+func (p *feedPosts) GetFeed(_ context.Context, userID int64, _, _ *models.FeedPosition, _, _ int) ([]*models.FeedItem, int64, error) {
+	return p.items(userID), 1, nil
+}
+
+func (p *feedPosts) GetRankedFeed(_ context.Context, userID int64, _ models.FeedRanking, _ time.Time, _ *models.RankedFeedPosition, _ int) ([]*models.FeedItem, int64, error) {
+	return p.items(userID), 1, nil
+}
+
+type feedMentions struct{ store.MentionRepository }
+
+func (feedMentions) GetForPosts(context.Context, []int64) (map[int64][]models.Mention, error) {
+	return nil, nil
+}
+
+type feedAttachments struct{ store.AttachmentRepository }
+
+func (feedAttachments) GetForPosts(context.Context, []int64) (map[int64][]*models.Attachment, error) {
+	return nil, nil
+}
+
+type feedLinkPreviews struct{ store.LinkPreviewRepository }
+
+func (feedLinkPreviews) GetForPosts(context.Context, []int64) (map[int64][]models.LinkPreview, error) {
+	return nil, nil
+}
+
+type feedPolls struct{ store.PollRepository }
+
+func (feedPolls) GetForPosts(context.Context, []int64, int64) (map[int64]*models.Poll, error) {
+	return nil, nil
+}
+
+func TestGetFeed(t *testing.T) {
+	for name, mode := range map[string]string{"default": "", "chronological": "chronological", "ranked": "ranked"} {
+		t.Run(name, func(t *testing.T) {
+			posts := &feedPosts{}
+			st := store.Storage{
+				Post:        posts,
+				Mention:     feedMentions{},
+				Attachment:  feedAttachments{},
+				LinkPreview: feedLinkPreviews{},
+				Poll:        feedPolls{},
+			}
+			media := service.NewMediaService(st, nil, nil, nil, 0)
+			h := NewFeedHandler(nil, nil, service.NewFeedService(st, media, models.FeedRanking{Window: time.Hour}))
+
+			rec := httptest.NewRecorder()
+			h.GetFeed(rec, httptest.NewRequest(http.MethodGet, "/v1/users/feed?mode="+mode, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+			}
+			var res struct {
+				Data models.FeedResponse `json:"data"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+				t.Fatal(err)
+			}
+			if len(res.Data.Items) != 1 || res.Data.Items[0].Post.ID != 7 || res.Data.Items[0].ID == "" {
+				t.Errorf("items = %+v, want post 7 with a feed id", res.Data.Items)
+			}
+			if posts.viewerID == 0 {
+				t.Error("feed was read without a viewer")
+			}
+		})
+	}
+}
+
+func TestGetFeedInvalidID(t *testing.T) {
+	h := NewFeedHandler(nil, nil, service.NewFeedService(store.Storage{Post: &feedPosts{}}, nil, models.FeedRanking{}))
+
+	rec := httptest.NewRecorder()
+	h.GetFeed(rec, httptest.NewRequest(http.MethodGet, "/v1/users/feed?since_id=nope", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400: %s", rec.Code, rec.Body)
+	}
+}
@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
+	"github.com/LikhithMar14/gopher-chat/internal/models"
 	"github.com/LikhithMar14/gopher-chat/internal/service"
 	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
 	"go.uber.org/zap"
 )
 
@@ -26,12 +29,14 @@ func NewFollowHandler(followService *service.FollowService, userService *service
 // FollowUser godoc
 //
 //	@Summary		Follow a user
-//	@Description	Follow another user to see their posts in your feed
+//	@Description	Follow another user to see their posts in your feed. Following a private account sends a follow request instead. Following an already followed user succeeds.
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
 //	@Param			id	path		int						true	"User ID to follow"
-//	@Success		200	{object}	utils.StandardResponse	"User followed successfully"
+//	@Success		200	{object}	utils.StandardResponse	"User followed or follow requested"
+//	@Failure		400	{object}	utils.StandardResponse	"Cannot follow yourself"
+//	@Failure		403	{object}	utils.StandardResponse	"User is blocked"
 //	@Failure		404	{object}	utils.StandardResponse	"User not found"
 //	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
 //	@Security		ApiKeyAuth
@@ -44,18 +49,34 @@ func (h *FollowHandler) FollowUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userID := user.ID
-	currentUserID := 667
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx = utils.SetUserID(ctx, int64(688))
 
-	err := h.followService.FollowUser(ctx, int64(currentUserID), int64(userID))
+	status, err := h.followService.FollowUser(ctx, user.ID)
 	if err != nil {
-		h.logger.Error("Error in follow handler", zap.Error(err))
-		utils.HandleInternalError(w, err)
+		switch {
+		case errors.Is(err, apperrors.ErrSelfFollow):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, apperrors.ErrUserBlocked):
+			utils.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, apperrors.ErrUserNotFound):
+			utils.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, apperrors.ErrUserIDNotFound):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+		default:
+			h.logger.Error("Error in follow handler", zap.Error(err))
+			utils.HandleInternalError(w, err)
+		}
 		return
 	}
 
+	message := "User followed successfully"
+	if status == models.FollowStatusRequested {
+		message = "Follow request sent"
+	}
 	data := map[string]interface{}{
-		"message": "User followed successfully",
+		"message": message,
+		"status":  status,
 	}
 	utils.WriteSuccessResponse(w, http.StatusOK, data)
 }
@@ -63,7 +84,7 @@ func (h *FollowHandler) FollowUser(w http.ResponseWriter, r *http.Request) {
 // UnfollowUser godoc
 //
 //	@Summary		Unfollow a user
-//	@Description	Unfollow a user to stop seeing their posts in your feed
+//	@Description	Unfollow a user to stop seeing their posts in your feed, or withdraw a pending follow request. Unfollowing a user you do not follow succeeds.
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
@@ -80,13 +101,18 @@ func (h *FollowHandler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
 		utils.HandleInternalError(w, errors.New("user not found"))
 		return
 	}
-	userID := user.ID
-	currentUserID := 667
 
-	err := h.followService.UnfollowUser(ctx, int64(currentUserID), int64(userID))
-	if err != nil {
-		h.logger.Error("Error in unfollow handler", zap.Error(err))
-		utils.HandleInternalError(w, err)
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx = utils.SetUserID(ctx, int64(688))
+
+	if err := h.followService.UnfollowUser(ctx, user.ID); err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrUserIDNotFound):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+		default:
+			h.logger.Error("Error in unfollow handler", zap.Error(err))
+			utils.HandleInternalError(w, err)
+		}
 		return
 	}
 
@@ -95,3 +121,174 @@ func (h *FollowHandler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
 	}
 	utils.WriteSuccessResponse(w, http.StatusOK, data)
 }
+
+// GetFollowers godoc
+//
+//	@Summary		List followers
+//	@Description	Retrieve the users following a user, most recent follow first, with follow flags relative to the current user
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int							true	"User ID"
+//	@Param			cursor	query		string						false	"Cursor returned by the previous page"
+//	@Param			limit	query		int							false	"Items per page (default: 20, max: 50)"
+//	@Success		200		{object}	models.FollowListResponse	"Followers retrieved successfully"
+//	@Failure		400		{object}	utils.StandardResponse		"Invalid cursor"
+//	@Failure		403		{object}	utils.StandardResponse		"Account is private"
+//	@Failure		404		{object}	utils.StandardResponse		"User not found"
+//	@Failure		500		{object}	utils.StandardResponse		"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/users/{id}/followers [get]
+func (h *FollowHandler) GetFollowers(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.followService.GetFollowers)
+}
+
+// GetFollowing godoc
+//
+//	@Summary		List followed users
+//	@Description	Retrieve the users a user follows, most recent follow first, with follow flags relative to the current user
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int							true	"User ID"
+//	@Param			cursor	query		string						false	"Cursor returned by the previous page"
+//	@Param			limit	query		int							false	"Items per page (default: 20, max: 50)"
+//	@Success		200		{object}	models.FollowListResponse	"Followed users retrieved successfully"
+//	@Failure		400		{object}	utils.StandardResponse		"Invalid cursor"
+//	@Failure		403		{object}	utils.StandardResponse		"Account is private"
+//	@Failure		404		{object}	utils.StandardResponse		"User not found"
+//	@Failure		500		{object}	utils.StandardResponse		"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/users/{id}/following [get]
+func (h *FollowHandler) GetFollowing(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.followService.GetFollowing)
+}
+
+func (h *FollowHandler) list(w http.ResponseWriter, r *http.Request, fetch func(context.Context, *models.User, models.CursorRequest) (*models.FollowListResponse, error)) {
+	user, ok := h.userService.GetUserFromContext(r.Context())
+	if !ok {
+		utils.WriteErrorResponse(w, http.StatusNotFound, "User not found")
+		return
+	}
+
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx := utils.SetUserID(r.Context(), int64(688))
+
+	res, err := fetch(ctx, user, utils.ReadCursorRequest(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidCursor):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		case utils.IsValidationError(err):
+			utils.HandleValidationError(w, err)
+		case errors.Is(err, apperrors.ErrPrivateAccount), errors.Is(err, apperrors.ErrUserBlocked):
+			utils.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		default:
+			utils.HandleInternalError(w, err)
+		}
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, res)
+}
+
+// GetFollowRequests godoc
+//
+//	@Summary		List follow requests
+//	@Description	Retrieve the pending requests to follow the current user, newest first
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			cursor	query		string							false	"Cursor returned by the previous page"
+//	@Param			limit	query		int								false	"Items per page (default: 20, max: 50)"
+//	@Success		200		{object}	models.FollowRequestsResponse	"Follow requests retrieved successfully"
+//	@Failure		400		{object}	utils.StandardResponse			"Invalid cursor"
+//	@Failure		401		{object}	utils.StandardResponse			"Unauthorized"
+//	@Failure		500		{object}	utils.StandardResponse			"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/users/me/follow-requests [get]
+func (h *FollowHandler) GetFollowRequests(w http.ResponseWriter, r *http.Request) {
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx := utils.SetUserID(r.Context(), int64(688))
+
+	res, err := h.followService.GetFollowRequests(ctx, utils.ReadCursorRequest(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidCursor):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		case utils.IsValidationError(err):
+			utils.HandleValidationError(w, err)
+		case errors.Is(err, apperrors.ErrUserIDNotFound):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+		default:
+			utils.HandleInternalError(w, err)
+		}
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, res)
+}
+
+// ApproveFollowRequest godoc
+//
+//	@Summary		Approve a follow request
+//	@Description	Let the requesting user follow the current user
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int						true	"Requesting user ID"
+//	@Success		200	{object}	utils.StandardResponse	"Follow request approved"
+//	@Failure		400	{object}	utils.StandardResponse	"Invalid user ID"
+//	@Failure		404	{object}	utils.StandardResponse	"Follow request not found"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/users/me/follow-requests/{id}/approve [post]
+func (h *FollowHandler) ApproveFollowRequest(w http.ResponseWriter, r *http.Request) {
+	h.resolveFollowRequest(w, r, h.followService.ApproveFollowRequest, "Follow request approved")
+}
+
+// DenyFollowRequest godoc
+//
+//	@Summary		Deny a follow request
+//	@Description	Reject the request of a user to follow the current user
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int						true	"Requesting user ID"
+//	@Success		200	{object}	utils.StandardResponse	"Follow request denied"
+//	@Failure		400	{object}	utils.StandardResponse	"Invalid user ID"
+//	@Failure		404	{object}	utils.StandardResponse	"Follow request not found"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/users/me/follow-requests/{id}/deny [post]
+func (h *FollowHandler) DenyFollowRequest(w http.ResponseWriter, r *http.Request) {
+	h.resolveFollowRequest(w, r, h.followService.DenyFollowRequest, "Follow request denied")
+}
+
+func (h *FollowHandler) resolveFollowRequest(w http.ResponseWriter, r *http.Request, resolve func(context.Context, int64) error, message string) {
+	requesterID, err := utils.ReadIDParam(r, "id")
+	if err != nil {
+		utils.HandleValidationError(w, err)
+		return
+	}
+
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx := utils.SetUserID(r.Context(), int64(688))
+
+	if err := resolve(ctx, requesterID); err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrFollowRequestNotFound):
+			utils.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, apperrors.ErrUserIDNotFound):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+		default:
+			utils.HandleInternalError(w, err)
+		}
+		return
+	}
+
+	data := map[string]interface{}{
+		"message": message,
+	}
+	utils.WriteSuccessResponse(w, http.StatusOK, data)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/LikhithMar14/gopher-chat/internal/service"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+)
+
+type MentionHandler struct {
+	mentionService *service.MentionService
+}
+
+func NewMentionHandler(mentionService *service.MentionService) *MentionHandler {
+	return &MentionHandler{
+		mentionService: mentionService,
+	}
+}
+
+// GetMentions godoc
+//
+//	@Summary		List posts mentioning me
+//	@Description	Retrieve the posts that mention the current user, newest first
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			cursor	query		string					false	"Cursor returned by the previous page"
+//	@Param			limit	query		int						false	"Items per page (default: 20, max: 50)"
+//	@Success		200		{object}	models.MentionsResponse	"Mentions retrieved successfully"
+//	@Failure		400		{object}	utils.StandardResponse	"Invalid cursor"
+//	@Failure		401		{object}	utils.StandardResponse	"Unauthorized"
+//	@Failure		500		{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/users/me/mentions [get]
+func (h *MentionHandler) GetMentions(w http.ResponseWriter, r *http.Request) {
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx := utils.SetUserID(r.Context(), int64(688))
+
+	res, err := h.mentionService.GetMentions(ctx, utils.ReadCursorRequest(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidCursor):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, apperrors.ErrUserIDNotFound):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+		default:
+			utils.HandleInternalError(w, err)
+		}
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, res)
+}
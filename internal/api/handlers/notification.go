@@ -0,0 +1,314 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/service"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	// notificationStreamDuration is how long a notification stream stays
+	// open. It ends within the request timeout of the router; EventSource
+	// clients reconnect on their own and resume from the last event they
+	// received. The write deadline of the server is extended to match.
+	notificationStreamDuration = 50 * time.Second
+	// notificationHeartbeatInterval is how often an idle stream sends a
+	// comment, so proxies don't close it for inactivity.
+	notificationHeartbeatInterval = 15 * time.Second
+)
+
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+	logger              *zap.SugaredLogger
+}
+
+func NewNotificationHandler(notificationService *service.NotificationService, logger *zap.SugaredLogger) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+		logger:              logger,
+	}
+}
+
+// GetNotifications godoc
+//
+//	@Summary		List my notifications
+//	@Description	Retrieve the notifications of the current user, latest first. Likes of the same post and follows are grouped per day ("alice and 4 others liked your post"). Notifications older than 90 days are not listed. Notifications from blocked or muted users are left out.
+//	@Tags			notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			cursor	query		string							false	"Cursor returned by the previous page"
+//	@Param			limit	query		int								false	"Items per page (default: 20, max: 50)"
+//	@Success		200		{object}	models.NotificationsResponse	"Notifications retrieved successfully"
+//	@Failure		400		{object}	utils.StandardResponse			"Invalid cursor"
+//	@Failure		401		{object}	utils.StandardResponse			"Unauthorized"
+//	@Failure		500		{object}	utils.StandardResponse			"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/notifications [get]
+func (h *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx := utils.SetUserID(r.Context(), int64(688))
+
+	res, err := h.notificationService.GetNotifications(ctx, utils.ReadCursorRequest(r))
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidCursor):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, apperrors.ErrUserIDNotFound):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+		default:
+			utils.HandleInternalError(w, err)
+		}
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, res)
+}
+
+// MarkRead godoc
+//
+//	@Summary		Mark a notification as read
+//	@Description	Mark a notification group, identified by the id of its latest notification, as read
+//	@Tags			notifications
+//	@Produce		json
+//	@Param			id	path		int						true	"Notification ID"
+//	@Success		200	{object}	utils.StandardResponse	"Notification marked as read"
+//	@Failure		400	{object}	utils.StandardResponse	"Invalid notification ID"
+//	@Failure		401	{object}	utils.StandardResponse	"Unauthorized"
+//	@Failure		404	{object}	utils.StandardResponse	"Notification not found"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx := utils.SetUserID(r.Context(), int64(688))
+
+	id, err := utils.ReadIDParam(r, "id")
+	if err != nil {
+		utils.HandleValidationError(w, errors.New("invalid input format"))
+		return
+	}
+
+	if err := h.notificationService.MarkRead(ctx, id); err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrNotificationNotFound):
+			utils.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, apperrors.ErrUserIDNotFound):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+		default:
+			utils.HandleInternalError(w, err)
+		}
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]interface{}{
+		"message": "Notification marked as read",
+	})
+}
+
+// MarkAllRead godoc
+//
+//	@Summary		Mark all notifications as read
+//	@Description	Mark every notification of the current user as read
+//	@Tags			notifications
+//	@Produce		json
+//	@Success		200	{object}	utils.StandardResponse	"Notifications marked as read"
+//	@Failure		401	{object}	utils.StandardResponse	"Unauthorized"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/notifications/read [post]
+func (h *NotificationHandler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx := utils.SetUserID(r.Context(), int64(688))
+
+	if err := h.notificationService.MarkAllRead(ctx); err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrUserIDNotFound):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+		default:
+			utils.HandleInternalError(w, err)
+		}
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]interface{}{
+		"message": "Notifications marked as read",
+	})
+}
+
+// GetPreferences godoc
+//
+//	@Summary		Get my notification preferences
+//	@Description	Retrieve how the current user hears about each kind of notification (in_app, email, daily or weekly digest), their timezone and their quiet hours
+//	@Tags			notifications
+//	@Produce		json
+//	@Success		200	{object}	models.NotificationPreferences	"Preferences retrieved successfully"
+//	@Failure		401	{object}	utils.StandardResponse			"Unauthorized"
+//	@Failure		500	{object}	utils.StandardResponse			"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/notifications/preferences [get]
+func (h *NotificationHandler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx := utils.SetUserID(r.Context(), int64(688))
+
+	prefs, err := h.notificationService.GetPreferences(ctx)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrUserIDNotFound):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+		default:
+			utils.HandleInternalError(w, err)
+		}
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, prefs)
+}
+
+// UpdatePreferences godoc
+//
+//	@Summary		Update my notification preferences
+//	@Description	Choose per kind of notification whether to hear about it only in the app, by email right away, or in a daily or weekly digest of unread notifications. No email is sent during the quiet hours, given as "HH:MM" in the user's timezone; send both ends as empty strings to turn them off.
+//	@Tags			notifications
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.UpdateNotificationPreferencesRequest	true	"Preferences to change"
+//	@Success		200		{object}	models.NotificationPreferences				"Preferences updated successfully"
+//	@Failure		400		{object}	utils.StandardResponse						"Validation error"
+//	@Failure		401		{object}	utils.StandardResponse						"Unauthorized"
+//	@Failure		500		{object}	utils.StandardResponse						"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/notifications/preferences [patch]
+func (h *NotificationHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateNotificationPreferencesRequest
+	if err := utils.ReadJSON(w, r, &req); err != nil {
+		utils.HandleValidationError(w, err)
+		return
+	}
+	if err := service.Validate.Struct(req); err != nil {
+		utils.HandleValidationError(w, err)
+		return
+	}
+
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx := utils.SetUserID(r.Context(), int64(688))
+
+	prefs, err := h.notificationService.UpdatePreferences(ctx, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrUserIDNotFound):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+		default:
+			utils.HandleInternalError(w, err)
+		}
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, prefs)
+}
+
+// Stream godoc
+//
+//	@Summary		Stream my notifications
+//	@Description	Push the notifications of the current user as they are created, as server-sent events named "notification" carrying the notification id. Streams end after about a minute; reconnect with the Last-Event-ID header (or the last_event_id query parameter) to receive what was missed in between.
+//	@Tags			notifications
+//	@Produce		text/event-stream
+//	@Param			Last-Event-ID	header		int						false	"Id of the last notification received"
+//	@Param			last_event_id	query		int						false	"Id of the last notification received"
+//	@Success		200				{string}	string					"Event stream"
+//	@Failure		400				{object}	utils.StandardResponse	"Invalid event ID"
+//	@Failure		401				{object}	utils.StandardResponse	"Unauthorized"
+//	@Security		ApiKeyAuth
+//	@Router			/notifications/stream [get]
+func (h *NotificationHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx := utils.SetUserID(r.Context(), int64(688))
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("last_event_id")
+	}
+	var afterID int64
+	if lastID != "" {
+		id, err := strconv.ParseInt(lastID, 10, 64)
+		if err != nil || id < 0 {
+			utils.WriteErrorResponse(w, http.StatusBadRequest, "invalid event ID")
+			return
+		}
+		afterID = id
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notificationStreamDuration)
+	defer cancel()
+
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Now().Add(notificationStreamDuration + 5*time.Second)); err != nil {
+		h.logger.Warnw("Notification stream can't extend the write deadline", "error", err)
+		utils.WriteErrorResponse(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if _, err := fmt.Fprint(w, "retry: 1000\n\n"); err != nil {
+		return
+	}
+	if err := rc.Flush(); err != nil {
+		h.logger.Warnw("Notification stream can't be flushed", "error", err)
+		return
+	}
+
+	// Events and heartbeats are written from different goroutines.
+	var mu sync.Mutex
+	write := func(format string, args ...any) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if _, err := fmt.Fprintf(w, format, args...); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(notificationHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := write(": heartbeat\n\n"); err != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	err := h.notificationService.Stream(ctx, afterID, func(n *models.Notification) error {
+		data, err := json.Marshal(n)
+		if err != nil {
+			return err
+		}
+		return write("id: %d\nevent: notification\ndata: %s\n\n", n.ID, data)
+	})
+	if err != nil && ctx.Err() == nil {
+		h.logger.Warnw("Notification stream failed", "error", err)
+	}
+	cancel()
+	wg.Wait()
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/LikhithMar14/gopher-chat/internal/service"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+)
+
+type OutboxHandler struct {
+	outboxService *service.OutboxService
+}
+
+func NewOutboxHandler(outboxService *service.OutboxService) *OutboxHandler {
+	return &OutboxHandler{outboxService: outboxService}
+}
+
+// GetFailed godoc
+//
+//	@Summary		List failed outbox messages
+//	@Description	Retrieve the emails and webhook events that were given up on after failing to dispatch, latest first, with the error of their last attempt
+//	@Tags			outbox
+//	@Produce		json
+//	@Param			cursor	query		string							false	"Cursor returned by the previous page"
+//	@Param			limit	query		int								false	"Items per page (default: 20, max: 50)"
+//	@Success		200		{object}	models.OutboxMessagesResponse	"Messages retrieved successfully"
+//	@Failure		400		{object}	utils.StandardResponse			"Invalid cursor"
+//	@Failure		401		{object}	utils.StandardResponse			"Unauthorized"
+//	@Failure		500		{object}	utils.StandardResponse			"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/outbox/failed [get]
+func (h *OutboxHandler) GetFailed(w http.ResponseWriter, r *http.Request) {
+	res, err := h.outboxService.GetFailed(r.Context(), utils.ReadCursorRequest(r))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, res)
+}
+
+// Retry godoc
+//
+//	@Summary		Retry a failed outbox message
+//	@Description	Dispatch a message that was given up on again, with its attempts reset. Invitation emails keep their activation link, so retry them before the invitation expires.
+//	@Tags			outbox
+//	@Produce		json
+//	@Param			id	path		int						true	"Outbox message ID"
+//	@Success		202	{object}	models.OutboxMessage	"Retry queued"
+//	@Failure		400	{object}	utils.StandardResponse	"Invalid ID"
+//	@Failure		401	{object}	utils.StandardResponse	"Unauthorized"
+//	@Failure		404	{object}	utils.StandardResponse	"Failed message not found"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/outbox/{id}/retry [post]
+func (h *OutboxHandler) Retry(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ReadIDParam(r, "id")
+	if err != nil {
+		utils.HandleValidationError(w, errors.New("invalid input format"))
+		return
+	}
+
+	message, err := h.outboxService.Retry(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusAccepted, message)
+}
+
+func (h *OutboxHandler) handleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperrors.ErrOutboxMessageNotFound):
+		utils.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, apperrors.ErrInvalidCursor):
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+	default:
+		utils.HandleInternalError(w, err)
+	}
+}
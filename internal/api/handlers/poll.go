@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/service"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+)
+
+type PollHandler struct {
+	pollService *service.PollService
+	postService *service.PostService
+}
+
+func NewPollHandler(pollService *service.PollService, postService *service.PostService) *PollHandler {
+	return &PollHandler{
+		pollService: pollService,
+		postService: postService,
+	}
+}
+
+// Vote godoc
+//
+//	@Summary		Vote on a poll
+//	@Description	Vote on the poll attached to a post. Voting again replaces the earlier vote until the poll closes
+//	@Tags			posts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int						true	"Post ID"
+//	@Param			vote	body		models.VotePollRequest	true	"Chosen options"
+//	@Success		200		{object}	models.Poll				"Vote recorded successfully"
+//	@Failure		400		{object}	utils.StandardResponse	"Validation error"
+//	@Failure		401		{object}	utils.StandardResponse	"Unauthorized"
+//	@Failure		404		{object}	utils.StandardResponse	"Post or poll not found"
+//	@Failure		409		{object}	utils.StandardResponse	"Poll is closed"
+//	@Failure		500		{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/posts/{id}/poll/votes [post]
+func (h *PollHandler) Vote(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	post, ok := h.postService.GetPostFromContext(ctx)
+	if !ok {
+		utils.WriteErrorResponse(w, http.StatusNotFound, "Post not found")
+		return
+	}
+
+	var req models.VotePollRequest
+	if err := utils.ReadJSON(w, r, &req); err != nil {
+		utils.HandleValidationError(w, err)
+		return
+	}
+	if err := service.Validate.Struct(req); err != nil {
+		utils.HandleValidationError(w, err)
+		return
+	}
+
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx = utils.SetUserID(ctx, int64(688))
+
+	poll, err := h.pollService.Vote(ctx, post, req)
+	if err != nil {
+		h.handlePollError(w, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, poll)
+}
+
+// RetractVote godoc
+//
+//	@Summary		Retract a poll vote
+//	@Description	Remove the current user's vote from the poll attached to a post while it is open
+//	@Tags			posts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int						true	"Post ID"
+//	@Success		200	{object}	models.Poll				"Vote retracted successfully"
+//	@Failure		401	{object}	utils.StandardResponse	"Unauthorized"
+//	@Failure		404	{object}	utils.StandardResponse	"Post or poll not found"
+//	@Failure		409	{object}	utils.StandardResponse	"Poll is closed"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/posts/{id}/poll/votes [delete]
+func (h *PollHandler) RetractVote(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	post, ok := h.postService.GetPostFromContext(ctx)
+	if !ok {
+		utils.WriteErrorResponse(w, http.StatusNotFound, "Post not found")
+		return
+	}
+
+	ctx = utils.SetUserID(ctx, int64(688))
+
+	poll, err := h.pollService.RetractVote(ctx, post)
+	if err != nil {
+		h.handlePollError(w, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, poll)
+}
+
+func (h *PollHandler) handlePollError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperrors.ErrPollNotFound):
+		utils.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, apperrors.ErrPollClosed):
+		utils.WriteErrorResponse(w, http.StatusConflict, err.Error())
+	case errors.Is(err, apperrors.ErrPollOptionNotFound), errors.Is(err, apperrors.ErrPollSingleChoice):
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, apperrors.ErrUserIDNotFound):
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+	default:
+		utils.HandleInternalError(w, err)
+	}
+}
@@ -64,7 +64,12 @@ func (h *PostHandler) CreatePost(w http.ResponseWriter, r *http.Request) {
 
 	post, err := h.postService.CreatePost(ctx, req)
 	if err != nil {
-		utils.HandleInternalError(w, err)
+		switch {
+		case errors.Is(err, apperrors.ErrAttachmentNotFound), errors.Is(err, apperrors.ErrInvalidPollCloseTime):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		default:
+			utils.HandleInternalError(w, err)
+		}
 		return
 	}
 
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/service"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+)
+
+type RepostHandler struct {
+	repostService *service.RepostService
+	postService   *service.PostService
+}
+
+func NewRepostHandler(repostService *service.RepostService, postService *service.PostService) *RepostHandler {
+	return &RepostHandler{
+		repostService: repostService,
+		postService:   postService,
+	}
+}
+
+// Repost godoc
+//
+//	@Summary		Repost a post
+//	@Description	Share a public post into your followers' feeds, optionally with a quote
+//	@Tags			posts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int							true	"Post ID"
+//	@Param			repost	body		models.CreateRepostRequest	false	"Optional quote"
+//	@Success		201		{object}	utils.StandardResponse		"Post reposted successfully"
+//	@Failure		400		{object}	utils.StandardResponse		"Validation error"
+//	@Failure		403		{object}	utils.StandardResponse		"Post cannot be reposted"
+//	@Failure		404		{object}	utils.StandardResponse		"Post not found"
+//	@Failure		409		{object}	utils.StandardResponse		"Post already reposted"
+//	@Failure		500		{object}	utils.StandardResponse		"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/posts/{id}/repost [post]
+func (h *RepostHandler) Repost(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	post, ok := h.postService.GetPostFromContext(ctx)
+	if !ok {
+		utils.WriteErrorResponse(w, http.StatusNotFound, "Post not found")
+		return
+	}
+
+	// The body is optional: an empty body creates a plain repost
+	var req models.CreateRepostRequest
+	if err := utils.ReadJSON(w, r, &req); err != nil && !errors.Is(err, io.EOF) {
+		utils.HandleValidationError(w, err)
+		return
+	}
+	if err := service.Validate.Struct(req); err != nil {
+		utils.HandleValidationError(w, err)
+		return
+	}
+
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx = utils.SetUserID(ctx, int64(688))
+
+	repost, err := h.repostService.Repost(ctx, post, req)
+	if err != nil {
+		h.handleRepostError(w, err)
+		return
+	}
+
+	data := map[string]interface{}{
+		"repost": repost,
+	}
+	utils.WriteSuccessResponse(w, http.StatusCreated, data)
+}
+
+// Unrepost godoc
+//
+//	@Summary		Undo a repost
+//	@Description	Remove your plain repost of a post
+//	@Tags			posts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int						true	"Post ID"
+//	@Success		200	{object}	utils.StandardResponse	"Repost removed successfully"
+//	@Failure		404	{object}	utils.StandardResponse	"Repost not found"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/posts/{id}/repost [delete]
+func (h *RepostHandler) Unrepost(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	post, ok := h.postService.GetPostFromContext(ctx)
+	if !ok {
+		utils.WriteErrorResponse(w, http.StatusNotFound, "Post not found")
+		return
+	}
+
+	ctx = utils.SetUserID(ctx, int64(688))
+
+	if err := h.repostService.Unrepost(ctx, post); err != nil {
+		h.handleRepostError(w, err)
+		return
+	}
+
+	data := map[string]interface{}{
+		"message": "Repost removed successfully",
+	}
+	utils.WriteSuccessResponse(w, http.StatusOK, data)
+}
+
+// DeleteRepost godoc
+//
+//	@Summary		Delete a repost or quote post
+//	@Description	Delete one of your reposts or quote posts by its ID
+//	@Tags			posts
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int						true	"Repost ID"
+//	@Success		200	{object}	utils.StandardResponse	"Repost deleted successfully"
+//	@Failure		400	{object}	utils.StandardResponse	"Invalid repost ID"
+//	@Failure		404	{object}	utils.StandardResponse	"Repost not found"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/reposts/{id} [delete]
+func (h *RepostHandler) DeleteRepost(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ReadIDParam(r, "id")
+	if err != nil {
+		utils.HandleValidationError(w, errors.New("invalid input format"))
+		return
+	}
+
+	ctx := utils.SetUserID(r.Context(), int64(688))
+
+	if err := h.repostService.DeleteRepost(ctx, id); err != nil {
+		h.handleRepostError(w, err)
+		return
+	}
+
+	data := map[string]interface{}{
+		"message": "Repost deleted successfully",
+	}
+	utils.WriteSuccessResponse(w, http.StatusOK, data)
+}
+
+func (h *RepostHandler) handleRepostError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperrors.ErrRepostNotFound):
+		utils.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, apperrors.ErrAlreadyReposted):
+		utils.WriteErrorResponse(w, http.StatusConflict, err.Error())
+	case errors.Is(err, apperrors.ErrRepostNotAllowed):
+		utils.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, apperrors.ErrUserIDNotFound):
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+	default:
+		utils.HandleInternalError(w, err)
+	}
+}
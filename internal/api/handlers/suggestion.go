@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/LikhithMar14/gopher-chat/internal/service"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+)
+
+type SuggestionHandler struct {
+	suggestionService *service.SuggestionService
+}
+
+func NewSuggestionHandler(suggestionService *service.SuggestionService) *SuggestionHandler {
+	return &SuggestionHandler{
+		suggestionService: suggestionService,
+	}
+}
+
+// GetSuggestions godoc
+//
+//	@Summary		Who to follow
+//	@Description	Retrieve accounts to follow, ranked by mutual follows, tags shared with your posts and recent activity
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			limit	query		int							false	"Number of suggestions (default: 20, max: 50)"
+//	@Success		200		{object}	models.SuggestionsResponse	"Suggestions retrieved successfully"
+//	@Failure		401		{object}	utils.StandardResponse		"Unauthorized"
+//	@Failure		500		{object}	utils.StandardResponse		"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/users/me/suggestions [get]
+func (h *SuggestionHandler) GetSuggestions(w http.ResponseWriter, r *http.Request) {
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx := utils.SetUserID(r.Context(), int64(688))
+
+	res, err := h.suggestionService.GetSuggestions(ctx, utils.ReadCursorRequest(r).Limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrUserIDNotFound):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
+		default:
+			utils.HandleInternalError(w, err)
+		}
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, res)
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/service"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"github.com/LikhithMar14/gopher-chat/pkg/syndication"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type SyndicationHandler struct {
+	syndicationService *service.SyndicationService
+	userService        *service.UserService
+	logger             *zap.SugaredLogger
+}
+
+func NewSyndicationHandler(syndicationService *service.SyndicationService, userService *service.UserService, logger *zap.SugaredLogger) *SyndicationHandler {
+	return &SyndicationHandler{
+		syndicationService: syndicationService,
+		userService:        userService,
+		logger:             logger,
+	}
+}
+
+// GetUserAtom godoc
+//
+//	@Summary		Atom feed of a user's posts
+//	@Description	Retrieve the latest public posts of a public account as an Atom feed, with ETag and Last-Modified for conditional requests
+//	@Tags			feeds
+//	@Produce		xml
+//	@Param			id	path		int						true	"User ID"
+//	@Success		200	{string}	string					"Atom feed"
+//	@Success		304	{string}	string					"Feed not modified"
+//	@Failure		403	{object}	utils.StandardResponse	"Account is private"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Router			/users/{id}/posts.atom [get]
+func (h *SyndicationHandler) GetUserAtom(w http.ResponseWriter, r *http.Request) {
+	h.serveUserFeed(w, r, "atom")
+}
+
+// GetUserRSS godoc
+//
+//	@Summary		RSS feed of a user's posts
+//	@Description	Retrieve the latest public posts of a public account as an RSS 2.0 feed, with ETag and Last-Modified for conditional requests
+//	@Tags			feeds
+//	@Produce		xml
+//	@Param			id	path		int						true	"User ID"
+//	@Success		200	{string}	string					"RSS feed"
+//	@Success		304	{string}	string					"Feed not modified"
+//	@Failure		403	{object}	utils.StandardResponse	"Account is private"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Router			/users/{id}/posts.rss [get]
+func (h *SyndicationHandler) GetUserRSS(w http.ResponseWriter, r *http.Request) {
+	h.serveUserFeed(w, r, "rss")
+}
+
+// GetTagAtom godoc
+//
+//	@Summary		Atom feed of a tag
+//	@Description	Retrieve the latest public posts carrying a tag as an Atom feed, with ETag and Last-Modified for conditional requests
+//	@Tags			feeds
+//	@Produce		xml
+//	@Param			tag	path		string					true	"Tag"
+//	@Success		200	{string}	string					"Atom feed"
+//	@Success		304	{string}	string					"Feed not modified"
+//	@Failure		400	{object}	utils.StandardResponse	"Invalid tag"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Router			/tags/{tag}/posts.atom [get]
+func (h *SyndicationHandler) GetTagAtom(w http.ResponseWriter, r *http.Request) {
+	h.serveTagFeed(w, r, "atom")
+}
+
+// GetTagRSS godoc
+//
+//	@Summary		RSS feed of a tag
+//	@Description	Retrieve the latest public posts carrying a tag as an RSS 2.0 feed, with ETag and Last-Modified for conditional requests
+//	@Tags			feeds
+//	@Produce		xml
+//	@Param			tag	path		string					true	"Tag"
+//	@Success		200	{string}	string					"RSS feed"
+//	@Success		304	{string}	string					"Feed not modified"
+//	@Failure		400	{object}	utils.StandardResponse	"Invalid tag"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Router			/tags/{tag}/posts.rss [get]
+func (h *SyndicationHandler) GetTagRSS(w http.ResponseWriter, r *http.Request) {
+	h.serveTagFeed(w, r, "rss")
+}
+
+func (h *SyndicationHandler) serveUserFeed(w http.ResponseWriter, r *http.Request, format string) {
+	user, ok := h.userService.GetUserFromContext(r.Context())
+	if !ok {
+		utils.WriteErrorResponse(w, http.StatusNotFound, apperrors.ErrUserNotFound.Error())
+		return
+	}
+
+	feed, err := h.syndicationService.GetUserFeed(r.Context(), user, format)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrPrivateAccount):
+			utils.WriteErrorResponse(w, http.StatusForbidden, err.Error())
+		default:
+			utils.HandleInternalError(w, err)
+		}
+		return
+	}
+
+	h.writeFeed(w, r, feed, format)
+}
+
+func (h *SyndicationHandler) serveTagFeed(w http.ResponseWriter, r *http.Request, format string) {
+	feed, err := h.syndicationService.GetTagFeed(r.Context(), chi.URLParam(r, "tag"), format)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperrors.ErrInvalidInput):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		default:
+			utils.HandleInternalError(w, err)
+		}
+		return
+	}
+
+	h.writeFeed(w, r, feed, format)
+}
+
+// writeFeed renders the feed as Atom or RSS and writes it, unless the client
+// already holds the current version according to If-None-Match or, without
+// it, If-Modified-Since.
+func (h *SyndicationHandler) writeFeed(w http.ResponseWriter, r *http.Request, feed *syndication.Feed, format string) {
+	render, contentType := syndication.Atom, syndication.AtomContentType
+	if format == "rss" {
+		render, contentType = syndication.RSS, syndication.RSSContentType
+	}
+	doc, err := render(feed)
+	if err != nil {
+		utils.HandleInternalError(w, err)
+		return
+	}
+
+	etag := syndication.ETag(doc)
+	modified := feed.LastModified().UTC().Truncate(time.Second)
+
+	w.Header().Set("ETag", etag)
+	if !modified.IsZero() {
+		w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+	}
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	if notModified(r, etag, modified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(doc); err != nil {
+		h.logger.Warnw("Failed to write feed", "self_link", feed.SelfLink, "error", err)
+	}
+}
+
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		return match == "*" || containsETag(match, etag)
+	}
+	if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !modified.IsZero() {
+		return !modified.After(since)
+	}
+	return false
+}
+
+// containsETag reports whether the If-None-Match list holds etag, comparing
+// weakly as RFC 9110 requires.
+func containsETag(list, etag string) bool {
+	for _, candidate := range strings.Split(list, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -48,12 +48,10 @@ func (h *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
 	utils.WriteSuccessResponse(w, http.StatusOK, data)
 }
 
-
-
 // GetUserByID godoc
 //
 //	@Summary		Get user by ID
-//	@Description	Get user by ID
+//	@Description	Get the public profile of a user, including follower, following and post counts
 //	@Tags			users
 //	@Accept			json
 //	@Produce		json
@@ -72,82 +70,58 @@ func (h *UserHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
 		utils.WriteErrorResponse(w, http.StatusNotFound, "User not found")
 		return
 	}
-	data := map[string]interface{}{
-		"user":    user,
-		"message": "User fetched successfully",
-		"success": true,
-	}
-
-	utils.WriteSuccessResponse(w, http.StatusOK, data)
-}
-
-func (h *UserHandler) FollowUser(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	user, ok := h.userService.GetUserFromContext(ctx)
-	if !ok {
-		utils.WriteErrorResponse(w, http.StatusNotFound, "User not found")
-		return
-	}
-
-	var req models.FollowUnfollowRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		utils.HandleValidationError(w, err)
-		return
-	}
-
-	// Check if trying to follow themselves
-	if user.ID == req.UserID {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Cannot follow yourself")
-		return
-	}
-
-	err := h.userService.FollowUser(ctx, user.ID, req.UserID)
+	profile, err := h.userService.GetProfile(ctx, user)
 	if err != nil {
-		switch {
-		case errors.Is(err, apperrors.ErrUserNotFound):
-			utils.WriteErrorResponse(w, http.StatusNotFound, "Target user not found")
-		case errors.Is(err, apperrors.ErrConflict):
-			utils.WriteErrorResponse(w, http.StatusConflict, "Already following this user")
-		default:
-			utils.HandleInternalError(w, err)
-		}
+		utils.HandleInternalError(w, err)
 		return
 	}
 
 	data := map[string]interface{}{
-		"message": "User followed successfully",
+		"user":    profile,
+		"message": "User fetched successfully",
 		"success": true,
 	}
 
 	utils.WriteSuccessResponse(w, http.StatusOK, data)
 }
 
-func (h *UserHandler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	user, ok := h.userService.GetUserFromContext(ctx)
-	if !ok {
-		utils.WriteErrorResponse(w, http.StatusNotFound, "User not found")
-		return
-	}
-
-	var req models.FollowUnfollowRequest
+// UpdateMe godoc
+//
+//	@Summary		Update my profile
+//	@Description	Update the display name, bio, links or avatar of the current user. Omitted fields are kept and an avatar_id of 0 removes the avatar.
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.UpdateProfileRequest	true	"Profile fields to change"
+//	@Success		200		{object}	utils.StandardResponse
+//	@Failure		400		{object}	utils.StandardResponse
+//	@Failure		401		{object}	utils.StandardResponse
+//	@Failure		500		{object}	utils.StandardResponse
+//	@Security		ApiKeyAuth
+//	@Router			/users/me [patch]
+func (h *UserHandler) UpdateMe(w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateProfileRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.HandleValidationError(w, err)
 		return
 	}
-
-	if user.ID == req.UserID {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Cannot unfollow yourself")
+	if err := service.Validate.Struct(req); err != nil {
+		utils.HandleValidationError(w, err)
 		return
 	}
 
-	err := h.userService.UnfollowUser(ctx, user.ID, req.UserID)
+	// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+	ctx := utils.SetUserID(r.Context(), int64(688))
+
+	profile, err := h.userService.UpdateProfile(ctx, &req)
 	if err != nil {
 		switch {
+		case errors.Is(err, apperrors.ErrInvalidAvatar):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, apperrors.ErrUserIDNotFound):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, err.Error())
 		case errors.Is(err, apperrors.ErrUserNotFound):
-			utils.WriteErrorResponse(w, http.StatusNotFound, "Target user not found")
-		case errors.Is(err, apperrors.ErrNotFound):
-			utils.WriteErrorResponse(w, http.StatusNotFound, "Not following this user")
+			utils.WriteErrorResponse(w, http.StatusNotFound, err.Error())
 		default:
 			utils.HandleInternalError(w, err)
 		}
@@ -155,9 +129,7 @@ func (h *UserHandler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
 	}
 
 	data := map[string]interface{}{
-		"message": "User unfollowed successfully",
-		"success": true,
+		"user": profile,
 	}
-
 	utils.WriteSuccessResponse(w, http.StatusOK, data)
 }
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/service"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+)
+
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+// CreateWebhook godoc
+//
+//	@Summary		Create a webhook
+//	@Description	Subscribe a URL to events. Deliveries are POSTed as JSON with the X-Gopher-Chat-Event, X-Gopher-Chat-Delivery and X-Gopher-Chat-Signature headers; the signature is "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">" made with the secret, which is only returned here. Without events, every event is delivered.
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		models.CreateWebhookRequest	true	"Webhook to create"
+//	@Success		201		{object}	models.Webhook				"Webhook created successfully"
+//	@Failure		400		{object}	utils.StandardResponse		"Validation error"
+//	@Failure		401		{object}	utils.StandardResponse		"Unauthorized"
+//	@Failure		500		{object}	utils.StandardResponse		"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateWebhookRequest
+	if err := utils.ReadJSON(w, r, &req); err != nil {
+		utils.HandleValidationError(w, err)
+		return
+	}
+	if err := service.Validate.Struct(req); err != nil {
+		utils.HandleValidationError(w, err)
+		return
+	}
+
+	hook, err := h.webhookService.CreateWebhook(r.Context(), req)
+	if err != nil {
+		utils.HandleInternalError(w, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusCreated, hook)
+}
+
+// GetWebhooks godoc
+//
+//	@Summary		List webhooks
+//	@Description	Retrieve every webhook, without their secrets
+//	@Tags			webhooks
+//	@Produce		json
+//	@Success		200	{array}		models.Webhook			"Webhooks retrieved successfully"
+//	@Failure		401	{object}	utils.StandardResponse	"Unauthorized"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/webhooks [get]
+func (h *WebhookHandler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	hooks, err := h.webhookService.GetWebhooks(r.Context())
+	if err != nil {
+		utils.HandleInternalError(w, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, hooks)
+}
+
+// GetWebhook godoc
+//
+//	@Summary		Get a webhook
+//	@Tags			webhooks
+//	@Produce		json
+//	@Param			id	path		int						true	"Webhook ID"
+//	@Success		200	{object}	models.Webhook			"Webhook retrieved successfully"
+//	@Failure		400	{object}	utils.StandardResponse	"Invalid webhook ID"
+//	@Failure		401	{object}	utils.StandardResponse	"Unauthorized"
+//	@Failure		404	{object}	utils.StandardResponse	"Webhook not found"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ReadIDParam(r, "id")
+	if err != nil {
+		utils.HandleValidationError(w, errors.New("invalid input format"))
+		return
+	}
+
+	hook, err := h.webhookService.GetWebhook(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, hook)
+}
+
+// UpdateWebhook godoc
+//
+//	@Summary		Update a webhook
+//	@Description	Change the URL, events, description of a webhook, or pause it by making it inactive. Deliveries queued while it is inactive are sent once it is active again.
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int							true	"Webhook ID"
+//	@Param			request	body		models.UpdateWebhookRequest	true	"Fields to change"
+//	@Success		200		{object}	models.Webhook				"Webhook updated successfully"
+//	@Failure		400		{object}	utils.StandardResponse		"Validation error"
+//	@Failure		401		{object}	utils.StandardResponse		"Unauthorized"
+//	@Failure		404		{object}	utils.StandardResponse		"Webhook not found"
+//	@Failure		500		{object}	utils.StandardResponse		"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/webhooks/{id} [patch]
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ReadIDParam(r, "id")
+	if err != nil {
+		utils.HandleValidationError(w, errors.New("invalid input format"))
+		return
+	}
+
+	var req models.UpdateWebhookRequest
+	if err := utils.ReadJSON(w, r, &req); err != nil {
+		utils.HandleValidationError(w, err)
+		return
+	}
+	if err := service.Validate.Struct(req); err != nil {
+		utils.HandleValidationError(w, err)
+		return
+	}
+
+	hook, err := h.webhookService.UpdateWebhook(r.Context(), id, req)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, hook)
+}
+
+// DeleteWebhook godoc
+//
+//	@Summary		Delete a webhook
+//	@Description	Delete a webhook with its delivery log
+//	@Tags			webhooks
+//	@Produce		json
+//	@Param			id	path		int						true	"Webhook ID"
+//	@Success		200	{object}	utils.StandardResponse	"Webhook deleted successfully"
+//	@Failure		400	{object}	utils.StandardResponse	"Invalid webhook ID"
+//	@Failure		401	{object}	utils.StandardResponse	"Unauthorized"
+//	@Failure		404	{object}	utils.StandardResponse	"Webhook not found"
+//	@Failure		500	{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ReadIDParam(r, "id")
+	if err != nil {
+		utils.HandleValidationError(w, errors.New("invalid input format"))
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(r.Context(), id); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, map[string]interface{}{
+		"message": "Webhook deleted successfully",
+	})
+}
+
+// GetDeliveries godoc
+//
+//	@Summary		List the deliveries of a webhook
+//	@Description	Retrieve the delivery log of a webhook, latest first, with the response status, the start of the response body and the duration of the latest attempt of each delivery
+//	@Tags			webhooks
+//	@Produce		json
+//	@Param			id		path		int									true	"Webhook ID"
+//	@Param			cursor	query		string								false	"Cursor returned by the previous page"
+//	@Param			limit	query		int									false	"Items per page (default: 20, max: 50)"
+//	@Success		200		{object}	models.WebhookDeliveriesResponse	"Deliveries retrieved successfully"
+//	@Failure		400		{object}	utils.StandardResponse				"Invalid cursor"
+//	@Failure		401		{object}	utils.StandardResponse				"Unauthorized"
+//	@Failure		404		{object}	utils.StandardResponse				"Webhook not found"
+//	@Failure		500		{object}	utils.StandardResponse				"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ReadIDParam(r, "id")
+	if err != nil {
+		utils.HandleValidationError(w, errors.New("invalid input format"))
+		return
+	}
+
+	res, err := h.webhookService.GetDeliveries(r.Context(), id, utils.ReadCursorRequest(r))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusOK, res)
+}
+
+// Redeliver godoc
+//
+//	@Summary		Redeliver a webhook delivery
+//	@Description	Queue a new delivery of the event of a past delivery, with the same payload and event id
+//	@Tags			webhooks
+//	@Produce		json
+//	@Param			id			path		int						true	"Webhook ID"
+//	@Param			deliveryID	path		int						true	"Delivery ID"
+//	@Success		202			{object}	models.WebhookDelivery	"Redelivery queued"
+//	@Failure		400			{object}	utils.StandardResponse	"Invalid ID"
+//	@Failure		401			{object}	utils.StandardResponse	"Unauthorized"
+//	@Failure		404			{object}	utils.StandardResponse	"Delivery not found"
+//	@Failure		500			{object}	utils.StandardResponse	"Internal server error"
+//	@Security		ApiKeyAuth
+//	@Router			/webhooks/{id}/deliveries/{deliveryID}/redeliver [post]
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	id, err := utils.ReadIDParam(r, "id")
+	if err != nil {
+		utils.HandleValidationError(w, errors.New("invalid input format"))
+		return
+	}
+	deliveryID, err := utils.ReadIDParam(r, "deliveryID")
+	if err != nil {
+		utils.HandleValidationError(w, errors.New("invalid input format"))
+		return
+	}
+
+	delivery, err := h.webhookService.Redeliver(r.Context(), id, deliveryID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	utils.WriteSuccessResponse(w, http.StatusAccepted, delivery)
+}
+
+func (h *WebhookHandler) handleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperrors.ErrWebhookNotFound), errors.Is(err, apperrors.ErrWebhookDeliveryNotFound):
+		utils.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, apperrors.ErrInvalidCursor):
+		utils.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+	default:
+		utils.HandleInternalError(w, err)
+	}
+}
@@ -2,8 +2,10 @@ package api
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/LikhithMar14/gopher-chat/internal/api/handlers"
@@ -29,7 +31,9 @@ func (app *Application) postsContextMiddleware(next http.Handler) http.Handler {
 			utils.HandleValidationError(w, errors.New("invalid input format"))
 			return
 		}
-		ctx := r.Context()
+		// Set user ID in context (hardcoded for demo - would come from auth middleware in real app)
+		// before the lookup, so posts only visible to the viewer are found.
+		ctx := utils.SetUserID(r.Context(), int64(688))
 		post, err := app.PostService.GetPostByID(ctx, id)
 		if err != nil {
 			switch {
@@ -67,6 +71,24 @@ func (app *Application) userContextMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// adminMiddleware only lets requests bearing the admin token through. The
+// admin endpoints can't be used at all while no token is configured.
+func (app *Application) adminMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := app.Config.Admin.Token
+		if token == "" {
+			utils.WriteErrorResponse(w, http.StatusForbidden, "admin endpoints are disabled")
+			return
+		}
+		bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, "invalid admin token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func (app *Application) Routes() *chi.Mux {
 	r := chi.NewRouter()
 
@@ -82,8 +104,24 @@ func (app *Application) Routes() *chi.Mux {
 	postHandler := handlers.NewPostHandler(app.PostService, app.CommentService, app.Logger)
 	commentHandler := handlers.NewCommentHandler(app.CommentService, app.PostService)
 	followHandler := handlers.NewFollowHandler(app.FollowService, app.UserService, app.Logger)
+	blockHandler := handlers.NewBlockHandler(app.BlockService, app.UserService)
+	suggestionHandler := handlers.NewSuggestionHandler(app.SuggestionService)
+	exploreHandler := handlers.NewExploreHandler(app.ExploreService)
+	syndicationHandler := handlers.NewSyndicationHandler(app.SyndicationService, app.UserService, app.Logger)
 	feedHandler := handlers.NewFeedHandler(app.UserService, app.PostService, app.FeedService)
 	authHandler := handlers.NewAuthHandler(app.AuthService)
+	engagementHandler := handlers.NewEngagementHandler(app.EngagementService, app.PostService)
+	repostHandler := handlers.NewRepostHandler(app.RepostService, app.PostService)
+	mentionHandler := handlers.NewMentionHandler(app.MentionService)
+	pollHandler := handlers.NewPollHandler(app.PollService, app.PostService)
+	attachmentHandler := handlers.NewAttachmentHandler(app.MediaService, app.Config.Media.MaxUploadSize, app.Logger)
+	federationHandler := handlers.NewFederationHandler(app.FederationService, app.UserService, app.Logger)
+	notificationHandler := handlers.NewNotificationHandler(app.NotificationService, app.Logger)
+	webhookHandler := handlers.NewWebhookHandler(app.WebhookService)
+	outboxHandler := handlers.NewOutboxHandler(app.OutboxService)
+
+	r.Get("/.well-known/webfinger", federationHandler.WebFinger)
+
 	r.Route("/v1", func(r chi.Router) {
 		r.Get("/swagger/*", httpSwagger.Handler(httpSwagger.URL("/v1/swagger/doc.json")))
 
@@ -96,6 +134,12 @@ func (app *Application) Routes() *chi.Mux {
 				r.Get("/", postHandler.GetPostByID)
 				r.Delete("/", postHandler.DeletePost)
 				r.Patch("/", postHandler.UpdatePost)
+				r.Post("/like", engagementHandler.ToggleLike)
+				r.Post("/bookmark", engagementHandler.ToggleBookmark)
+				r.Post("/repost", repostHandler.Repost)
+				r.Delete("/repost", repostHandler.Unrepost)
+				r.Post("/poll/votes", pollHandler.Vote)
+				r.Delete("/poll/votes", pollHandler.RetractVote)
 				r.Route("/comments", func(r chi.Router) {
 					r.Post("/", commentHandler.CreateComment)
 					r.Get("/", commentHandler.GetCommentsByPostID)
@@ -103,17 +147,95 @@ func (app *Application) Routes() *chi.Mux {
 			})
 		})
 
+		r.Delete("/reposts/{id}", repostHandler.DeleteRepost)
+
+		r.Get("/explore", exploreHandler.GetExplore)
+
+		r.Route("/tags/{tag}", func(r chi.Router) {
+			r.Get("/posts.atom", syndicationHandler.GetTagAtom)
+			r.Get("/posts.rss", syndicationHandler.GetTagRSS)
+		})
+
+		r.Route("/notifications", func(r chi.Router) {
+			r.Get("/", notificationHandler.GetNotifications)
+			r.Get("/stream", notificationHandler.Stream)
+			r.Get("/preferences", notificationHandler.GetPreferences)
+			r.Patch("/preferences", notificationHandler.UpdatePreferences)
+			r.Post("/read", notificationHandler.MarkAllRead)
+			r.Post("/{id}/read", notificationHandler.MarkRead)
+		})
+
+		r.Route("/ap", func(r chi.Router) {
+			r.Post("/inbox", federationHandler.Inbox)
+			r.Get("/posts/{id}", federationHandler.GetNote)
+			r.Route("/users/{id}", func(r chi.Router) {
+				r.Use(app.userContextMiddleware)
+				r.Get("/", federationHandler.GetActor)
+				r.Post("/inbox", federationHandler.Inbox)
+				r.Get("/outbox", federationHandler.GetOutbox)
+				r.Get("/followers", federationHandler.GetFollowers)
+			})
+		})
+
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Use(app.adminMiddleware)
+			r.Get("/", webhookHandler.GetWebhooks)
+			r.Post("/", webhookHandler.CreateWebhook)
+			r.Route("/{id}", func(r chi.Router) {
+				r.Get("/", webhookHandler.GetWebhook)
+				r.Patch("/", webhookHandler.UpdateWebhook)
+				r.Delete("/", webhookHandler.DeleteWebhook)
+				r.Get("/deliveries", webhookHandler.GetDeliveries)
+				r.Post("/deliveries/{deliveryID}/redeliver", webhookHandler.Redeliver)
+			})
+		})
+
+		r.Route("/outbox", func(r chi.Router) {
+			r.Use(app.adminMiddleware)
+			r.Get("/failed", outboxHandler.GetFailed)
+			r.Post("/{id}/retry", outboxHandler.Retry)
+		})
+
+		r.Route("/attachments", func(r chi.Router) {
+			r.Post("/", attachmentHandler.UploadAttachment)
+			r.Get("/{id}/download", attachmentHandler.DownloadAttachment)
+		})
+
 		r.Route("/users", func(r chi.Router) {
 			r.Put("/activate/{token}", authHandler.ActivateUser)
 			r.Get("/", userHandler.GetUsers)
+			r.Get("/lookup", federationHandler.LookupAccount)
+			r.Patch("/me", userHandler.UpdateMe)
+			r.Get("/me/blocks", blockHandler.GetBlocked)
+			r.Get("/me/mutes", blockHandler.GetMuted)
+			r.Get("/me/suggestions", suggestionHandler.GetSuggestions)
+			r.Route("/me/follow-requests", func(r chi.Router) {
+				r.Get("/", followHandler.GetFollowRequests)
+				r.Post("/{id}/approve", followHandler.ApproveFollowRequest)
+				r.Post("/{id}/deny", followHandler.DenyFollowRequest)
+			})
+			r.Get("/me/bookmarks", engagementHandler.GetBookmarks)
+			r.Get("/me/mentions", mentionHandler.GetMentions)
 			r.Route("/{id}", func(r chi.Router) {
 				r.Use(app.userContextMiddleware)
 				r.Get("/", userHandler.GetUserByID)
+				r.Post("/follow", followHandler.FollowUser)
+				r.Delete("/follow", followHandler.UnfollowUser)
+				// Kept for clients of the original PUT routes.
 				r.Put("/follow", followHandler.FollowUser)
 				r.Put("/unfollow", followHandler.UnfollowUser)
+				r.Get("/followers", followHandler.GetFollowers)
+				r.Get("/following", followHandler.GetFollowing)
+				r.Post("/block", blockHandler.Block)
+				r.Delete("/block", blockHandler.Unblock)
+				r.Post("/mute", blockHandler.Mute)
+				r.Delete("/mute", blockHandler.Unmute)
+				r.Get("/posts.atom", syndicationHandler.GetUserAtom)
+				r.Get("/posts.rss", syndicationHandler.GetUserRSS)
 			})
 			r.Route("/feed", func(r chi.Router) {
 				r.Get("/", feedHandler.GetFeed)
+				r.Get("/new", feedHandler.CountNewItems)
 			})
 
 		})
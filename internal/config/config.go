@@ -1,19 +1,33 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/LikhithMar14/gopher-chat/pkg/env"
 )
 
 type Config struct {
-	Addr        string
-	DB          DBConfig
-	Env         string
-	APIURL      string
-	FrontendURL string
-	Mail        MailConfig
-	FromEmail   string
+	Addr              string
+	DB                DBConfig
+	Env               string
+	APIURL            string
+	FrontendURL       string
+	Mail              MailConfig
+	FromEmail         string
+	Media             MediaConfig
+	LinkPreview       LinkPreviewConfig
+	Suggestions       SuggestionsConfig
+	Timeline          TimelineConfig
+	FeedRanking       FeedRankingConfig
+	Trending          TrendingConfig
+	Federation        FederationConfig
+	NotificationEmail NotificationEmailConfig
+	Webhooks          WebhooksConfig
+	Outbox            OutboxConfig
+	Admin             AdminConfig
 }
 
 type DBConfig struct {
@@ -30,6 +44,106 @@ type MailConfig struct {
 	FromEmail string
 }
 
+type MediaConfig struct {
+	Driver        string
+	LocalDir      string
+	MaxUploadSize int64
+	SigningSecret string
+	URLExpiry     time.Duration
+	Workers       int
+	S3            S3Config
+}
+
+type LinkPreviewConfig struct {
+	Timeout  time.Duration
+	MaxBytes int64
+	TTL      time.Duration
+	Workers  int
+}
+
+// SuggestionsConfig controls the precomputed who-to-follow suggestions. They
+// are recomputed for a user at most once per TTL, keeping up to Size entries.
+type SuggestionsConfig struct {
+	TTL  time.Duration
+	Size int
+}
+
+// TimelineConfig controls the materialized home timelines. Posts are fanned
+// out to the followers of authors with at most MaxFanOut followers; following
+// someone backfills up to BackfillSize of their latest posts and reposts.
+// Workers fan out posts and apply follow events in the background.
+type TimelineConfig struct {
+	MaxFanOut    int
+	BackfillSize int
+	Workers      int
+}
+
+// FeedRankingConfig holds the scoring weights of the ranked feed, see
+// models.FeedRanking.
+type FeedRankingConfig struct {
+	Window           time.Duration
+	HalfLife         time.Duration
+	RecencyWeight    float64
+	EngagementWeight float64
+	AuthorWeight     float64
+	TagWeight        float64
+}
+
+// TrendingConfig controls the trending aggregation: every Interval the top
+// Size posts and tags of each window are recomputed.
+type TrendingConfig struct {
+	Interval time.Duration
+	Size     int
+}
+
+// FederationConfig controls ActivityPub federation. Requests to other servers
+// give up after Timeout, and DeliveryWorkers deliver activities in parallel.
+// AllowPrivateAddresses lets instances on a private network federate, which
+// is only meant for development.
+type FederationConfig struct {
+	Timeout               time.Duration
+	DeliveryWorkers       int
+	AllowPrivateAddresses bool
+}
+
+// NotificationEmailConfig controls the emails of notifications. Workers
+// look for emails and digests due every PollInterval.
+type NotificationEmailConfig struct {
+	Workers      int
+	PollInterval time.Duration
+}
+
+// WebhooksConfig controls outgoing webhooks. Deliveries give up after
+// Timeout, and Workers deliver in parallel. AllowPrivateAddresses lets
+// webhooks receive deliveries on private networks and loopback addresses.
+type WebhooksConfig struct {
+	Timeout               time.Duration
+	Workers               int
+	AllowPrivateAddresses bool
+}
+
+// OutboxConfig controls the dispatch of the outbox, the emails and events
+// queued along with the changes they are about. Workers dispatch in
+// parallel.
+type OutboxConfig struct {
+	Workers int
+}
+
+// AdminConfig controls the admin endpoints, which manage webhooks and the
+// outbox. They require Token as a bearer token, and are disabled while it is
+// empty.
+type AdminConfig struct {
+	Token string
+}
+
+type S3Config struct {
+	Endpoint  string
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+}
+
 type SendgridConfig struct {
 	APIKey string
 }
@@ -60,9 +174,104 @@ func Load() Config {
 			},
 			Exp: env.GetDuration("MAIL_EXP", 10*time.Minute),
 		},
+		Media: MediaConfig{
+			Driver:        env.GetString("MEDIA_DRIVER", "local"),
+			LocalDir:      env.GetString("MEDIA_LOCAL_DIR", "./uploads"),
+			MaxUploadSize: int64(env.GetInt("MEDIA_MAX_UPLOAD_SIZE", 10<<20)),
+			SigningSecret: env.GetString("MEDIA_SIGNING_SECRET", ""),
+			URLExpiry:     env.GetDuration("MEDIA_URL_EXPIRY", 15*time.Minute),
+			Workers:       env.GetInt("MEDIA_PROCESSING_WORKERS", 2),
+			S3: S3Config{
+				Endpoint:  env.GetString("S3_ENDPOINT", ""),
+				Bucket:    env.GetString("S3_BUCKET", ""),
+				Region:    env.GetString("S3_REGION", "us-east-1"),
+				AccessKey: env.GetString("S3_ACCESS_KEY", ""),
+				SecretKey: env.GetString("S3_SECRET_KEY", ""),
+			},
+		},
+		LinkPreview: LinkPreviewConfig{
+			Timeout:  env.GetDuration("LINK_PREVIEW_TIMEOUT", 5*time.Second),
+			MaxBytes: int64(env.GetInt("LINK_PREVIEW_MAX_BYTES", 1<<20)),
+			TTL:      env.GetDuration("LINK_PREVIEW_TTL", 24*time.Hour),
+			Workers:  env.GetInt("LINK_PREVIEW_WORKERS", 2),
+		},
+		Suggestions: SuggestionsConfig{
+			TTL:  env.GetDuration("SUGGESTIONS_TTL", 6*time.Hour),
+			Size: env.GetInt("SUGGESTIONS_SIZE", 100),
+		},
+		Timeline: TimelineConfig{
+			MaxFanOut:    env.GetInt("TIMELINE_MAX_FANOUT", 10000),
+			BackfillSize: env.GetInt("TIMELINE_BACKFILL_SIZE", 200),
+			Workers:      env.GetInt("TIMELINE_WORKERS", 2),
+		},
+		FeedRanking: FeedRankingConfig{
+			Window:           env.GetDuration("FEED_RANKING_WINDOW", 72*time.Hour),
+			HalfLife:         env.GetDuration("FEED_RANKING_HALF_LIFE", 6*time.Hour),
+			RecencyWeight:    env.GetFloat("FEED_RANKING_RECENCY_WEIGHT", 3),
+			EngagementWeight: env.GetFloat("FEED_RANKING_ENGAGEMENT_WEIGHT", 1),
+			AuthorWeight:     env.GetFloat("FEED_RANKING_AUTHOR_WEIGHT", 1.5),
+			TagWeight:        env.GetFloat("FEED_RANKING_TAG_WEIGHT", 0.5),
+		},
+		Trending: TrendingConfig{
+			Interval: env.GetDuration("TRENDING_INTERVAL", 5*time.Minute),
+			Size:     env.GetInt("TRENDING_SIZE", 100),
+		},
+		Federation: FederationConfig{
+			Timeout:               env.GetDuration("FEDERATION_TIMEOUT", 10*time.Second),
+			DeliveryWorkers:       env.GetInt("FEDERATION_DELIVERY_WORKERS", 2),
+			AllowPrivateAddresses: env.GetBool("FEDERATION_ALLOW_PRIVATE_ADDRESSES", false),
+		},
+		NotificationEmail: NotificationEmailConfig{
+			Workers:      env.GetInt("NOTIFICATION_EMAIL_WORKERS", 1),
+			PollInterval: env.GetDuration("NOTIFICATION_EMAIL_POLL_INTERVAL", time.Minute),
+		},
+		Webhooks: WebhooksConfig{
+			Timeout:               env.GetDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+			Workers:               env.GetInt("WEBHOOK_WORKERS", 2),
+			AllowPrivateAddresses: env.GetBool("WEBHOOK_ALLOW_PRIVATE_ADDRESSES", false),
+		},
+		Outbox: OutboxConfig{
+			Workers: env.GetInt("OUTBOX_WORKERS", 2),
+		},
+		Admin: AdminConfig{
+			// WEBHOOK_ADMIN_TOKEN is the former name of the setting.
+			Token: env.GetString("ADMIN_TOKEN", env.GetString("WEBHOOK_ADMIN_TOKEN", "")),
+		},
 	}
 
 
 
 	return cfg
 }
+
+// Validate reports the settings the application cannot run with.
+func (c Config) Validate() error {
+	return c.FeedRanking.Validate()
+}
+
+// Validate checks that the window and half-life are positive and the weights
+// are non-negative numbers, so every entry gets a finite score.
+func (c FeedRankingConfig) Validate() error {
+	if c.Window <= 0 {
+		return errors.New("FEED_RANKING_WINDOW must be positive")
+	}
+	if c.HalfLife <= 0 {
+		return errors.New("FEED_RANKING_HALF_LIFE must be positive")
+	}
+
+	weights := []struct {
+		name  string
+		value float64
+	}{
+		{"FEED_RANKING_RECENCY_WEIGHT", c.RecencyWeight},
+		{"FEED_RANKING_ENGAGEMENT_WEIGHT", c.EngagementWeight},
+		{"FEED_RANKING_AUTHOR_WEIGHT", c.AuthorWeight},
+		{"FEED_RANKING_TAG_WEIGHT", c.TagWeight},
+	}
+	for _, w := range weights {
+		if math.IsNaN(w.value) || math.IsInf(w.value, 0) || w.value < 0 {
+			return fmt.Errorf("%s must be a non-negative number", w.name)
+		}
+	}
+	return nil
+}
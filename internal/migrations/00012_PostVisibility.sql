@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE posts
+    ADD COLUMN visibility VARCHAR(20) NOT NULL DEFAULT 'public'
+    CONSTRAINT posts_visibility_check CHECK (visibility IN ('public', 'followers', 'private'));
+
+CREATE INDEX idx_posts_visibility ON posts (visibility);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_posts_visibility;
+
+ALTER TABLE posts DROP COLUMN visibility;
+-- +goose StatementEnd
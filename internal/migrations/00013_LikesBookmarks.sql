@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE posts ADD COLUMN like_count BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS post_likes (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id BIGINT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, post_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_likes_post_id ON post_likes (post_id);
+
+CREATE TABLE IF NOT EXISTS post_bookmarks (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id BIGINT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, post_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_bookmarks_user_created ON post_bookmarks (user_id, created_at DESC, post_id DESC);
+-- +goose StatementEnd
+
+-- like_count is maintained by a trigger so every insert or delete, including
+-- cascades from deleted users, keeps the counter in step with post_likes.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION post_likes_count() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE posts SET like_count = like_count + 1 WHERE id = NEW.post_id;
+        RETURN NEW;
+    END IF;
+    UPDATE posts SET like_count = like_count - 1 WHERE id = OLD.post_id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_post_likes_count
+    AFTER INSERT OR DELETE ON post_likes
+    FOR EACH ROW EXECUTE PROCEDURE post_likes_count();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS trg_post_likes_count ON post_likes;
+DROP FUNCTION IF EXISTS post_likes_count();
+DROP TABLE IF EXISTS post_bookmarks;
+DROP TABLE IF EXISTS post_likes;
+ALTER TABLE posts DROP COLUMN like_count;
+-- +goose StatementEnd
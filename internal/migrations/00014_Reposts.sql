@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS reposts (
+    id BIGSERIAL PRIMARY KEY,
+    post_id BIGINT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    quote TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- A user can repost a post once, but may quote it any number of times
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reposts_user_post_plain ON reposts (user_id, post_id) WHERE quote IS NULL;
+CREATE INDEX IF NOT EXISTS idx_reposts_user_created ON reposts (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_reposts_post_id ON reposts (post_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS reposts;
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Existing attachments predate processing and are served as they are.
+ALTER TABLE attachments
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'ready'
+    CONSTRAINT attachments_status_check CHECK (status IN ('pending', 'processing', 'ready', 'failed')),
+    ADD COLUMN width INT,
+    ADD COLUMN height INT,
+    ADD COLUMN attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN processing_error TEXT,
+    ADD COLUMN processing_started_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN processed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_attachments_unprocessed ON attachments (id) WHERE status IN ('pending', 'processing');
+
+CREATE TABLE IF NOT EXISTS attachment_variants (
+    attachment_id BIGINT NOT NULL REFERENCES attachments(id) ON DELETE CASCADE,
+    name VARCHAR(20) NOT NULL,
+    storage_key TEXT NOT NULL UNIQUE,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    width INT NOT NULL,
+    height INT NOT NULL,
+    PRIMARY KEY (attachment_id, name)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS attachment_variants;
+
+DROP INDEX IF EXISTS idx_attachments_unprocessed;
+
+ALTER TABLE attachments
+    DROP COLUMN processed_at,
+    DROP COLUMN processing_started_at,
+    DROP COLUMN processing_error,
+    DROP COLUMN attempts,
+    DROP COLUMN height,
+    DROP COLUMN width,
+    DROP COLUMN status;
+-- +goose StatementEnd
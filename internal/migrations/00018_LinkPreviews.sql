@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Previews are cached per URL and shared by every post linking to it.
+CREATE TABLE IF NOT EXISTS link_previews (
+    url TEXT PRIMARY KEY,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CONSTRAINT link_previews_status_check CHECK (status IN ('pending', 'fetching', 'ready', 'failed')),
+    title TEXT,
+    description TEXT,
+    image_url TEXT,
+    site_name TEXT,
+    attempts INT NOT NULL DEFAULT 0,
+    error TEXT,
+    fetch_started_at TIMESTAMP WITH TIME ZONE,
+    fetched_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_link_previews_unfetched ON link_previews (created_at) WHERE status IN ('pending', 'fetching');
+
+CREATE TABLE IF NOT EXISTS post_link_previews (
+    post_id BIGINT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    url TEXT NOT NULL REFERENCES link_previews(url) ON DELETE CASCADE,
+    position SMALLINT NOT NULL,
+    PRIMARY KEY (post_id, url)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_link_previews_url ON post_link_previews (url);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS post_link_previews;
+DROP TABLE IF EXISTS link_previews;
+-- +goose StatementEnd
@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS polls (
+    id BIGSERIAL PRIMARY KEY,
+    post_id BIGINT NOT NULL UNIQUE REFERENCES posts(id) ON DELETE CASCADE,
+    multiple_choice BOOLEAN NOT NULL DEFAULT FALSE,
+    results_visibility VARCHAR(20) NOT NULL DEFAULT 'always'
+        CONSTRAINT polls_results_visibility_check CHECK (results_visibility IN ('always', 'after_vote', 'after_close')),
+    closes_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT polls_after_close_needs_close_time CHECK (results_visibility <> 'after_close' OR closes_at IS NOT NULL)
+);
+
+CREATE TABLE IF NOT EXISTS poll_options (
+    id BIGSERIAL PRIMARY KEY,
+    poll_id BIGINT NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    position SMALLINT NOT NULL,
+    text VARCHAR(100) NOT NULL,
+    vote_count BIGINT NOT NULL DEFAULT 0,
+    UNIQUE (poll_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS poll_votes (
+    poll_id BIGINT NOT NULL REFERENCES polls(id) ON DELETE CASCADE,
+    option_id BIGINT NOT NULL REFERENCES poll_options(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (poll_id, user_id, option_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_poll_votes_option_id ON poll_votes (option_id);
+-- +goose StatementEnd
+
+-- vote_count is maintained by a trigger, like posts.like_count, so changed
+-- votes and cascades from deleted users keep the tallies correct.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION poll_votes_count() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE poll_options SET vote_count = vote_count + 1 WHERE id = NEW.option_id;
+        RETURN NEW;
+    END IF;
+    UPDATE poll_options SET vote_count = vote_count - 1 WHERE id = OLD.option_id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER trg_poll_votes_count
+    AFTER INSERT OR DELETE ON poll_votes
+    FOR EACH ROW EXECUTE PROCEDURE poll_votes_count();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS trg_poll_votes_count ON poll_votes;
+DROP FUNCTION IF EXISTS poll_votes_count();
+DROP TABLE IF EXISTS poll_votes;
+DROP TABLE IF EXISTS poll_options;
+DROP TABLE IF EXISTS polls;
+-- +goose StatementEnd
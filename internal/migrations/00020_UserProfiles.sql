@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN display_name VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN bio VARCHAR(300) NOT NULL DEFAULT '',
+    ADD COLUMN links TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN avatar_attachment_id BIGINT REFERENCES attachments(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP COLUMN avatar_attachment_id,
+    DROP COLUMN links,
+    DROP COLUMN bio,
+    DROP COLUMN display_name;
+-- +goose StatementEnd
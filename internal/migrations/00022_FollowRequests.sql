@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN is_private BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS follow_requests (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    requester_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, requester_id),
+    CONSTRAINT follow_requests_not_self CHECK (user_id <> requester_id)
+);
+
+CREATE INDEX idx_follow_requests_user_created ON follow_requests (user_id, created_at DESC);
+CREATE INDEX idx_follow_requests_requester_id ON follow_requests (requester_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS follow_requests;
+
+ALTER TABLE users DROP COLUMN is_private;
+-- +goose StatementEnd
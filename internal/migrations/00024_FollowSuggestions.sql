@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS follow_suggestions (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    suggested_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    score DOUBLE PRECISION NOT NULL,
+    mutual_count INT NOT NULL DEFAULT 0,
+    shared_tag_count INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, suggested_id)
+);
+
+CREATE INDEX idx_follow_suggestions_user_score ON follow_suggestions (user_id, score DESC);
+
+CREATE TABLE IF NOT EXISTS follow_suggestion_runs (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    computed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_posts_user_created ON posts (user_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_posts_user_created;
+
+DROP TABLE IF EXISTS follow_suggestion_runs;
+
+DROP TABLE IF EXISTS follow_suggestions;
+-- +goose StatementEnd
@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+DELETE FROM followers
+WHERE user_id = follower_id
+    OR NOT EXISTS (SELECT 1 FROM users u WHERE u.id = followers.user_id)
+    OR NOT EXISTS (SELECT 1 FROM users u WHERE u.id = followers.follower_id);
+
+ALTER TABLE followers
+    ADD CONSTRAINT followers_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    ADD CONSTRAINT followers_follower_id_fkey FOREIGN KEY (follower_id) REFERENCES users(id) ON DELETE CASCADE,
+    ADD CONSTRAINT followers_not_self CHECK (user_id <> follower_id);
+
+-- The timestamps were written without a time zone by a server running in UTC.
+ALTER TABLE followers
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at SET DEFAULT NOW(),
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_at SET DEFAULT NOW();
+
+CREATE TABLE IF NOT EXISTS follow_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    follower_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('follow', 'unfollow')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_follow_events_user_id ON follow_events (user_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS follow_events;
+
+ALTER TABLE followers
+    ALTER COLUMN created_at TYPE TIMESTAMP USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at SET DEFAULT CURRENT_TIMESTAMP,
+    ALTER COLUMN updated_at TYPE TIMESTAMP USING updated_at AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_at SET DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE followers
+    DROP CONSTRAINT followers_not_self,
+    DROP CONSTRAINT followers_follower_id_fkey,
+    DROP CONSTRAINT followers_user_id_fkey;
+-- +goose StatementEnd
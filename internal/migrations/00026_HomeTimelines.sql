@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Materialized home timelines. actor_id is the followed user whose activity
+-- put the entry there: the author of a post or the user who reposted it.
+CREATE TABLE IF NOT EXISTS timeline_entries (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id BIGINT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    repost_id BIGINT REFERENCES reposts(id) ON DELETE CASCADE,
+    actor_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    dedupe_key TEXT NOT NULL,
+    activity_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_timeline_entries_entry ON timeline_entries (user_id, post_id, COALESCE(repost_id, 0));
+CREATE INDEX idx_timeline_entries_user_activity ON timeline_entries (user_id, activity_at DESC);
+CREATE INDEX idx_timeline_entries_user_actor ON timeline_entries (user_id, actor_id);
+
+-- Authors with too many followers to fan out to. Their posts and reposts are
+-- read from the source tables when building a timeline instead.
+CREATE TABLE IF NOT EXISTS timeline_pull_authors (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Position of the timeline maintenance in follow_events.
+CREATE TABLE IF NOT EXISTS timeline_follow_cursor (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    last_event_id BIGINT NOT NULL
+);
+
+INSERT INTO timeline_follow_cursor (last_event_id)
+SELECT COALESCE(MAX(id), 0) FROM follow_events;
+
+INSERT INTO timeline_entries (user_id, post_id, repost_id, actor_id, dedupe_key, activity_at)
+SELECT f.follower_id, p.id, NULL, p.user_id, 'p' || p.id, p.created_at
+FROM posts p
+INNER JOIN followers f ON f.user_id = p.user_id
+UNION ALL
+SELECT f.follower_id, r.post_id, r.id, r.user_id,
+    CASE WHEN r.quote IS NULL THEN 'p' || r.post_id ELSE 'q' || r.id END,
+    r.created_at
+FROM reposts r
+INNER JOIN followers f ON f.user_id = r.user_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS timeline_follow_cursor;
+
+DROP TABLE IF EXISTS timeline_pull_authors;
+
+DROP TABLE IF EXISTS timeline_entries;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The ranked feed looks up the recent likes and comments of the viewer to
+-- derive author and tag affinity.
+CREATE INDEX IF NOT EXISTS idx_comments_user_created ON comments (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_post_likes_user_created ON post_likes (user_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_post_likes_user_created;
+
+DROP INDEX IF EXISTS idx_comments_user_created;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Trending posts and tags are recomputed per window by a background job and
+-- served from these tables. computed_at on the run row tells instances when a
+-- window is due again.
+CREATE TABLE IF NOT EXISTS trending_runs (
+    period TEXT PRIMARY KEY,
+    computed_at TIMESTAMPTZ NOT NULL DEFAULT 'epoch'
+);
+
+CREATE TABLE IF NOT EXISTS trending_posts (
+    period TEXT NOT NULL,
+    post_id BIGINT NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    score DOUBLE PRECISION NOT NULL,
+    engager_count INT NOT NULL,
+    PRIMARY KEY (period, post_id)
+);
+
+CREATE INDEX idx_trending_posts_period_score ON trending_posts (period, score DESC);
+
+CREATE TABLE IF NOT EXISTS trending_tags (
+    period TEXT NOT NULL,
+    tag TEXT NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    post_count INT NOT NULL,
+    author_count INT NOT NULL,
+    PRIMARY KEY (period, tag)
+);
+
+CREATE INDEX idx_trending_tags_period_score ON trending_tags (period, score DESC);
+
+CREATE INDEX IF NOT EXISTS idx_posts_created_at ON posts (created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_post_likes_created_at ON post_likes (created_at);
+CREATE INDEX IF NOT EXISTS idx_comments_created_at ON comments (created_at);
+CREATE INDEX IF NOT EXISTS idx_reposts_created_at ON reposts (created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_reposts_created_at;
+DROP INDEX IF EXISTS idx_comments_created_at;
+DROP INDEX IF EXISTS idx_post_likes_created_at;
+DROP INDEX IF EXISTS idx_posts_created_at;
+
+DROP TABLE IF EXISTS trending_tags;
+
+DROP TABLE IF EXISTS trending_posts;
+
+DROP TABLE IF EXISTS trending_runs;
+-- +goose StatementEnd
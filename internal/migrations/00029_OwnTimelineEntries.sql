@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Home timelines include the user's own posts and reposts.
+INSERT INTO timeline_entries (user_id, post_id, repost_id, actor_id, dedupe_key, activity_at)
+SELECT p.user_id, p.id, NULL, p.user_id, 'p' || p.id, p.created_at
+FROM posts p
+UNION ALL
+SELECT r.user_id, r.post_id, r.id, r.user_id,
+    CASE WHEN r.quote IS NULL THEN 'p' || r.post_id ELSE 'q' || r.id END,
+    r.created_at
+FROM reposts r
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM timeline_entries WHERE user_id = actor_id;
+-- +goose StatementEnd
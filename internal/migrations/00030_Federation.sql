@@ -0,0 +1,79 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Accounts on other ActivityPub servers are stored as users with the domain
+-- of their server, so follows, likes and posts of remote accounts live in the
+-- same tables as local ones. Remote accounts have no email or password.
+ALTER TABLE users
+    ADD COLUMN domain TEXT,
+    ALTER COLUMN email DROP NOT NULL,
+    ALTER COLUMN password_hash DROP NOT NULL,
+    ADD CONSTRAINT users_local_credentials CHECK (domain IS NOT NULL OR (email IS NOT NULL AND password_hash IS NOT NULL));
+
+-- The key pairs local accounts sign their deliveries with, generated on first
+-- use.
+CREATE TABLE IF NOT EXISTS actor_keys (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    public_key_pem TEXT NOT NULL,
+    private_key_pem TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS remote_actors (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    uri TEXT NOT NULL UNIQUE,
+    key_id TEXT NOT NULL UNIQUE,
+    public_key_pem TEXT NOT NULL,
+    inbox TEXT NOT NULL,
+    shared_inbox TEXT,
+    profile_url TEXT,
+    fetched_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Posts of remote accounts keep the id of their object.
+ALTER TABLE posts ADD COLUMN ap_id TEXT UNIQUE;
+
+-- The Follow activity behind a follow, or a request to follow, between a
+-- local and a remote account, referenced when it is accepted or undone.
+CREATE TABLE IF NOT EXISTS follow_activities (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    follower_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    activity_id TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, follower_id)
+);
+
+CREATE TABLE IF NOT EXISTS federation_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    sender_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    inbox TEXT NOT NULL,
+    activity TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivering', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMPTZ,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_federation_deliveries_due ON federation_deliveries (next_attempt_at) WHERE status <> 'failed';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS federation_deliveries;
+DROP TABLE IF EXISTS follow_activities;
+
+ALTER TABLE posts DROP COLUMN ap_id;
+
+DROP TABLE IF EXISTS remote_actors;
+DROP TABLE IF EXISTS actor_keys;
+
+DELETE FROM posts WHERE user_id IN (SELECT id FROM users WHERE domain IS NOT NULL);
+DELETE FROM users WHERE domain IS NOT NULL;
+
+ALTER TABLE users
+    DROP CONSTRAINT users_local_credentials,
+    ALTER COLUMN password_hash SET NOT NULL,
+    ALTER COLUMN email SET NOT NULL,
+    DROP COLUMN domain;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- In-app notifications. actor_id is the user whose action caused the
+-- notification; post_id and comment_id point at what it is about, if
+-- anything.
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    actor_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('follow', 'follow_request', 'comment', 'reply', 'mention', 'like')),
+    post_id BIGINT REFERENCES posts(id) ON DELETE CASCADE,
+    comment_id BIGINT REFERENCES comments(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    read_at TIMESTAMPTZ,
+    CONSTRAINT notifications_not_self CHECK (user_id <> actor_id)
+);
+
+-- An action notifies a user once, however often it is repeated.
+CREATE UNIQUE INDEX idx_notifications_dedupe
+    ON notifications (user_id, kind, actor_id, COALESCE(post_id, 0), COALESCE(comment_id, 0));
+CREATE INDEX idx_notifications_user_id ON notifications (user_id, id DESC);
+CREATE INDEX idx_notifications_unread ON notifications (user_id) WHERE read_at IS NULL;
+
+-- Position of the follow notifications in follow_events.
+CREATE TABLE IF NOT EXISTS notification_follow_cursor (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    last_event_id BIGINT NOT NULL
+);
+
+INSERT INTO notification_follow_cursor (last_event_id)
+SELECT COALESCE(MAX(id), 0) FROM follow_events;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notification_follow_cursor;
+
+DROP TABLE IF EXISTS notifications;
+-- +goose StatementEnd
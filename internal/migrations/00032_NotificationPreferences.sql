@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+-- How users hear about each kind of notification. Kinds without a row are
+-- only shown in the app. updated_at is when the delivery last changed, so
+-- switching to email doesn't send notifications from before.
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('follow', 'follow_request', 'comment', 'reply', 'mention', 'like')),
+    delivery VARCHAR(10) NOT NULL CHECK (delivery IN ('in_app', 'email', 'daily', 'weekly')),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, kind)
+);
+
+CREATE INDEX idx_notification_preferences_email
+    ON notification_preferences (user_id) WHERE delivery <> 'in_app';
+
+-- The timezone and quiet hours of users, and when their last digests were
+-- sent. Quiet hours wrap around midnight when they end before they start.
+CREATE TABLE IF NOT EXISTS notification_settings (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    quiet_hours_start TIME,
+    quiet_hours_end TIME,
+    last_daily_digest_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_weekly_digest_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT notification_settings_quiet_hours CHECK ((quiet_hours_start IS NULL) = (quiet_hours_end IS NULL))
+);
+
+-- When a notification was emailed, on its own or in a digest.
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS emailed_at TIMESTAMPTZ;
+
+CREATE INDEX idx_notifications_unemailed ON notifications (user_id) WHERE read_at IS NULL AND emailed_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_notifications_unemailed;
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS emailed_at;
+
+DROP TABLE IF EXISTS notification_settings;
+
+DROP TABLE IF EXISTS notification_preferences;
+-- +goose StatementEnd
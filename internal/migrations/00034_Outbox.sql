@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Side effects of a change, written in the transaction making it and
+-- dispatched in the background: emails to send and events to deliver to
+-- webhooks. Dispatched messages are deleted.
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    kind TEXT NOT NULL CHECK (kind IN ('email', 'event')),
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'dispatching', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMPTZ,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_outbox_due ON outbox (next_attempt_at) WHERE status <> 'failed';
+
+-- An event is queued for each webhook once, even when its outbox message is
+-- dispatched again after a crash.
+CREATE UNIQUE INDEX idx_webhook_deliveries_event ON webhook_deliveries (webhook_id, event_id) WHERE redelivery_of IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_webhook_deliveries_event;
+
+DROP TABLE IF EXISTS outbox;
+-- +goose StatementEnd
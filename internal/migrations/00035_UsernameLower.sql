@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Mentions are resolved case-insensitively.
+CREATE INDEX IF NOT EXISTS idx_users_username_lower ON users (lower(username));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_users_username_lower;
+-- +goose StatementEnd
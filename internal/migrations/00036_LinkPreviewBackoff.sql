@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Failed fetches are retried with backoff rather than right away.
+ALTER TABLE link_previews ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE link_previews DROP COLUMN IF EXISTS next_attempt_at;
+-- +goose StatementEnd
@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Follow events are marked as handled by each of their consumers instead of
+-- consumers keeping their position in follow_events: ids are taken before
+-- commit, so an event can become visible after events with higher ids were
+-- already handled.
+ALTER TABLE follow_events
+    ADD COLUMN timeline_applied BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN notified BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN webhooks_queued BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE follow_events SET timeline_applied = TRUE
+WHERE id <= (SELECT last_event_id FROM timeline_follow_cursor);
+
+UPDATE follow_events SET notified = TRUE
+WHERE id <= (SELECT last_event_id FROM notification_follow_cursor);
+
+UPDATE follow_events SET webhooks_queued = TRUE
+WHERE id <= (SELECT last_event_id FROM webhook_follow_cursor);
+
+CREATE INDEX idx_follow_events_timeline_pending ON follow_events (id) WHERE NOT timeline_applied;
+CREATE INDEX idx_follow_events_notification_pending ON follow_events (id) WHERE NOT notified;
+CREATE INDEX idx_follow_events_webhook_pending ON follow_events (id) WHERE NOT webhooks_queued;
+
+DROP TABLE timeline_follow_cursor;
+DROP TABLE notification_follow_cursor;
+DROP TABLE webhook_follow_cursor;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS timeline_follow_cursor (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    last_event_id BIGINT NOT NULL
+);
+INSERT INTO timeline_follow_cursor (last_event_id)
+SELECT COALESCE(MIN(id) - 1, (SELECT COALESCE(MAX(id), 0) FROM follow_events)) FROM follow_events WHERE NOT timeline_applied;
+
+CREATE TABLE IF NOT EXISTS notification_follow_cursor (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    last_event_id BIGINT NOT NULL
+);
+INSERT INTO notification_follow_cursor (last_event_id)
+SELECT COALESCE(MIN(id) - 1, (SELECT COALESCE(MAX(id), 0) FROM follow_events)) FROM follow_events WHERE NOT notified;
+
+CREATE TABLE IF NOT EXISTS webhook_follow_cursor (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    last_event_id BIGINT NOT NULL
+);
+INSERT INTO webhook_follow_cursor (last_event_id)
+SELECT COALESCE(MIN(id) - 1, (SELECT COALESCE(MAX(id), 0) FROM follow_events)) FROM follow_events WHERE NOT webhooks_queued;
+
+ALTER TABLE follow_events
+    DROP COLUMN webhooks_queued,
+    DROP COLUMN notified,
+    DROP COLUMN timeline_applied;
+-- +goose StatementEnd
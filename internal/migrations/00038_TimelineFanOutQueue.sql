@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Posts and reposts waiting to be fanned out to home timelines by the
+-- TimelineWorker. New rows are pending; existing ones were fanned out.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS timeline_pending BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE posts ALTER COLUMN timeline_pending SET DEFAULT TRUE;
+
+ALTER TABLE reposts ADD COLUMN IF NOT EXISTS timeline_pending BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE reposts ALTER COLUMN timeline_pending SET DEFAULT TRUE;
+
+CREATE INDEX idx_posts_timeline_pending ON posts (id) WHERE timeline_pending;
+CREATE INDEX idx_reposts_timeline_pending ON reposts (id) WHERE timeline_pending;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE reposts DROP COLUMN IF EXISTS timeline_pending;
+
+ALTER TABLE posts DROP COLUMN IF EXISTS timeline_pending;
+-- +goose StatementEnd
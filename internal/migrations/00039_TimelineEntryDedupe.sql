@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Keep one entry per dedupe key and timeline, at its most recent activity.
+DELETE FROM timeline_entries t
+USING timeline_entries newer
+WHERE newer.user_id = t.user_id
+    AND newer.dedupe_key = t.dedupe_key
+    AND (newer.activity_at, newer.ctid) > (t.activity_at, t.ctid);
+
+DROP INDEX IF EXISTS idx_timeline_entries_entry;
+CREATE UNIQUE INDEX idx_timeline_entries_dedupe ON timeline_entries (user_id, dedupe_key);
+
+-- Timelines only keep the entries of the last 30 days.
+DELETE FROM timeline_entries WHERE activity_at <= NOW() - INTERVAL '30 days';
+CREATE INDEX idx_timeline_entries_activity ON timeline_entries (activity_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_timeline_entries_activity;
+
+DROP INDEX IF EXISTS idx_timeline_entries_dedupe;
+CREATE UNIQUE INDEX idx_timeline_entries_entry ON timeline_entries (user_id, post_id, COALESCE(repost_id, 0));
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Tags are stored lower cased, so they can be matched with the GIN index on
+-- posts.tags. Tags differing only in case are merged, keeping the first.
+UPDATE posts p
+SET tags = ARRAY(
+    SELECT t.tag
+    FROM (
+        SELECT lower(u.tag) AS tag, MIN(u.ord) AS ord
+        FROM unnest(p.tags) WITH ORDINALITY AS u(tag, ord)
+        GROUP BY lower(u.tag)
+    ) t
+    ORDER BY t.ord
+)
+WHERE p.tags::TEXT <> lower(p.tags::TEXT);
+-- +goose StatementEnd
+
+-- +goose Down
+-- The original case of the tags is not kept, so there is nothing to undo.
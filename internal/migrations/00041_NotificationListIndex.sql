@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Notification lists read a bounded range of recent notifications.
+CREATE INDEX idx_notifications_user_created ON notifications (user_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_notifications_user_created;
+-- +goose StatementEnd
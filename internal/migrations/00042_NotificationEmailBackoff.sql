@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- How often emailing a notification was attempted, and when it may be
+-- attempted again after a failure.
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS email_attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS email_next_attempt_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE notifications DROP COLUMN IF EXISTS email_next_attempt_at;
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS email_attempts;
+-- +goose StatementEnd
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
@@ -52,47 +53,245 @@ func (p *Password) HasText() bool {
 	return p.text != nil
 }
 
+// PostVisibility controls who is allowed to see a post.
+type PostVisibility string
+
+const (
+	// VisibilityPublic posts are visible to everyone.
+	VisibilityPublic PostVisibility = "public"
+	// VisibilityFollowers posts are visible to the author and their followers.
+	VisibilityFollowers PostVisibility = "followers"
+	// VisibilityPrivate posts are visible to the author only.
+	VisibilityPrivate PostVisibility = "private"
+)
+
 type Post struct {
-	ID        int64      `json:"id"`
-	Content   string     `json:"content"`
-	Title     string     `json:"title"`
-	UserID    int64      `json:"user_id"`
-	Tags      []string   `json:"tags"`
-	Version   int        `json:"version"`
-	Comments  []*Comment `json:"comments"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
+	ID           int64          `json:"id"`
+	Content      string         `json:"content"`
+	ContentHTML  string         `json:"content_html"`
+	Title        string         `json:"title"`
+	UserID       int64          `json:"user_id"`
+	Tags         []string       `json:"tags"`
+	Visibility   PostVisibility `json:"visibility"`
+	Version      int            `json:"version"`
+	Mentions     []Mention      `json:"mentions"`
+	Attachments  []*Attachment  `json:"attachments"`
+	LinkPreviews []LinkPreview  `json:"link_previews"`
+	Poll         *Poll          `json:"poll,omitempty"`
+	Comments     []*Comment     `json:"comments"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
 }
 
-type Comment struct {
+// Repost shares an existing post into the reposter's followers' feeds. A
+// repost with a Quote is a quote post carrying the reposter's commentary.
+type Repost struct {
 	ID        int64     `json:"id"`
 	PostID    int64     `json:"post_id"`
 	UserID    int64     `json:"user_id"`
-	Content   string    `json:"content"`
+	Quote     *string   `json:"quote,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	User      User      `json:"user"`
+}
+
+type Comment struct {
+	ID          int64         `json:"id"`
+	PostID      int64         `json:"post_id"`
+	UserID      int64         `json:"user_id"`
+	Content     string        `json:"content"`
+	ContentHTML string        `json:"content_html"`
+	Mentions    []Mention     `json:"mentions"`
+	Attachments []*Attachment `json:"attachments"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	User        PublicUser    `json:"user"`
+}
+
+// AttachmentStatus tracks the processing of an uploaded attachment.
+type AttachmentStatus string
+
+const (
+	// AttachmentPending images are waiting for the processing worker.
+	AttachmentPending AttachmentStatus = "pending"
+	// AttachmentProcessing images are being processed by a worker.
+	AttachmentProcessing AttachmentStatus = "processing"
+	// AttachmentReady attachments can be downloaded.
+	AttachmentReady AttachmentStatus = "ready"
+	// AttachmentFailed images could not be processed and are never served.
+	AttachmentFailed AttachmentStatus = "failed"
+)
+
+// Attachment is an uploaded file linked to a post or comment. URL is a signed,
+// expiring download link filled in when the attachment is returned, and only
+// once the attachment is ready.
+type Attachment struct {
+	ID          int64                `json:"id"`
+	UserID      int64                `json:"user_id"`
+	PostID      *int64               `json:"post_id,omitempty"`
+	CommentID   *int64               `json:"comment_id,omitempty"`
+	StorageKey  string               `json:"-"`
+	Filename    string               `json:"filename"`
+	ContentType string               `json:"content_type"`
+	Size        int64                `json:"size"`
+	Status      AttachmentStatus     `json:"status"`
+	Width       *int                 `json:"width,omitempty"`
+	Height      *int                 `json:"height,omitempty"`
+	URL         string               `json:"url,omitempty"`
+	Variants    []*AttachmentVariant `json:"variants,omitempty"`
+	Attempts    int                  `json:"-"`
+	CreatedAt   time.Time            `json:"created_at"`
+}
+
+// AttachmentVariant is a resized copy of an image attachment.
+type AttachmentVariant struct {
+	AttachmentID int64  `json:"-"`
+	Name         string `json:"name"`
+	StorageKey   string `json:"-"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	URL          string `json:"url"`
+}
+
+// LinkPreview is the OpenGraph or Twitter card metadata of a URL found in
+// post content.
+type LinkPreview struct {
+	URL         string `json:"url"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+}
+
+// PollResultsVisibility controls when voters can see the tallies of a poll.
+// The author of the post can always see them.
+type PollResultsVisibility string
+
+const (
+	// PollResultsAlways shows the tallies to everyone at any time.
+	PollResultsAlways PollResultsVisibility = "always"
+	// PollResultsAfterVote shows the tallies once the viewer has voted or
+	// the poll has closed.
+	PollResultsAfterVote PollResultsVisibility = "after_vote"
+	// PollResultsAfterClose shows the tallies once the poll has closed.
+	PollResultsAfterClose PollResultsVisibility = "after_close"
+)
+
+// Poll is attached to a post. When the results are hidden from the viewer
+// ResultsHidden is set and the vote counts are omitted.
+type Poll struct {
+	ID                int64                 `json:"id"`
+	PostID            int64                 `json:"post_id"`
+	MultipleChoice    bool                  `json:"multiple_choice"`
+	ResultsVisibility PollResultsVisibility `json:"results_visibility"`
+	ClosesAt          *time.Time            `json:"closes_at,omitempty"`
+	Closed            bool                  `json:"closed"`
+	Options           []*PollOption         `json:"options"`
+	VoterCount        *int64                `json:"voter_count,omitempty"`
+	ResultsHidden     bool                  `json:"results_hidden"`
+	MyVotes           []int64               `json:"my_votes"`
+	CreatedAt         time.Time             `json:"created_at"`
+}
+
+type PollOption struct {
+	ID        int64  `json:"id"`
+	Position  int    `json:"position"`
+	Text      string `json:"text"`
+	VoteCount *int64 `json:"vote_count,omitempty"`
+}
+
+// Mention links an @username in post or comment content to the user it
+// refers to.
+type Mention struct {
+	UserID   int64  `json:"user_id"`
+	Username string `json:"username"`
 }
 
 type User struct {
-	ID        int64     `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	Password  *Password `json:"-"`
-	Activated bool      `json:"activated"`
+	ID          int64     `json:"id"`
+	Username    string    `json:"username"`
+	Email       string    `json:"email"`
+	Password    *Password `json:"-"`
+	Activated   bool      `json:"activated"`
+	DisplayName string    `json:"display_name"`
+	Bio         string    `json:"bio"`
+	Links       []string  `json:"links"`
+	AvatarID    *int64    `json:"avatar_id,omitempty"`
+	IsPrivate   bool      `json:"is_private"`
+	// Domain is the server of a remote account, and empty for local ones.
+	Domain    string    `json:"domain,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// Public returns the projection of the user that can be shown to anyone.
+func (u *User) Public() PublicUser {
+	return PublicUser{
+		ID:          u.ID,
+		Username:    u.Username,
+		DisplayName: u.DisplayName,
+		AvatarID:    u.AvatarID,
+		CreatedAt:   u.CreatedAt,
+	}
+}
+
+// PublicUser is the projection of a user that can be shown to anyone. It
+// never includes the email address.
+type PublicUser struct {
+	ID          int64       `json:"id"`
+	Username    string      `json:"username"`
+	DisplayName string      `json:"display_name"`
+	AvatarID    *int64      `json:"-"`
+	Avatar      *Attachment `json:"avatar,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// Profile is the public profile of a user.
+type Profile struct {
+	PublicUser
+	Bio            string   `json:"bio"`
+	Links          []string `json:"links"`
+	IsPrivate      bool     `json:"is_private"`
+	FollowerCount  int64    `json:"follower_count"`
+	FollowingCount int64    `json:"following_count"`
+	PostCount      int64    `json:"post_count"`
+}
+
+// UpdateProfileRequest changes the profile of the current user. Fields left
+// out are kept; an avatar_id of 0 removes the avatar.
+type UpdateProfileRequest struct {
+	DisplayName *string   `json:"display_name" validate:"omitempty,max=50"`
+	Bio         *string   `json:"bio" validate:"omitempty,max=300"`
+	Links       *[]string `json:"links" validate:"omitempty,max=5,unique,dive,http_url,max=200"`
+	AvatarID    *int64    `json:"avatar_id" validate:"omitempty,min=0"`
+	IsPrivate   *bool     `json:"is_private"`
+}
+
 type CreatePostRequest struct {
-	Title   string   `json:"title" validate:"required,min=3,max=100"`
-	Content string   `json:"content" validate:"required,min=10,max=1000"`
-	Tags    []string `json:"tags" validate:"required,min=1,max=5"`
+	Title         string             `json:"title" validate:"required,min=3,max=100"`
+	Content       string             `json:"content" validate:"required,min=10,max=1000"`
+	Tags          []string           `json:"tags" validate:"required,min=1,max=5"`
+	Visibility    PostVisibility     `json:"visibility" validate:"omitempty,oneof=public followers private"`
+	AttachmentIDs []int64            `json:"attachment_ids" validate:"omitempty,max=4,unique"`
+	Poll          *CreatePollRequest `json:"poll,omitempty"`
+}
+
+type CreatePollRequest struct {
+	Options           []string              `json:"options" validate:"required,min=2,max=10,unique,dive,required,max=100"`
+	MultipleChoice    bool                  `json:"multiple_choice"`
+	ResultsVisibility PollResultsVisibility `json:"results_visibility" validate:"omitempty,oneof=always after_vote after_close"`
+	ClosesAt          *time.Time            `json:"closes_at"`
 }
+
+type VotePollRequest struct {
+	OptionIDs []int64 `json:"option_ids" validate:"required,min=1,max=10,unique"`
+}
+
 type UpdatePostRequest struct {
-	Title   *string   `json:"title" validate:"omitempty,max=100"`
-	Content *string   `json:"content" validate:"omitempty,max=1000"`
-	Tags    *[]string `json:"tags" validate:"omitempty,max=5"`
+	Title      *string         `json:"title" validate:"omitempty,max=100"`
+	Content    *string         `json:"content" validate:"omitempty,max=1000"`
+	Tags       *[]string       `json:"tags" validate:"omitempty,max=5"`
+	Visibility *PostVisibility `json:"visibility" validate:"omitempty,oneof=public followers private"`
 }
 
 type RegisterUserRequest struct {
@@ -100,22 +299,148 @@ type RegisterUserRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=8"`
 }
+type CreateRepostRequest struct {
+	Quote *string `json:"quote" validate:"omitempty,min=1,max=1000"`
+}
+
 type CreateCommentRequest struct {
-	Content string `json:"content" validate:"required,min=1,max=500"`
+	Content       string  `json:"content" validate:"required,min=1,max=500"`
+	AttachmentIDs []int64 `json:"attachment_ids" validate:"omitempty,max=4,unique"`
+}
+
+// FollowEventKind is the change recorded by a FollowEvent.
+type FollowEventKind string
+
+const (
+	FollowEventFollow   FollowEventKind = "follow"
+	FollowEventUnfollow FollowEventKind = "unfollow"
+)
+
+// FollowEvent records that FollowerID started or stopped following UserID.
+type FollowEvent struct {
+	ID         int64           `json:"id"`
+	UserID     int64           `json:"user_id"`
+	FollowerID int64           `json:"follower_id"`
+	Kind       FollowEventKind `json:"kind"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// FollowStatus is the outcome of a follow: private accounts turn follows into
+// requests that the account owner has to approve.
+type FollowStatus string
+
+const (
+	FollowStatusFollowing FollowStatus = "following"
+	FollowStatusRequested FollowStatus = "requested"
+)
+
+// FollowRequest is a pending request to follow a private account.
+type FollowRequest struct {
+	User        PublicUser `json:"user"`
+	RequestedAt time.Time  `json:"requested_at"`
+}
+
+type FollowRequestsResponse struct {
+	Items      []*FollowRequest      `json:"items"`
+	Pagination *CursorPaginationInfo `json:"pagination"`
+}
+
+// UserListEntry is a user in a block or mute list, with the time they were
+// added to it.
+type UserListEntry struct {
+	User  PublicUser `json:"user"`
+	Since time.Time  `json:"since"`
 }
 
-type FollowUnfollowRequest struct {
-	UserID int64 `json:"user_id"`
+type UserListResponse struct {
+	Items      []*UserListEntry      `json:"items"`
+	Pagination *CursorPaginationInfo `json:"pagination"`
 }
 
+// FollowSuggestion is an account suggested to follow, with the signals it was
+// ranked on.
+type FollowSuggestion struct {
+	User           PublicUser `json:"user"`
+	MutualCount    int        `json:"mutual_count"`
+	SharedTagCount int        `json:"shared_tag_count"`
+}
+
+type SuggestionsResponse struct {
+	Items []*FollowSuggestion `json:"items"`
+}
+
+// FollowEntry is a user in a follower or following list. FollowsYou and
+// YouFollow are relative to the viewer and are false for anonymous viewers.
+type FollowEntry struct {
+	User       PublicUser `json:"user"`
+	FollowedAt time.Time  `json:"followed_at"`
+	FollowsYou bool       `json:"follows_you"`
+	YouFollow  bool       `json:"you_follow"`
+}
+
+type FollowListResponse struct {
+	Items      []*FollowEntry        `json:"items"`
+	Pagination *CursorPaginationInfo `json:"pagination"`
+}
+
+// FeedItem is a post in a feed, along with the repost that put it there. ID is
+// only set in the home feed, where it is an opaque identifier of the entry
+// that orders with its position in the feed.
 type FeedItem struct {
-	Post   *Post `json:"post"`
-	Author *User `json:"author"`
+	ID             string      `json:"id,omitempty"`
+	Post           *Post       `json:"post"`
+	Author         *PublicUser `json:"author"`
+	LikeCount      int64       `json:"like_count"`
+	LikedByMe      bool        `json:"liked_by_me"`
+	BookmarkedByMe bool        `json:"bookmarked_by_me"`
+	BookmarkedAt   *time.Time  `json:"bookmarked_at,omitempty"`
+	Repost         *Repost     `json:"repost,omitempty"`
+	RepostedBy     *PublicUser `json:"reposted_by,omitempty"`
+	// Score is the rank of the item in the ranked feed.
+	Score float64 `json:"-"`
+}
+
+// TimelineEntry is a post or repost fanned out to the home timelines of the
+// followers of ActorID. Entries with the same DedupeKey are shown once.
+type TimelineEntry struct {
+	PostID     int64     `json:"post_id"`
+	RepostID   *int64    `json:"repost_id,omitempty"`
+	ActorID    int64     `json:"actor_id"`
+	DedupeKey  string    `json:"dedupe_key"`
+	ActivityAt time.Time `json:"activity_at"`
 }
 
+// TrendWindow is the sliding time window trends are computed over.
+type TrendWindow string
+
+const (
+	TrendWindowDay  TrendWindow = "24h"
+	TrendWindowWeek TrendWindow = "7d"
+)
+
+// TrendingTag is a tag used in recent public posts. Each author and each
+// account engaging with a post is counted once.
+type TrendingTag struct {
+	Tag         string `json:"tag"`
+	PostCount   int    `json:"post_count"`
+	AuthorCount int    `json:"author_count"`
+}
+
+// ExploreResponse lists the trending posts and tags of a window as of
+// ComputedAt, which is nil until the window was first aggregated.
+type ExploreResponse struct {
+	Window     TrendWindow    `json:"window"`
+	Posts      []*FeedItem    `json:"posts"`
+	Tags       []*TrendingTag `json:"tags"`
+	ComputedAt *time.Time     `json:"computed_at,omitempty"`
+}
+
+// FeedResponse is a page of the home feed. Ranked feeds are paged with
+// NextCursor, which is empty on the last page.
 type FeedResponse struct {
 	Items      []*FeedItem     `json:"items"`
 	Pagination *PaginationInfo `json:"pagination"`
+	NextCursor string          `json:"next_cursor,omitempty"`
 }
 
 type PaginationInfo struct {
@@ -127,7 +452,368 @@ type PaginationInfo struct {
 	HasPrevious  bool  `json:"has_previous"`
 }
 
+// FeedMode selects how the home feed is ordered.
+type FeedMode string
+
+const (
+	FeedModeChronological FeedMode = "chronological"
+	FeedModeRanked        FeedMode = "ranked"
+)
+
 type FeedRequest struct {
-	Page     int `json:"page" validate:"min=1"`
-	PageSize int `json:"page_size" validate:"min=1,max=50"`
+	Page     int      `json:"page" validate:"min=1"`
+	PageSize int      `json:"page_size" validate:"min=1,max=50"`
+	Mode     FeedMode `json:"mode" validate:"omitempty,oneof=chronological ranked"`
+	SinceID  string   `json:"since_id"`
+	MaxID    string   `json:"max_id"`
+	Cursor   string   `json:"cursor"`
+}
+
+// FeedPosition is the place of an entry in the chronological home feed: its
+// activity time and post, encoded in FeedItem.ID.
+type FeedPosition struct {
+	ActivityAt time.Time
+	PostID     int64
+}
+
+// RankedFeedPosition is the place of an entry in the ranked home feed as
+// scored at AsOf: entries rank by Score, then by activity time and post.
+type RankedFeedPosition struct {
+	AsOf       time.Time
+	Score      float64
+	ActivityAt time.Time
+	PostID     int64
+}
+
+// FeedNewItemsResponse reports how many home feed entries are newer than the
+// entry a client last saw.
+type FeedNewItemsResponse struct {
+	Count int64 `json:"count"`
+}
+
+// FeedRanking configures the ranked feed. Candidates are the timeline entries
+// of the last Window. Each is scored as the weighted sum of its recency, which
+// halves every HalfLife, and the logarithms of its likes and comments, of the
+// viewer's recent interactions with its author and with its tags.
+type FeedRanking struct {
+	Window           time.Duration
+	HalfLife         time.Duration
+	RecencyWeight    float64
+	EngagementWeight float64
+	AuthorWeight     float64
+	TagWeight        float64
+}
+
+// CursorPaginationInfo describes a keyset paginated page. NextCursor is empty
+// when there are no more items.
+type CursorPaginationInfo struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	Limit      int    `json:"limit"`
+	HasMore    bool   `json:"has_more"`
+}
+
+type CursorRequest struct {
+	Cursor string `json:"cursor"`
+	Limit  int    `json:"limit" validate:"min=1,max=50"`
+}
+
+type LikeResponse struct {
+	PostID    int64 `json:"post_id"`
+	Liked     bool  `json:"liked"`
+	LikeCount int64 `json:"like_count"`
+}
+
+type BookmarkResponse struct {
+	PostID     int64 `json:"post_id"`
+	Bookmarked bool  `json:"bookmarked"`
+}
+
+type BookmarksResponse struct {
+	Items      []*FeedItem           `json:"items"`
+	Pagination *CursorPaginationInfo `json:"pagination"`
+}
+
+type MentionsResponse struct {
+	Items      []*FeedItem           `json:"items"`
+	Pagination *CursorPaginationInfo `json:"pagination"`
+}
+
+// NotificationKind is what a notification tells its recipient about.
+type NotificationKind string
+
+const (
+	// NotificationFollow: the actor started following the recipient.
+	NotificationFollow NotificationKind = "follow"
+	// NotificationFollowRequest: the actor asked to follow the private
+	// account of the recipient.
+	NotificationFollowRequest NotificationKind = "follow_request"
+	// NotificationComment: the actor commented on a post of the recipient.
+	NotificationComment NotificationKind = "comment"
+	// NotificationReply: the actor commented on a post the recipient
+	// commented on as well.
+	NotificationReply NotificationKind = "reply"
+	// NotificationMention: the actor mentioned the recipient in a post or a
+	// comment.
+	NotificationMention NotificationKind = "mention"
+	// NotificationLike: the actor liked a post of the recipient.
+	NotificationLike NotificationKind = "like"
+)
+
+// Notification tells UserID about an action of ActorID. It is what is
+// pushed over the notification stream as it happens.
+type Notification struct {
+	ID        int64            `json:"id"`
+	UserID    int64            `json:"-"`
+	ActorID   int64            `json:"-"`
+	Kind      NotificationKind `json:"kind"`
+	PostID    *int64           `json:"post_id,omitempty"`
+	CommentID *int64           `json:"comment_id,omitempty"`
+	Actor     *PublicUser      `json:"actor"`
+	CreatedAt time.Time        `json:"created_at"`
+	ReadAt    *time.Time       `json:"read_at"`
+}
+
+// NotificationGroup is an entry of the notification inbox. Likes of the same
+// post and follows of the same day are grouped while they are unread ("alice
+// and 4 others liked your post"); other notifications make up a group of
+// their own. ID is
+// the id of the latest notification of the group, and Actors holds the most
+// recent of ActorCount actors.
+type NotificationGroup struct {
+	ID         int64            `json:"id"`
+	Kind       NotificationKind `json:"kind"`
+	PostID     *int64           `json:"post_id,omitempty"`
+	CommentID  *int64           `json:"comment_id,omitempty"`
+	Actors     []*PublicUser    `json:"actors"`
+	ActorIDs   []int64          `json:"-"`
+	ActorCount int64            `json:"actor_count"`
+	Summary    string           `json:"summary"`
+	Read       bool             `json:"read"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+type NotificationsResponse struct {
+	Items       []*NotificationGroup  `json:"items"`
+	UnreadCount int64                 `json:"unread_count"`
+	Pagination  *CursorPaginationInfo `json:"pagination"`
+}
+
+// NotificationKinds lists every kind of notification.
+var NotificationKinds = []NotificationKind{
+	NotificationFollow,
+	NotificationFollowRequest,
+	NotificationComment,
+	NotificationReply,
+	NotificationMention,
+	NotificationLike,
+}
+
+// NotificationDelivery is how a user hears about notifications of a kind.
+// Notifications always show up in the app; the other deliveries email them
+// as well.
+type NotificationDelivery string
+
+const (
+	NotificationDeliveryInApp NotificationDelivery = "in_app"
+	// NotificationDeliveryEmail emails each notification as it happens.
+	NotificationDeliveryEmail NotificationDelivery = "email"
+	// NotificationDeliveryDaily and NotificationDeliveryWeekly collect the
+	// notifications still unread in a digest email.
+	NotificationDeliveryDaily  NotificationDelivery = "daily"
+	NotificationDeliveryWeekly NotificationDelivery = "weekly"
+)
+
+// NotificationPreferences are the notification settings of a user. Delivery
+// holds the delivery of every kind of notification. No email is sent during
+// the quiet hours, from QuietHoursStart to QuietHoursEnd ("22:00" to
+// "07:00"), in the user's Timezone; emails due in between go out when they
+// end.
+type NotificationPreferences struct {
+	Delivery        map[NotificationKind]NotificationDelivery `json:"delivery"`
+	Timezone        string                                    `json:"timezone"`
+	QuietHoursStart *string                                   `json:"quiet_hours_start"`
+	QuietHoursEnd   *string                                   `json:"quiet_hours_end"`
+}
+
+// UpdateNotificationPreferencesRequest changes the given notification
+// settings. Quiet hours are set by giving both of their ends, and turned off
+// by giving both as empty strings.
+type UpdateNotificationPreferencesRequest struct {
+	Delivery        map[NotificationKind]NotificationDelivery `json:"delivery" validate:"omitempty,dive,keys,oneof=follow follow_request comment reply mention like,endkeys,oneof=in_app email daily weekly"`
+	Timezone        *string                                   `json:"timezone" validate:"omitempty,timezone"`
+	QuietHoursStart *string                                   `json:"quiet_hours_start" validate:"required_with=QuietHoursEnd,omitempty,len=0|datetime=15:04"`
+	QuietHoursEnd   *string                                   `json:"quiet_hours_end" validate:"required_with=QuietHoursStart,omitempty,len=0|datetime=15:04"`
+}
+
+// NotificationEmail is a batch of notifications emailed to a user at once:
+// those due for immediate email, or the notifications of a digest. Attempts
+// counts the attempts to email the most tried of them, this one included.
+type NotificationEmail struct {
+	UserID        int64
+	Delivery      NotificationDelivery
+	Notifications []*Notification
+	Attempts      int
+}
+
+// ActorKeys is the key pair a local account signs its ActivityPub deliveries
+// with, in PEM form.
+type ActorKeys struct {
+	UserID        int64
+	PublicKeyPEM  string
+	PrivateKeyPEM string
+}
+
+// RemoteActor is an account on another ActivityPub server, stored as the
+// user UserID. Username is the account's name on its server.
+type RemoteActor struct {
+	UserID       int64
+	URI          string
+	KeyID        string
+	PublicKeyPEM string
+	Inbox        string
+	SharedInbox  string
+	ProfileURL   string
+	Username     string
+	Domain       string
+	DisplayName  string
+	Bio          string
+	IsPrivate    bool
+	FetchedAt    time.Time
+}
+
+// Delivery is an activity queued for delivery to the inbox of a remote
+// server, signed by the sender.
+type Delivery struct {
+	ID       int64
+	SenderID int64
+	Inbox    string
+	Activity []byte
+	Attempts int
+}
+
+// WebhookEvent names an event webhooks can subscribe to.
+type WebhookEvent string
+
+const (
+	WebhookUserRegistered WebhookEvent = "user.registered"
+	WebhookUserActivated  WebhookEvent = "user.activated"
+	WebhookPostCreated    WebhookEvent = "post.created"
+	WebhookPostUpdated    WebhookEvent = "post.updated"
+	WebhookPostDeleted    WebhookEvent = "post.deleted"
+	WebhookCommentCreated WebhookEvent = "comment.created"
+	WebhookFollowCreated  WebhookEvent = "follow.created"
+	WebhookFollowDeleted  WebhookEvent = "follow.deleted"
+)
+
+// Webhook is a URL events are POSTed to, signed with Secret. It receives
+// the given Events, or every event when there are none. The secret is only
+// shown when the webhook is created.
+type Webhook struct {
+	ID          int64          `json:"id"`
+	URL         string         `json:"url"`
+	Secret      string         `json:"secret,omitempty"`
+	Events      []WebhookEvent `json:"events"`
+	Description string         `json:"description"`
+	Active      bool           `json:"active"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+type CreateWebhookRequest struct {
+	URL         string         `json:"url" validate:"required,http_url,max=2000"`
+	Events      []WebhookEvent `json:"events" validate:"omitempty,unique,dive,oneof=user.registered user.activated post.created post.updated post.deleted comment.created follow.created follow.deleted"`
+	Description string         `json:"description" validate:"max=200"`
+}
+
+type UpdateWebhookRequest struct {
+	URL         *string         `json:"url" validate:"omitempty,http_url,max=2000"`
+	Events      *[]WebhookEvent `json:"events" validate:"omitempty,unique,dive,oneof=user.registered user.activated post.created post.updated post.deleted comment.created follow.created follow.deleted"`
+	Description *string         `json:"description" validate:"omitempty,max=200"`
+	Active      *bool           `json:"active"`
+}
+
+// WebhookPayload is the body of a webhook delivery. ID identifies the
+// event and is the same for every webhook it is delivered to, and on
+// redeliveries.
+type WebhookPayload struct {
+	ID        string       `json:"id"`
+	Event     WebhookEvent `json:"event"`
+	CreatedAt time.Time    `json:"created_at"`
+	Data      any          `json:"data"`
+}
+
+// WebhookDeliveryStatus is the state of a webhook delivery.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending    WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivering WebhookDeliveryStatus = "delivering"
+	WebhookDeliverySucceeded  WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed     WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is the delivery of an event to a webhook, as kept in the
+// delivery log. The response fields describe the latest attempt; Error is
+// set when it got no response. Pending deliveries are retried at
+// NextAttemptAt.
+type WebhookDelivery struct {
+	ID             int64                 `json:"id"`
+	WebhookID      int64                 `json:"webhook_id"`
+	EventID        string                `json:"event_id"`
+	Event          WebhookEvent          `json:"event"`
+	Payload        json.RawMessage       `json:"payload" swaggertype:"object"`
+	RedeliveryOf   *int64                `json:"redelivery_of,omitempty"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty"`
+	ResponseStatus *int                  `json:"response_status"`
+	ResponseBody   *string               `json:"response_body"`
+	Error          *string               `json:"error"`
+	DurationMS     *int                  `json:"duration_ms"`
+	CreatedAt      time.Time             `json:"created_at"`
+	CompletedAt    *time.Time            `json:"completed_at"`
+	// URL and Secret of the webhook, set on claimed deliveries.
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+type WebhookDeliveriesResponse struct {
+	Items      []*WebhookDelivery    `json:"items"`
+	Pagination *CursorPaginationInfo `json:"pagination"`
+}
+
+// OutboxKind is what an outbox message asks to be done.
+type OutboxKind string
+
+const (
+	// OutboxEmail messages hold an OutboxEmailJob.
+	OutboxEmail OutboxKind = "email"
+	// OutboxEvent messages hold the WebhookPayload of an event.
+	OutboxEvent OutboxKind = "event"
+)
+
+// OutboxMessage is a side effect of a change, stored with the change and
+// dispatched in the background. Attempts counts the dispatches so far,
+// including the current one. Error and CreatedAt are only set on messages
+// listed as failed.
+type OutboxMessage struct {
+	ID        int64           `json:"id"`
+	Kind      OutboxKind      `json:"kind"`
+	Payload   json.RawMessage `json:"payload" swaggertype:"object"`
+	Attempts  int             `json:"attempts"`
+	Error     *string         `json:"error"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type OutboxMessagesResponse struct {
+	Items      []*OutboxMessage      `json:"items"`
+	Pagination *CursorPaginationInfo `json:"pagination"`
+}
+
+// OutboxEmailJob is an email to send with a mailer template.
+type OutboxEmailJob struct {
+	Template string         `json:"template"`
+	Username string         `json:"username"`
+	Email    string         `json:"email"`
+	Data     map[string]any `json:"data"`
 }
@@ -19,21 +19,24 @@ import (
 type AuthService struct {
 	store          store.Storage
 	mailExpiration time.Duration
-	mailer mailer.Client
 	frontendURL string
 	logger *zap.SugaredLogger
 	config config.Config
+	outbox *OutboxDispatcher
 	}
 
-func NewAuthService(store store.Storage, mailExpiration time.Duration, mailer mailer.Client, config config.Config, logger *zap.SugaredLogger) *AuthService {
+// NewAuthService returns a service writing the invitation emails and the
+// events of users to the outbox, which the dispatcher sends once they are
+// committed.
+func NewAuthService(store store.Storage, mailExpiration time.Duration, config config.Config, outbox *OutboxDispatcher, logger *zap.SugaredLogger) *AuthService {
 
 	return &AuthService{
 		store:          store,
 		mailExpiration: mailExpiration,
-		mailer:         mailer,
 		frontendURL:    config.FrontendURL,
 		logger:         logger,
 		config:         config,
+		outbox:         outbox,
 	}
 }
 
@@ -70,27 +73,26 @@ func (s *AuthService) RegisterUser(ctx context.Context, req models.RegisterUserR
 
 	fmt.Printf("Hashed token for storage: %s (length: %d)\n", hashedToken, len(hashedToken))
 
-	err = s.store.Auth.CreateAndInvite(ctx, user, hashedToken, s.mailExpiration)
-	if err != nil {
-		return nil, "", err
-	}
-	// sending email to user
-	isProdEnv := s.config.Env == "prod"
-	s.logger.Info("Sending email to user", zap.String("email", user.Email), zap.String("username", user.Username), zap.String("frontendURL", s.frontendURL), zap.String("hashedToken", hashedToken), zap.Bool("isProdEnv", isProdEnv))
-
-	vars := map[string]interface{}{
-		"Username": user.Username,
-		"ActivationURL": fmt.Sprintf("%s/activate/%s", s.frontendURL, hashedToken),
-	}
-
-	if err := s.mailer.Send(mailer.UserWelcomeTemplate, user.Username, user.Email, vars, !isProdEnv); err != nil {
-		// SAGA PATTERN
-		if err := s.store.Auth.DeleteInvitationToken(ctx, hashedToken); err != nil {
-			s.logger.Error("Error deleting invitation token", zap.Error(err))
+	// The invitation email and the event are queued with the user, so they
+	// are sent in the background and only for users that were created.
+	err = s.store.Auth.CreateAndInvite(ctx, user, hashedToken, s.mailExpiration, func(user *models.User) ([]*models.OutboxMessage, error) {
+		invitation, err := newOutboxEmail(mailer.UserWelcomeTemplate, user.Username, user.Email, map[string]any{
+			"Username":      user.Username,
+			"ActivationURL": fmt.Sprintf("%s/activate/%s", s.frontendURL, hashedToken),
+		})
+		if err != nil {
+			return nil, err
+		}
+		registered, err := newOutboxEvent(models.WebhookUserRegistered, user)
+		if err != nil {
+			return nil, err
 		}
-		s.logger.Error("Error sending email", zap.Error(err))
+		return []*models.OutboxMessage{invitation, registered}, nil
+	})
+	if err != nil {
 		return nil, "", err
 	}
+	s.outbox.Notify()
 
 	return user, plainToken, nil
 }
@@ -144,13 +146,15 @@ func (s *AuthService) ActivateUser(ctx context.Context, token string) error {
 		return apperrors.ErrUserAlreadyActivated
 	}
 
-	if err := s.store.Auth.ActivateUser(ctx, user.ID); err != nil {
+	user.Activated = true
+	activated, err := newOutboxEvent(models.WebhookUserActivated, user)
+	if err != nil {
 		return err
 	}
 
-	if err := s.store.Auth.DeleteInvitationToken(ctx, hashedToken); err != nil {
+	if err := s.store.Auth.ActivateUser(ctx, user.ID, hashedToken, []*models.OutboxMessage{activated}); err != nil {
 		return err
 	}
-
+	s.outbox.Notify()
 	return nil
 }
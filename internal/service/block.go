@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+)
+
+type BlockService struct {
+	store store.Storage
+	media *MediaService
+}
+
+func NewBlockService(store store.Storage, media *MediaService) *BlockService {
+	return &BlockService{store: store, media: media}
+}
+
+// Block blocks targetID for the user in ctx. Blocking removes the follow
+// relations between the two users in both directions.
+func (s *BlockService) Block(ctx context.Context, targetID int64) error {
+	userID, err := s.checkTarget(ctx, targetID)
+	if err != nil {
+		return err
+	}
+	return s.store.Block.Block(ctx, userID, targetID)
+}
+
+func (s *BlockService) Unblock(ctx context.Context, targetID int64) error {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return apperrors.ErrUserIDNotFound
+	}
+	return s.store.Block.Unblock(ctx, userID, targetID)
+}
+
+// Mute hides the posts and reposts of targetID from the feed of the user in
+// ctx without them being told.
+func (s *BlockService) Mute(ctx context.Context, targetID int64) error {
+	userID, err := s.checkTarget(ctx, targetID)
+	if err != nil {
+		return err
+	}
+	return s.store.Block.Mute(ctx, userID, targetID)
+}
+
+func (s *BlockService) Unmute(ctx context.Context, targetID int64) error {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return apperrors.ErrUserIDNotFound
+	}
+	return s.store.Block.Unmute(ctx, userID, targetID)
+}
+
+// GetBlocked returns the users blocked by the user in ctx, most recent first.
+func (s *BlockService) GetBlocked(ctx context.Context, req models.CursorRequest) (*models.UserListResponse, error) {
+	return s.list(ctx, req, s.store.Block.GetBlocked)
+}
+
+// GetMuted returns the users muted by the user in ctx, most recent first.
+func (s *BlockService) GetMuted(ctx context.Context, req models.CursorRequest) (*models.UserListResponse, error) {
+	return s.list(ctx, req, s.store.Block.GetMuted)
+}
+
+func (s *BlockService) list(ctx context.Context, req models.CursorRequest, fetch func(context.Context, int64, *time.Time, int64, int) ([]*models.UserListEntry, error)) (*models.UserListResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, apperrors.ErrUserIDNotFound
+	}
+
+	if err := Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var before *time.Time
+	var beforeID int64
+	if req.Cursor != "" {
+		t, id, err := utils.DecodeCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		before, beforeID = &t, id
+	}
+
+	entries, err := fetch(ctx, userID, before, beforeID, req.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	pagination := &models.CursorPaginationInfo{Limit: req.Limit}
+	if len(entries) > req.Limit {
+		entries = entries[:req.Limit]
+		last := entries[len(entries)-1]
+		pagination.HasMore = true
+		pagination.NextCursor = utils.EncodeCursor(last.Since, last.User.ID)
+	}
+
+	users := make([]*models.PublicUser, 0, len(entries))
+	for _, e := range entries {
+		users = append(users, &e.User)
+	}
+	if err := s.media.attachAvatars(ctx, users...); err != nil {
+		return nil, err
+	}
+
+	return &models.UserListResponse{
+		Items:      entries,
+		Pagination: pagination,
+	}, nil
+}
+
+// checkTarget returns the id of the user in ctx after checking that targetID
+// is another existing user.
+func (s *BlockService) checkTarget(ctx context.Context, targetID int64) (int64, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return 0, apperrors.ErrUserIDNotFound
+	}
+	if userID == targetID {
+		return 0, apperrors.ErrSelfRelation
+	}
+
+	if _, err := s.store.User.GetByID(ctx, targetID); err != nil {
+		if err == store.ErrNotFound {
+			return 0, apperrors.ErrUserNotFound
+		}
+		return 0, err
+	}
+	return userID, nil
+}
+
+// isBlocked reports whether the viewer in ctx and userID blocked each other
+// in either direction. Anonymous viewers are never blocked.
+func isBlocked(ctx context.Context, st store.Storage, userID int64) (bool, error) {
+	viewerID, ok := utils.GetUserID(ctx)
+	if !ok || viewerID == userID {
+		return false, nil
+	}
+	return st.Block.IsBlocked(ctx, viewerID, userID)
+}
@@ -10,11 +10,14 @@ import (
 )
 
 type CommentService struct {
-	store store.Storage
+	store         store.Storage
+	media         *MediaService
+	notifications *NotificationService
+	webhooks      *WebhookService
 }
 
-func NewCommentService(store store.Storage) *CommentService {
-	return &CommentService{store: store}
+func NewCommentService(store store.Storage, media *MediaService, notifications *NotificationService, webhooks *WebhookService) *CommentService {
+	return &CommentService{store: store, media: media, notifications: notifications, webhooks: webhooks}
 }
 
 func (s *CommentService) CreateComment(ctx context.Context, req *models.CreateCommentRequest) (*models.Comment, error) {
@@ -35,24 +38,108 @@ func (s *CommentService) CreateComment(ctx context.Context, req *models.CreateCo
 		return nil, apperrors.ErrPostNotFound
 	}
 
+	// Commenting is only allowed on posts the commenter can see
+	visible, err := canViewPost(ctx, s.store, post)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, apperrors.ErrPostNotFound
+	}
+
 	// Create comment
 	comment := &models.Comment{
 		PostID:  post.ID,
 		UserID:  userID,
 		Content: req.Content,
 	}
+	if err := setCommentMentions(ctx, s.store, comment); err != nil {
+		return nil, err
+	}
+	attachments, err := s.media.pendingAttachments(ctx, req.AttachmentIDs)
+	if err != nil {
+		return nil, err
+	}
 
-	createdComment, err := s.store.Comment.Create(ctx, comment)
+	// The comment is stored with its mentions, attachments and
+	// comment.created event at once.
+	created := eventMessages(models.WebhookCommentCreated, func(c *models.Comment) *models.Comment {
+		for _, a := range attachments {
+			a.CommentID = &c.ID
+		}
+		c.Attachments = attachments
+		return renderComment(c)
+	})
+	createdComment, err := s.store.Comment.Create(ctx, comment, req.AttachmentIDs, created)
 	if err != nil {
 		return nil, err
 	}
+	s.webhooks.published()
+
+	s.notifications.notifyComment(ctx, post, createdComment)
+
+	if err := s.media.attachCommentAttachments(ctx, []*models.Comment{createdComment}); err != nil {
+		return nil, err
+	}
 
-	return createdComment, nil
+	return renderComment(createdComment), nil
 }
 
 func (s *CommentService) GetCommentsByPostID(ctx context.Context, postID int64) ([]*models.Comment, error) {
 	if postID <= 0 {
 		return nil, apperrors.NewBadRequestError("PostId should be Valid")
 	}
-	return s.store.Comment.GetByPostID(ctx, postID)
+
+	post, err := s.store.Post.GetByID(ctx, postID)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return nil, apperrors.ErrPostNotFound
+		}
+		return nil, err
+	}
+	visible, err := canViewPost(ctx, s.store, post)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, apperrors.ErrPostNotFound
+	}
+
+	comments, err := s.store.Comment.GetByPostID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if comments, err = withoutBlockedComments(ctx, s.store, comments); err != nil {
+		return nil, err
+	}
+	if err := hydrateComments(ctx, s.store, s.media, comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// withoutBlockedComments drops the comments written by users who blocked the
+// viewer in ctx or were blocked by them.
+func withoutBlockedComments(ctx context.Context, st store.Storage, comments []*models.Comment) ([]*models.Comment, error) {
+	viewerID, ok := utils.GetUserID(ctx)
+	if !ok || len(comments) == 0 {
+		return comments, nil
+	}
+
+	authorIDs := make([]int64, 0, len(comments))
+	for _, c := range comments {
+		authorIDs = append(authorIDs, c.UserID)
+	}
+	blocked, err := st.Block.BlockedAmong(ctx, viewerID, authorIDs)
+	if err != nil || len(blocked) == 0 {
+		return comments, err
+	}
+
+	visible := make([]*models.Comment, 0, len(comments))
+	for _, c := range comments {
+		if !blocked[c.UserID] {
+			visible = append(visible, c)
+		}
+	}
+	return visible, nil
 }
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+)
+
+type EngagementService struct {
+	store         store.Storage
+	media         *MediaService
+	notifications *NotificationService
+}
+
+func NewEngagementService(store store.Storage, media *MediaService, notifications *NotificationService) *EngagementService {
+	return &EngagementService{store: store, media: media, notifications: notifications}
+}
+
+// ToggleLike likes or unlikes the post for the user in ctx. The post is
+// expected to come from postsContextMiddleware, which already checked that the
+// user can see it.
+func (s *EngagementService) ToggleLike(ctx context.Context, post *models.Post) (*models.LikeResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, apperrors.ErrUserIDNotFound
+	}
+
+	liked, count, err := s.store.Engagement.ToggleLike(ctx, userID, post.ID)
+	if err != nil {
+		return nil, err
+	}
+	if liked {
+		s.notifications.notifyLike(ctx, userID, post)
+	}
+
+	return &models.LikeResponse{
+		PostID:    post.ID,
+		Liked:     liked,
+		LikeCount: count,
+	}, nil
+}
+
+// ToggleBookmark bookmarks or un-bookmarks the post for the user in ctx.
+// Bookmarks are private and never counted publicly.
+func (s *EngagementService) ToggleBookmark(ctx context.Context, post *models.Post) (*models.BookmarkResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, apperrors.ErrUserIDNotFound
+	}
+
+	bookmarked, err := s.store.Engagement.ToggleBookmark(ctx, userID, post.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BookmarkResponse{
+		PostID:     post.ID,
+		Bookmarked: bookmarked,
+	}, nil
+}
+
+func (s *EngagementService) GetBookmarks(ctx context.Context, req models.CursorRequest) (*models.BookmarksResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, apperrors.ErrUserIDNotFound
+	}
+
+	if err := Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var before *time.Time
+	var beforeID int64
+	if req.Cursor != "" {
+		t, id, err := utils.DecodeCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		before, beforeID = &t, id
+	}
+
+	// Fetch one extra row to find out whether another page exists
+	items, err := s.store.Engagement.GetBookmarks(ctx, userID, before, beforeID, req.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	pagination := &models.CursorPaginationInfo{Limit: req.Limit}
+	if len(items) > req.Limit {
+		items = items[:req.Limit]
+		last := items[len(items)-1]
+		pagination.HasMore = true
+		pagination.NextCursor = utils.EncodeCursor(*last.BookmarkedAt, last.Post.ID)
+	}
+
+	if err := hydrateFeedItems(ctx, s.store, s.media, items); err != nil {
+		return nil, err
+	}
+
+	return &models.BookmarksResponse{
+		Items:      items,
+		Pagination: pagination,
+	}, nil
+}
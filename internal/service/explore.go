@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"go.uber.org/zap"
+)
+
+// trendingPollInterval is how often the trending worker checks whether a
+// window is due to be recomputed.
+const trendingPollInterval = time.Minute
+
+// trendWindows maps the trend windows to the activity they cover.
+var trendWindows = map[models.TrendWindow]time.Duration{
+	models.TrendWindowDay:  24 * time.Hour,
+	models.TrendWindowWeek: 7 * 24 * time.Hour,
+}
+
+type ExploreService struct {
+	store store.Storage
+	media *MediaService
+}
+
+func NewExploreService(store store.Storage, media *MediaService) *ExploreService {
+	return &ExploreService{store: store, media: media}
+}
+
+// GetExplore returns up to limit trending posts and tags of the window, as
+// last aggregated by the TrendingWorker. Posts hidden from the viewer in ctx,
+// or written by users they muted, are left out. The window defaults to the
+// last day.
+func (s *ExploreService) GetExplore(ctx context.Context, window models.TrendWindow, limit int) (*models.ExploreResponse, error) {
+	if window == "" {
+		window = models.TrendWindowDay
+	}
+	if _, ok := trendWindows[window]; !ok {
+		return nil, apperrors.ErrInvalidTrendWindow
+	}
+
+	// Anonymous viewers only see public posts of public accounts.
+	viewerID, _ := utils.GetUserID(ctx)
+
+	posts, err := s.store.Trending.GetPosts(ctx, window, viewerID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if err := hydrateFeedItems(ctx, s.store, s.media, posts); err != nil {
+		return nil, err
+	}
+
+	tags, err := s.store.Trending.GetTags(ctx, window, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	computedAt, err := s.store.Trending.LastComputed(ctx, window)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ExploreResponse{
+		Window:     window,
+		Posts:      posts,
+		Tags:       tags,
+		ComputedAt: computedAt,
+	}, nil
+}
+
+// TrendingWorker recomputes the trending posts and tags of every window in
+// the background, so serving them never aggregates engagement on the request
+// path. Instances coordinate through the database and each window is
+// recomputed once per interval.
+type TrendingWorker struct {
+	*queueWorker
+	store    store.Storage
+	interval time.Duration
+	size     int
+}
+
+// NewTrendingWorker returns a worker keeping the top size posts and tags of
+// each window, recomputed every interval.
+func NewTrendingWorker(store store.Storage, interval time.Duration, size int, logger *zap.SugaredLogger) *TrendingWorker {
+	w := &TrendingWorker{
+		store:    store,
+		interval: interval,
+		size:     size,
+	}
+	w.queueWorker = newQueueWorker("trending", 1, trendingPollInterval, logger, w.refresh)
+	return w
+}
+
+// refresh recomputes the windows that are due. It never reports more work, so
+// the worker sleeps until the next poll.
+func (w *TrendingWorker) refresh(ctx context.Context) (bool, error) {
+	for window, length := range trendWindows {
+		if _, err := w.store.Trending.Refresh(ctx, window, time.Now().Add(-length), w.interval, w.size); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
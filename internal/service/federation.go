@@ -0,0 +1,567 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"github.com/LikhithMar14/gopher-chat/pkg/activitypub"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// outboxSize is the number of recent posts listed in an outbox.
+	outboxSize = 20
+	// remoteActorTTL is how long a fetched remote account is used before it
+	// is fetched again.
+	remoteActorTTL = 24 * time.Hour
+)
+
+// apURLs builds the ids of the ActivityPub actors and objects of local
+// accounts, all served under the API URL.
+type apURLs struct {
+	base string
+}
+
+func newAPURLs(apiURL string) apURLs {
+	return apURLs{base: strings.TrimSuffix(apiURL, "/")}
+}
+
+func (u apURLs) actor(userID int64) string {
+	return u.base + "/v1/ap/users/" + strconv.FormatInt(userID, 10)
+}
+
+func (u apURLs) key(userID int64) string       { return u.actor(userID) + "#main-key" }
+func (u apURLs) inbox(userID int64) string     { return u.actor(userID) + "/inbox" }
+func (u apURLs) outbox(userID int64) string    { return u.actor(userID) + "/outbox" }
+func (u apURLs) followers(userID int64) string { return u.actor(userID) + "/followers" }
+func (u apURLs) sharedInbox() string           { return u.base + "/v1/ap/inbox" }
+
+func (u apURLs) note(postID int64) string {
+	return u.base + "/v1/ap/posts/" + strconv.FormatInt(postID, 10)
+}
+
+// activity returns a new unique id for an activity of a local account.
+func (u apURLs) activity(userID int64) string {
+	return u.actor(userID) + "/activities/" + uuid.NewString()
+}
+
+// localID returns the id of the local account or post an id under prefix
+// refers to.
+func (u apURLs) localID(id, prefix string) (int64, bool) {
+	rest, ok := strings.CutPrefix(id, u.base+prefix)
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(rest, 10, 64)
+	return n, err == nil
+}
+
+func (u apURLs) localUserID(id string) (int64, bool) { return u.localID(id, "/v1/ap/users/") }
+func (u apURLs) localPostID(id string) (int64, bool) { return u.localID(id, "/v1/ap/posts/") }
+
+// FederationService makes local accounts and their posts available to other
+// ActivityPub servers and handles the activities they send. Remote accounts
+// are stored as users, so their follows, likes and posts go through the same
+// follow graph, timelines and visibility rules as local ones.
+type FederationService struct {
+	store         store.Storage
+	client        *activitypub.Client
+	timeline      *TimelineWorker
+	deliveries    *DeliveryWorker
+	notifications *NotificationService
+	urls          apURLs
+	host          string
+	frontendURL   string
+	logger        *zap.SugaredLogger
+}
+
+// NewFederationService returns a service federating the accounts served at
+// apiURL. The host of apiURL is the domain of their WebFinger accounts.
+func NewFederationService(store store.Storage, client *activitypub.Client, timeline *TimelineWorker, deliveries *DeliveryWorker, notifications *NotificationService, apiURL, frontendURL string, logger *zap.SugaredLogger) *FederationService {
+	host := apiURL
+	if u, err := url.Parse(apiURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	return &FederationService{
+		store:         store,
+		client:        client,
+		timeline:      timeline,
+		deliveries:    deliveries,
+		notifications: notifications,
+		urls:          newAPURLs(apiURL),
+		host:          strings.ToLower(host),
+		frontendURL:   strings.TrimSuffix(frontendURL, "/"),
+		logger:        logger,
+	}
+}
+
+// WebFinger resolves an "acct:" resource to the actor of a local account.
+func (s *FederationService) WebFinger(ctx context.Context, resource string) (*activitypub.JRD, error) {
+	username, host, err := activitypub.ParseAccount(resource)
+	if err != nil {
+		return nil, apperrors.ErrInvalidInput
+	}
+	if host != s.host {
+		return nil, apperrors.ErrUserNotFound
+	}
+
+	users, err := s.store.User.GetByUsernames(ctx, []string{username})
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, apperrors.ErrUserNotFound
+	}
+
+	user := users[0]
+	return &activitypub.JRD{
+		Subject: "acct:" + user.Username + "@" + s.host,
+		Aliases: []string{s.urls.actor(user.ID)},
+		Links: []activitypub.JRDLink{
+			{Rel: "self", Type: activitypub.ContentType, Href: s.urls.actor(user.ID)},
+			{Rel: "http://webfinger.net/rel/profile-page", Type: "text/html", Href: s.profileURL(user.ID)},
+		},
+	}, nil
+}
+
+// GetActor returns the actor document of a local account, creating its key
+// pair on first use.
+func (s *FederationService) GetActor(ctx context.Context, user *models.User) (*activitypub.Actor, error) {
+	if user.Domain != "" {
+		return nil, apperrors.ErrUserNotFound
+	}
+
+	keys, err := actorKeys(ctx, s.store, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	published := user.CreatedAt
+	return &activitypub.Actor{
+		Context:                   activitypub.Context,
+		ID:                        s.urls.actor(user.ID),
+		Type:                      activitypub.TypePerson,
+		PreferredUsername:         user.Username,
+		Name:                      user.DisplayName,
+		Summary:                   html.EscapeString(user.Bio),
+		URL:                       s.profileURL(user.ID),
+		Inbox:                     s.urls.inbox(user.ID),
+		Outbox:                    s.urls.outbox(user.ID),
+		Followers:                 s.urls.followers(user.ID),
+		ManuallyApprovesFollowers: user.IsPrivate,
+		Published:                 &published,
+		Endpoints:                 &activitypub.Endpoints{SharedInbox: s.urls.sharedInbox()},
+		PublicKey: activitypub.PublicKey{
+			ID:           s.urls.key(user.ID),
+			Owner:        s.urls.actor(user.ID),
+			PublicKeyPem: keys.PublicKeyPEM,
+		},
+	}, nil
+}
+
+// GetOutbox returns the outbox of a local account, listing the Create
+// activities of its latest public posts.
+func (s *FederationService) GetOutbox(ctx context.Context, user *models.User) (*activitypub.OrderedCollection, error) {
+	if user.Domain != "" {
+		return nil, apperrors.ErrUserNotFound
+	}
+
+	total, err := s.store.Post.CountByUser(ctx, user.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.store.Post.GetPublic(ctx, user.ID, "", outboxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	outbox := &activitypub.OrderedCollection{
+		Context:      activitypub.Context,
+		ID:           s.urls.outbox(user.ID),
+		Type:         activitypub.TypeOrderedCollection,
+		TotalItems:   total,
+		OrderedItems: make([]any, 0, len(items)),
+	}
+	for _, item := range items {
+		outbox.OrderedItems = append(outbox.OrderedItems, s.createActivity(item.Post))
+	}
+	return outbox, nil
+}
+
+// GetFollowers returns the followers collection of a local account. Only its
+// size is published.
+func (s *FederationService) GetFollowers(ctx context.Context, user *models.User) (*activitypub.OrderedCollection, error) {
+	if user.Domain != "" {
+		return nil, apperrors.ErrUserNotFound
+	}
+
+	count, err := s.store.Follow.GetFollowerCount(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &activitypub.OrderedCollection{
+		Context:    activitypub.Context,
+		ID:         s.urls.followers(user.ID),
+		Type:       activitypub.TypeOrderedCollection,
+		TotalItems: count,
+	}, nil
+}
+
+// GetNote returns the Note of a post of a local account that anyone may see.
+// Other posts are reported as not found.
+func (s *FederationService) GetNote(ctx context.Context, postID int64) (*activitypub.Note, error) {
+	post, err := s.store.Post.GetByID(ctx, postID)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return nil, apperrors.ErrPostNotFound
+		}
+		return nil, err
+	}
+
+	// Notes are served to other servers, which see what anonymous viewers
+	// see.
+	visible, err := canViewPost(ctx, s.store, post)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, apperrors.ErrPostNotFound
+	}
+	author, err := s.store.User.GetByID(ctx, post.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if author.Domain != "" {
+		return nil, apperrors.ErrPostNotFound
+	}
+
+	note := s.note(post)
+	note.Context = activitypub.Context
+	return note, nil
+}
+
+// LookupAccount resolves an account such as "alice@example.com" on another
+// server with WebFinger and returns the user it is stored as, so it can be
+// followed like a local account.
+func (s *FederationService) LookupAccount(ctx context.Context, account string) (*models.PublicUser, error) {
+	username, host, err := activitypub.ParseAccount(account)
+	if err != nil {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	var userID int64
+	if host == s.host {
+		users, err := s.store.User.GetByUsernames(ctx, []string{username})
+		if err != nil {
+			return nil, err
+		}
+		if len(users) == 0 {
+			return nil, apperrors.ErrUserNotFound
+		}
+		userID = users[0].ID
+	} else {
+		actorID, err := s.client.Finger(ctx, account)
+		if err != nil {
+			s.logger.Infow("Failed to look up remote account", "account", account, "error", err)
+			return nil, apperrors.ErrRemoteAccountNotFound
+		}
+		actor, err := s.resolveActor(ctx, actorID, false)
+		if err != nil {
+			s.logger.Infow("Failed to fetch remote account", "account", account, "actor", actorID, "error", err)
+			return nil, apperrors.ErrRemoteAccountNotFound
+		}
+		userID = actor.UserID
+	}
+
+	user, err := s.store.User.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	public := user.Public()
+	return &public, nil
+}
+
+// resolveActor returns the remote account with the given actor id, fetching
+// it when it is not stored, when it was fetched longer than remoteActorTTL
+// ago or when refresh is set.
+func (s *FederationService) resolveActor(ctx context.Context, id string, refresh bool) (*models.RemoteActor, error) {
+	if !refresh {
+		actor, err := s.store.Federation.GetRemoteActorByURI(ctx, id)
+		if err == nil && time.Since(actor.FetchedAt) < remoteActorTTL {
+			return actor, nil
+		}
+		if err != nil && err != store.ErrNotFound {
+			return nil, err
+		}
+	}
+	if _, ok := s.urls.localUserID(id); ok {
+		return nil, apperrors.ErrInvalidActivity
+	}
+
+	doc, err := s.client.FetchActor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if doc.PublicKey.Owner != "" && doc.PublicKey.Owner != doc.ID {
+		return nil, activitypub.ErrInvalidObject
+	}
+	u, err := url.Parse(doc.ID)
+	if err != nil {
+		return nil, activitypub.ErrInvalidObject
+	}
+
+	actor := &models.RemoteActor{
+		URI:          doc.ID,
+		KeyID:        doc.PublicKey.ID,
+		PublicKeyPEM: doc.PublicKey.PublicKeyPem,
+		Inbox:        doc.Inbox,
+		ProfileURL:   doc.URL,
+		Username:     doc.PreferredUsername,
+		Domain:       strings.ToLower(u.Host),
+		DisplayName:  truncate(doc.Name, 50),
+		Bio:          truncate(activitypub.PlainText(doc.Summary), 300),
+		IsPrivate:    doc.ManuallyApprovesFollowers,
+	}
+	if doc.Endpoints != nil {
+		actor.SharedInbox = doc.Endpoints.SharedInbox
+	}
+	if actor.Username == "" {
+		actor.Username = strings.TrimPrefix(u.Path[strings.LastIndex(u.Path, "/")+1:], "@")
+	}
+	if err := s.store.Federation.SaveRemoteActor(ctx, actor); err != nil {
+		return nil, err
+	}
+	actor.FetchedAt = time.Now()
+	return actor, nil
+}
+
+// publishPost delivers a newly created post of a local account to the
+// servers of its remote followers.
+func (s *FederationService) publishPost(ctx context.Context, post *models.Post) error {
+	if post.Visibility == models.VisibilityPrivate {
+		return nil
+	}
+	return s.deliverToFollowers(ctx, post.UserID, s.createActivity(post))
+}
+
+// retractPost tells the servers of the remote followers of the author that
+// a post was deleted.
+func (s *FederationService) retractPost(ctx context.Context, post *models.Post) error {
+	if post.Visibility == models.VisibilityPrivate {
+		return nil
+	}
+	activity, err := activitypub.NewActivity(s.urls.activity(post.UserID), activitypub.TypeDelete, s.urls.actor(post.UserID), map[string]string{
+		"id":   s.urls.note(post.ID),
+		"type": activitypub.TypeTombstone,
+	})
+	if err != nil {
+		return err
+	}
+	activity.To = s.audience(post).To
+	return s.deliverToFollowers(ctx, post.UserID, activity)
+}
+
+func (s *FederationService) deliverToFollowers(ctx context.Context, userID int64, activity any) error {
+	inboxes, err := s.store.Federation.GetFollowerInboxes(ctx, userID)
+	if err != nil || len(inboxes) == 0 {
+		return err
+	}
+	return s.deliveries.enqueue(ctx, userID, inboxes, activity)
+}
+
+// follow asks the remote account target to let followerID follow it. The
+// follow is kept as a pending follow request until the remote server accepts
+// it.
+func (s *FederationService) follow(ctx context.Context, followerID int64, target *models.User) (models.FollowStatus, error) {
+	following, err := s.store.Follow.IsFollowing(ctx, followerID, target.ID)
+	if err != nil {
+		return "", err
+	}
+	if following {
+		return models.FollowStatusFollowing, nil
+	}
+
+	actor, err := s.store.Federation.GetRemoteActor(ctx, target.ID)
+	if err != nil {
+		return "", err
+	}
+	if err := s.store.FollowRequest.Create(ctx, target.ID, followerID); err != nil {
+		return "", err
+	}
+
+	id := s.urls.activity(followerID)
+	if err := s.store.Federation.SaveFollowActivity(ctx, target.ID, followerID, id); err != nil {
+		return "", err
+	}
+	activity, err := activitypub.NewActivity(id, activitypub.TypeFollow, s.urls.actor(followerID), actor.URI)
+	if err != nil {
+		return "", err
+	}
+	if err := s.deliveries.enqueue(ctx, followerID, []string{actor.Inbox}, activity); err != nil {
+		return "", err
+	}
+	return models.FollowStatusRequested, nil
+}
+
+// undoFollow tells the remote account userID that followerID no longer
+// follows it or withdrew their request. It does nothing for local accounts.
+func (s *FederationService) undoFollow(ctx context.Context, followerID, userID int64) error {
+	return s.respondToFollow(ctx, userID, followerID, followerID, activitypub.TypeUndo)
+}
+
+// acceptFollow tells the remote account followerID that its request to
+// follow userID was approved. It does nothing for local accounts.
+func (s *FederationService) acceptFollow(ctx context.Context, userID, followerID int64) error {
+	return s.respondToFollow(ctx, userID, followerID, userID, activitypub.TypeAccept)
+}
+
+// rejectFollow tells the remote account followerID that its request to
+// follow userID was denied. It does nothing for local accounts.
+func (s *FederationService) rejectFollow(ctx context.Context, userID, followerID int64) error {
+	return s.respondToFollow(ctx, userID, followerID, userID, activitypub.TypeReject)
+}
+
+// respondToFollow sends an activity of type typ from the local account
+// senderID about the recorded Follow of followerID following userID, to the
+// other, remote, account of the two. Except for accepts, the Follow is
+// forgotten.
+func (s *FederationService) respondToFollow(ctx context.Context, userID, followerID, senderID int64, typ string) error {
+	followID, err := s.store.Federation.GetFollowActivity(ctx, userID, followerID)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return nil
+		}
+		return err
+	}
+
+	remoteID := userID
+	if senderID == userID {
+		remoteID = followerID
+	}
+	remote, err := s.store.Federation.GetRemoteActor(ctx, remoteID)
+	if err != nil {
+		return err
+	}
+
+	followerURI, followeeURI := s.urls.actor(followerID), remote.URI
+	if remoteID == followerID {
+		followerURI, followeeURI = remote.URI, s.urls.actor(userID)
+	}
+	follow, err := activitypub.NewActivity(followID, activitypub.TypeFollow, followerURI, followeeURI)
+	if err != nil {
+		return err
+	}
+	follow.Context = nil
+	activity, err := activitypub.NewActivity(s.urls.activity(senderID), typ, s.urls.actor(senderID), follow)
+	if err != nil {
+		return err
+	}
+
+	if typ != activitypub.TypeAccept {
+		if err := s.store.Federation.DeleteFollowActivity(ctx, userID, followerID); err != nil {
+			return err
+		}
+	}
+	return s.deliveries.enqueue(ctx, senderID, []string{remote.Inbox}, activity)
+}
+
+// createActivity wraps the Note of a post of a local account in the Create
+// activity delivered to followers and listed in outboxes.
+func (s *FederationService) createActivity(post *models.Post) *activitypub.Activity {
+	note := s.note(post)
+	raw, _ := json.Marshal(note)
+	published := post.CreatedAt
+	return &activitypub.Activity{
+		Context:   activitypub.Context,
+		ID:        s.urls.note(post.ID) + "/activity",
+		Type:      activitypub.TypeCreate,
+		Actor:     note.AttributedTo,
+		Object:    raw,
+		To:        note.To,
+		Cc:        note.Cc,
+		Published: &published,
+	}
+}
+
+func (s *FederationService) note(post *models.Post) *activitypub.Note {
+	renderPost(post)
+	audience := s.audience(post)
+	note := &activitypub.Note{
+		ID:           s.urls.note(post.ID),
+		Type:         activitypub.TypeNote,
+		AttributedTo: s.urls.actor(post.UserID),
+		Name:         post.Title,
+		Content:      post.ContentHTML,
+		MediaType:    "text/html",
+		URL:          fmt.Sprintf("%s/posts/%d", s.frontendURL, post.ID),
+		Published:    post.CreatedAt,
+		To:           audience.To,
+		Cc:           audience.Cc,
+	}
+	if post.UpdatedAt.After(post.CreatedAt) {
+		updated := post.UpdatedAt
+		note.Updated = &updated
+	}
+	for _, tag := range post.Tags {
+		note.Tag = append(note.Tag, activitypub.Tag{
+			Type: "Hashtag",
+			Name: "#" + tag,
+			Href: s.frontendURL + "/tags/" + url.PathEscape(tag),
+		})
+	}
+	return note
+}
+
+// audience addresses public posts to everyone, copying the followers, and
+// the other posts to the followers only.
+func (s *FederationService) audience(post *models.Post) activitypub.Note {
+	followers := s.urls.followers(post.UserID)
+	if post.Visibility == models.VisibilityPublic {
+		return activitypub.Note{To: activitypub.Audience{activitypub.Public}, Cc: activitypub.Audience{followers}}
+	}
+	return activitypub.Note{To: activitypub.Audience{followers}}
+}
+
+func (s *FederationService) profileURL(userID int64) string {
+	return fmt.Sprintf("%s/users/%d", s.frontendURL, userID)
+}
+
+// actorKeys returns the key pair of a local account, generating it on first
+// use.
+func actorKeys(ctx context.Context, st store.Storage, userID int64) (*models.ActorKeys, error) {
+	keys, err := st.Federation.GetKeys(ctx, userID)
+	if err != store.ErrNotFound {
+		return keys, err
+	}
+
+	private, public, err := activitypub.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	// Concurrent requests may both generate a pair; the first one stored
+	// wins.
+	return st.Federation.CreateKeys(ctx, &models.ActorKeys{
+		UserID:        userID,
+		PublicKeyPEM:  public,
+		PrivateKeyPEM: private,
+	})
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
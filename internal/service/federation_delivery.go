@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+	"github.com/LikhithMar14/gopher-chat/pkg/activitypub"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxDeliveryAttempts is how often a failing delivery is tried before it
+	// is given up on.
+	maxDeliveryAttempts = 8
+	// deliveryRetryDelay is the delay before the first retry, doubled on each
+	// further one.
+	deliveryRetryDelay = 30 * time.Second
+	// staleDeliveryAfter is how long a delivery may be in progress before
+	// another worker picks it up again.
+	staleDeliveryAfter = 5 * time.Minute
+	// deliveryPollInterval is how often idle workers look for deliveries that
+	// are due again or were queued by other instances.
+	deliveryPollInterval = 30 * time.Second
+)
+
+// DeliveryWorker delivers the activities of local accounts to the inboxes of
+// other servers in the background, retrying with backoff while they are
+// unreachable.
+type DeliveryWorker struct {
+	*queueWorker
+	store  store.Storage
+	client *activitypub.Client
+	urls   apURLs
+}
+
+// NewDeliveryWorker returns a worker signing deliveries as the actors of the
+// local accounts served at apiURL.
+func NewDeliveryWorker(store store.Storage, client *activitypub.Client, apiURL string, workers int, logger *zap.SugaredLogger) *DeliveryWorker {
+	w := &DeliveryWorker{
+		store:  store,
+		client: client,
+		urls:   newAPURLs(apiURL),
+	}
+	w.queueWorker = newQueueWorker("federation_delivery", workers, deliveryPollInterval, logger, w.deliverNext)
+	return w
+}
+
+// enqueue queues activity of senderID for delivery to each of the inboxes.
+func (w *DeliveryWorker) enqueue(ctx context.Context, senderID int64, inboxes []string, activity any) error {
+	body, err := json.Marshal(activity)
+	if err != nil {
+		return err
+	}
+	if err := w.store.Federation.EnqueueDeliveries(ctx, senderID, inboxes, body); err != nil {
+		return err
+	}
+	w.Notify()
+	return nil
+}
+
+func (w *DeliveryWorker) deliverNext(ctx context.Context) (bool, error) {
+	delivery, err := w.store.Federation.ClaimDelivery(ctx, staleDeliveryAfter)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	keys, err := actorKeys(ctx, w.store, delivery.SenderID)
+	if err != nil {
+		return true, err
+	}
+	key, err := activitypub.ParsePrivateKey(keys.PrivateKeyPEM)
+	if err != nil {
+		return true, w.store.Federation.FailDelivery(ctx, delivery.ID, err.Error(), nil)
+	}
+
+	err = w.client.Deliver(ctx, delivery.Inbox, delivery.Activity, w.urls.key(delivery.SenderID), key)
+	if err == nil {
+		return true, w.store.Federation.CompleteDelivery(ctx, delivery.ID)
+	}
+
+	var retryAt *time.Time
+	if delivery.Attempts < maxDeliveryAttempts && isTemporaryDeliveryError(err) {
+		t := time.Now().Add(deliveryRetryDelay << (delivery.Attempts - 1))
+		retryAt = &t
+	}
+	w.logger.Infow("Failed to deliver activity", "inbox", delivery.Inbox, "attempt", delivery.Attempts, "retry", retryAt != nil, "error", err)
+
+	return true, w.store.Federation.FailDelivery(ctx, delivery.ID, err.Error(), retryAt)
+}
+
+// isTemporaryDeliveryError reports whether a delivery that failed with err
+// may succeed later. Servers rejecting an activity, and inboxes that can't be
+// reached from here at all, are not retried.
+func isTemporaryDeliveryError(err error) bool {
+	var status *activitypub.StatusError
+	if errors.As(err, &status) {
+		return status.Temporary()
+	}
+	return !errors.Is(err, activitypub.ErrForbiddenAddress) && !errors.Is(err, activitypub.ErrUnsupportedURL)
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"github.com/LikhithMar14/gopher-chat/pkg/activitypub"
+	"github.com/LikhithMar14/gopher-chat/pkg/textparse"
+)
+
+// HandleInbox handles an activity delivered to an inbox by another server.
+// The request must be signed by the actor of the activity. Activities that
+// are not understood, or that don't concern any local account, are accepted
+// and ignored, as other servers don't expect anything else.
+func (s *FederationService) HandleInbox(ctx context.Context, r *http.Request, body []byte) error {
+	var activity activitypub.Activity
+	if err := json.Unmarshal(body, &activity); err != nil || activity.ID == "" || activity.Actor == "" {
+		return apperrors.ErrInvalidActivity
+	}
+
+	actor, err := s.verify(ctx, r, body)
+	if err != nil {
+		return err
+	}
+	if actor.URI != activity.Actor {
+		return apperrors.ErrActorMismatch
+	}
+
+	switch activity.Type {
+	case activitypub.TypeFollow:
+		return s.handleFollow(ctx, actor, &activity)
+	case activitypub.TypeUndo:
+		return s.handleUndo(ctx, actor, &activity)
+	case activitypub.TypeLike:
+		return s.handleLike(ctx, actor, activity.ObjectID(), true)
+	case activitypub.TypeCreate:
+		return s.handleCreate(ctx, actor, &activity)
+	case activitypub.TypeDelete:
+		return s.handleDelete(ctx, actor, &activity)
+	case activitypub.TypeAccept, activitypub.TypeReject:
+		return s.handleResponse(ctx, actor, &activity)
+	}
+	return nil
+}
+
+// verify checks the signature of an inbox request and returns the remote
+// account that signed it. A key that fails to verify is fetched again once,
+// since the account may have rotated it. A fetched account must currently
+// have the key the request was signed with.
+func (s *FederationService) verify(ctx context.Context, r *http.Request, body []byte) (*models.RemoteActor, error) {
+	keyID, err := activitypub.KeyID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := s.store.Federation.GetRemoteActorByKeyID(ctx, keyID)
+	fetched := false
+	if err == store.ErrNotFound {
+		actorID, _, _ := strings.Cut(keyID, "#")
+		if actor, err = s.resolveActor(ctx, actorID, true); err != nil {
+			s.logger.Infow("Failed to fetch signing actor", "key", keyID, "error", err)
+			return nil, activitypub.ErrInvalidSignature
+		}
+		if actor.KeyID != keyID {
+			return nil, activitypub.ErrInvalidSignature
+		}
+		fetched = true
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Accounts stored before keys were checked on fetch may carry the key
+	// id of another account.
+	if !activitypub.OwnsKey(actor.URI, keyID) {
+		return nil, activitypub.ErrInvalidSignature
+	}
+
+	if err := verifyWith(r, body, actor); err == nil {
+		return actor, nil
+	} else if fetched {
+		return nil, err
+	}
+
+	if actor, err = s.resolveActor(ctx, actor.URI, true); err != nil {
+		s.logger.Infow("Failed to refetch signing actor", "key", keyID, "error", err)
+		return nil, activitypub.ErrInvalidSignature
+	}
+	if actor.KeyID != keyID {
+		return nil, activitypub.ErrInvalidSignature
+	}
+	if err := verifyWith(r, body, actor); err != nil {
+		return nil, err
+	}
+	return actor, nil
+}
+
+func verifyWith(r *http.Request, body []byte, actor *models.RemoteActor) error {
+	key, err := activitypub.ParsePublicKey(actor.PublicKeyPEM)
+	if err != nil {
+		return activitypub.ErrInvalidSignature
+	}
+	return activitypub.Verify(r, body, key)
+}
+
+// handleFollow lets the remote actor follow a local account, or asks the
+// account to approve it when it is private. Blocked actors are rejected.
+func (s *FederationService) handleFollow(ctx context.Context, actor *models.RemoteActor, activity *activitypub.Activity) error {
+	userID, ok := s.urls.localUserID(activity.ObjectID())
+	if !ok {
+		return nil
+	}
+	user, err := s.store.User.GetByID(ctx, userID)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return nil
+		}
+		return err
+	}
+	if err := s.store.Federation.SaveFollowActivity(ctx, userID, actor.UserID, activity.ID); err != nil {
+		return err
+	}
+
+	blocked, err := s.store.Block.IsBlocked(ctx, userID, actor.UserID)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return s.rejectFollow(ctx, userID, actor.UserID)
+	}
+
+	if user.IsPrivate {
+		following, err := s.store.Follow.IsFollowing(ctx, actor.UserID, userID)
+		if err != nil {
+			return err
+		}
+		if !following {
+			if err := s.store.FollowRequest.Create(ctx, userID, actor.UserID); err != nil {
+				return err
+			}
+			s.notifications.notifyFollowRequest(ctx, actor.UserID, userID)
+			return nil
+		}
+	} else {
+		followed, err := s.store.Follow.Follow(ctx, actor.UserID, userID)
+		if err != nil {
+			return err
+		}
+		if followed {
+			s.timeline.Notify()
+		}
+	}
+	return s.acceptFollow(ctx, userID, actor.UserID)
+}
+
+// handleUndo reverts a follow or a like of the remote actor.
+func (s *FederationService) handleUndo(ctx context.Context, actor *models.RemoteActor, activity *activitypub.Activity) error {
+	var undone activitypub.Activity
+	if err := activity.DecodeObject(&undone); err != nil {
+		// Some servers only reference the undone activity, which is
+		// enough to find a Follow.
+		undone = activitypub.Activity{ID: activity.ObjectID(), Type: activitypub.TypeFollow}
+	}
+	if undone.Actor != "" && undone.Actor != actor.URI {
+		return apperrors.ErrActorMismatch
+	}
+
+	switch undone.Type {
+	case activitypub.TypeFollow:
+		userID, followerID, err := s.store.Federation.GetFollowByActivity(ctx, undone.ID)
+		if err != nil {
+			if err == store.ErrNotFound {
+				return nil
+			}
+			return err
+		}
+		if followerID != actor.UserID {
+			return apperrors.ErrActorMismatch
+		}
+		return s.dropFollow(ctx, followerID, userID)
+	case activitypub.TypeLike:
+		return s.handleLike(ctx, actor, undone.ObjectID(), false)
+	}
+	return nil
+}
+
+// handleLike likes or unlikes a local post for the remote actor, provided
+// the actor may see it.
+func (s *FederationService) handleLike(ctx context.Context, actor *models.RemoteActor, object string, liked bool) error {
+	postID, ok := s.urls.localPostID(object)
+	if !ok {
+		return nil
+	}
+	post, err := s.store.Post.GetByID(ctx, postID)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return nil
+		}
+		return err
+	}
+
+	if !liked {
+		return s.store.Federation.SetLike(ctx, actor.UserID, post.ID, false)
+	}
+	visible, err := canViewPost(utils.SetUserID(ctx, actor.UserID), s.store, post)
+	if err != nil || !visible {
+		return err
+	}
+	if err := s.store.Federation.SetLike(ctx, actor.UserID, post.ID, true); err != nil {
+		return err
+	}
+	s.notifications.notifyLike(ctx, actor.UserID, post)
+	return nil
+}
+
+// handleCreate stores a Note of the remote actor and adds it to the home
+// feeds of its local followers. Notes that no local account follows, and
+// direct messages, are ignored.
+func (s *FederationService) handleCreate(ctx context.Context, actor *models.RemoteActor, activity *activitypub.Activity) error {
+	if activity.ObjectType() != activitypub.TypeNote {
+		return nil
+	}
+	var note activitypub.Note
+	if err := activity.DecodeObject(&note); err != nil {
+		return apperrors.ErrInvalidActivity
+	}
+	if note.AttributedTo != actor.URI || !activitypub.SameHost(note.ID, actor.URI) {
+		return apperrors.ErrActorMismatch
+	}
+
+	followed, err := s.store.Federation.HasLocalFollowers(ctx, actor.UserID)
+	if err != nil || !followed {
+		return err
+	}
+
+	post := &models.Post{
+		UserID:    actor.UserID,
+		Content:   activitypub.PlainText(note.Content),
+		CreatedAt: note.Published,
+	}
+	switch {
+	case note.To.Contains(activitypub.Public) || note.Cc.Contains(activitypub.Public):
+		post.Visibility = models.VisibilityPublic
+	case note.To.Contains(actor.URI+"/followers") || note.Cc.Contains(actor.URI+"/followers"):
+		post.Visibility = models.VisibilityFollowers
+	default:
+		return nil
+	}
+	post.Title = note.Name
+	if post.Title == "" {
+		post.Title = note.Summary
+	}
+	post.Title = truncate(activitypub.PlainText(post.Title), 255)
+
+	var tags []string
+	for _, tag := range note.Tag {
+		if tag.Type == "Hashtag" {
+			tags = append(tags, strings.TrimPrefix(tag.Name, "#"))
+		}
+	}
+	post.Tags = textparse.MergeTags(tags, textparse.ExtractHashtags(post.Content))
+	if post.CreatedAt.IsZero() {
+		post.CreatedAt = time.Now()
+	}
+
+	created, err := s.store.Federation.CreateRemotePost(ctx, post, note.ID)
+	if err != nil || !created {
+		return err
+	}
+	s.timeline.Notify()
+	return nil
+}
+
+// handleDelete deletes a post of the remote actor, or the actor itself with
+// everything stored for it.
+func (s *FederationService) handleDelete(ctx context.Context, actor *models.RemoteActor, activity *activitypub.Activity) error {
+	object := activity.ObjectID()
+	if object == actor.URI {
+		return s.store.Federation.DeleteRemoteActor(ctx, actor.UserID)
+	}
+	return s.store.Federation.DeleteRemotePost(ctx, actor.UserID, object)
+}
+
+// handleResponse applies the answer of the remote actor to a local account
+// asking to follow it.
+func (s *FederationService) handleResponse(ctx context.Context, actor *models.RemoteActor, activity *activitypub.Activity) error {
+	userID, followerID, err := s.store.Federation.GetFollowByActivity(ctx, activity.ObjectID())
+	if err != nil {
+		if err == store.ErrNotFound {
+			return nil
+		}
+		return err
+	}
+	if userID != actor.UserID {
+		return apperrors.ErrActorMismatch
+	}
+
+	if activity.Type == activitypub.TypeReject {
+		if err := s.store.Federation.DeleteFollowActivity(ctx, userID, followerID); err != nil {
+			return err
+		}
+		return s.dropFollow(ctx, followerID, userID)
+	}
+
+	err = s.store.FollowRequest.Approve(ctx, userID, followerID)
+	switch {
+	case err == nil:
+		s.timeline.Notify()
+		return nil
+	case errors.Is(err, apperrors.ErrFollowRequestNotFound):
+		// Already accepted, or withdrawn in the meantime.
+		return nil
+	default:
+		return err
+	}
+}
+
+// dropFollow withdraws the request of followerID to follow userID, or ends
+// the follow.
+func (s *FederationService) dropFollow(ctx context.Context, followerID, userID int64) error {
+	withdrawn, err := withdrawFollowRequest(ctx, s.store, followerID, userID)
+	if err != nil || withdrawn {
+		return err
+	}
+	unfollowed, err := s.store.Follow.Unfollow(ctx, followerID, userID)
+	if err != nil {
+		return err
+	}
+	if unfollowed {
+		s.timeline.Notify()
+	}
+	return nil
+}
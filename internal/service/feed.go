@@ -2,7 +2,11 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
 	"math"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/LikhithMar14/gopher-chat/internal/models"
 	"github.com/LikhithMar14/gopher-chat/internal/store"
@@ -11,15 +15,26 @@ import (
 )
 
 type FeedService struct {
-	store store.Storage
+	store   store.Storage
+	media   *MediaService
+	ranking models.FeedRanking
 }
 
-func NewFeedService(store store.Storage) *FeedService {
+// NewFeedService returns a service scoring the ranked feed with ranking.
+func NewFeedService(store store.Storage, media *MediaService, ranking models.FeedRanking) *FeedService {
 	return &FeedService{
-		store: store,
+		store:   store,
+		media:   media,
+		ranking: ranking,
 	}
 }
 
+// GetUserFeed returns a page of the home feed of the user in ctx: the posts
+// and reposts of the accounts they follow and their own. The feed is
+// chronological unless req.Mode asks for it to be ranked. Chronological feeds
+// can be restricted to the entries after req.SinceID and before req.MaxID.
+// Ranked feeds are scored once for the first page and paged with the cursor
+// returned along with it, so entries don't move between pages.
 func (s *FeedService) GetUserFeed(ctx context.Context, req models.FeedRequest) (*models.FeedResponse, error) {
 
 	userID, ok := utils.GetUserID(ctx)
@@ -41,10 +56,45 @@ func (s *FeedService) GetUserFeed(ctx context.Context, req models.FeedRequest) (
 		req.PageSize = 50
 	}
 
-	feedItems, totalCount, err := s.store.Post.GetFeed(ctx, userID, req.Page, req.PageSize)
+	since, err := decodeFeedID(req.SinceID)
 	if err != nil {
 		return nil, err
 	}
+	max, err := decodeFeedID(req.MaxID)
+	if err != nil {
+		return nil, err
+	}
+
+	var feedItems []*models.FeedItem
+	var totalCount int64
+	var nextCursor string
+	switch req.Mode {
+	case models.FeedModeRanked:
+		asOf := time.Now().Truncate(time.Microsecond)
+		var after *models.RankedFeedPosition
+		if req.Cursor != "" {
+			after, err = decodeRankedCursor(req.Cursor)
+			if err != nil {
+				return nil, err
+			}
+			asOf = after.AsOf
+		}
+		feedItems, totalCount, err = s.store.Post.GetRankedFeed(ctx, userID, s.ranking, asOf, after, req.PageSize)
+		if err == nil && len(feedItems) == req.PageSize {
+			nextCursor = encodeRankedCursor(asOf, feedItems[len(feedItems)-1])
+		}
+	default:
+		feedItems, totalCount, err = s.store.Post.GetFeed(ctx, userID, since, max, req.Page, req.PageSize)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range feedItems {
+		item.ID = encodeFeedID(item)
+	}
+	if err := hydrateFeedItems(ctx, s.store, s.media, feedItems); err != nil {
+		return nil, err
+	}
 
 	totalPages := int(math.Ceil(float64(totalCount) / float64(req.PageSize)))
 
@@ -56,9 +106,111 @@ func (s *FeedService) GetUserFeed(ctx context.Context, req models.FeedRequest) (
 		HasNext:      req.Page < totalPages,
 		HasPrevious:  req.Page > 1,
 	}
+	if req.Mode == models.FeedModeRanked {
+		paginationInfo.HasNext = nextCursor != ""
+		paginationInfo.HasPrevious = req.Cursor != ""
+	}
 
 	return &models.FeedResponse{
 		Items:      feedItems,
 		Pagination: paginationInfo,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// CountNewItems returns the number of entries in the chronological home feed
+// of the user in ctx that are newer than the entry sinceID, so clients can
+// poll for updates without fetching them.
+func (s *FeedService) CountNewItems(ctx context.Context, sinceID string) (*models.FeedNewItemsResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, apperrors.ErrUserIDNotFound
+	}
+
+	since, err := decodeFeedID(sinceID)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.store.Post.CountFeedSince(ctx, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	return &models.FeedNewItemsResponse{Count: count}, nil
+}
+
+// encodeFeedID returns the ID of a home feed entry, which encodes its
+// position: the time of the repost that put the post in the feed, or of the
+// post itself.
+func encodeFeedID(item *models.FeedItem) string {
+	return utils.EncodeCursor(feedActivityAt(item), item.Post.ID)
+}
+
+// feedActivityAt returns the time of the repost that put the post of item in
+// the feed, or of the post itself.
+func feedActivityAt(item *models.FeedItem) time.Time {
+	if item.Repost != nil {
+		return item.Repost.CreatedAt
+	}
+	return item.Post.CreatedAt
+}
+
+// decodeFeedID reverses encodeFeedID. An empty id decodes to nil.
+func decodeFeedID(id string) (*models.FeedPosition, error) {
+	if id == "" {
+		return nil, nil
+	}
+	activityAt, postID, err := utils.DecodeCursor(id)
+	if err != nil {
+		return nil, err
+	}
+	return &models.FeedPosition{ActivityAt: activityAt, PostID: postID}, nil
+}
+
+// encodeRankedCursor returns the cursor of the ranked feed page following
+// item, scored as of asOf.
+func encodeRankedCursor(asOf time.Time, item *models.FeedItem) string {
+	raw := strings.Join([]string{
+		strconv.FormatInt(asOf.UnixNano(), 10),
+		strconv.FormatFloat(item.Score, 'g', -1, 64),
+		strconv.FormatInt(feedActivityAt(item).UnixNano(), 10),
+		strconv.FormatInt(item.Post.ID, 10),
+	}, ":")
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeRankedCursor reverses encodeRankedCursor.
+func decodeRankedCursor(cursor string) (*models.RankedFeedPosition, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, apperrors.ErrInvalidCursor
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 4 {
+		return nil, apperrors.ErrInvalidCursor
+	}
+
+	asOf, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, apperrors.ErrInvalidCursor
+	}
+	score, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return nil, apperrors.ErrInvalidCursor
+	}
+	activityAt, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, apperrors.ErrInvalidCursor
+	}
+	postID, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil {
+		return nil, apperrors.ErrInvalidCursor
+	}
+
+	return &models.RankedFeedPosition{
+		AsOf:       time.Unix(0, asOf),
+		Score:      score,
+		ActivityAt: time.Unix(0, activityAt),
+		PostID:     postID,
 	}, nil
 }
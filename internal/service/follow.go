@@ -2,24 +2,115 @@ package service
 
 import (
 	"context"
+	"time"
 
+	"github.com/LikhithMar14/gopher-chat/internal/models"
 	"github.com/LikhithMar14/gopher-chat/internal/store"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
 )
 
 type FollowService struct {
-	store store.Storage
+	store         store.Storage
+	media         *MediaService
+	timeline      *TimelineWorker
+	federation    *FederationService
+	notifications *NotificationService
 }
 
-func NewFollowService(store store.Storage) *FollowService {
-	return &FollowService{store: store}
+func NewFollowService(store store.Storage, media *MediaService, timeline *TimelineWorker, federation *FederationService, notifications *NotificationService) *FollowService {
+	return &FollowService{store: store, media: media, timeline: timeline, federation: federation, notifications: notifications}
 }
 
-func (s *FollowService) FollowUser(ctx context.Context, currentUserID, userID int64) error {
-	return s.store.Follow.FollowUser(ctx, currentUserID, userID)
+// FollowUser makes the user in ctx follow userID. Following a private account
+// only creates a follow request, reported as FollowStatusRequested. Following
+// an account that is already followed, or already asked, succeeds without
+// changing anything. Following a remote account asks its server, so it is
+// requested until the server accepts.
+func (s *FollowService) FollowUser(ctx context.Context, userID int64) (models.FollowStatus, error) {
+	followerID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return "", apperrors.ErrUserIDNotFound
+	}
+	if followerID == userID {
+		return "", apperrors.ErrSelfFollow
+	}
+
+	target, err := s.store.User.GetByID(ctx, userID)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return "", apperrors.ErrUserNotFound
+		}
+		return "", err
+	}
+
+	blocked, err := s.store.Block.IsBlocked(ctx, followerID, userID)
+	if err != nil {
+		return "", err
+	}
+	if blocked {
+		return "", apperrors.ErrUserBlocked
+	}
+
+	if target.Domain != "" {
+		return s.federation.follow(ctx, followerID, target)
+	}
+
+	if target.IsPrivate {
+		following, err := s.store.Follow.IsFollowing(ctx, followerID, userID)
+		if err != nil {
+			return "", err
+		}
+		if !following {
+			if err := s.store.FollowRequest.Create(ctx, userID, followerID); err != nil {
+				return "", err
+			}
+			s.notifications.notifyFollowRequest(ctx, followerID, userID)
+			return models.FollowStatusRequested, nil
+		}
+		return models.FollowStatusFollowing, nil
+	}
+
+	followed, err := s.store.Follow.Follow(ctx, followerID, userID)
+	if err != nil {
+		return "", err
+	}
+	if followed {
+		s.timeline.Notify()
+	}
+	return models.FollowStatusFollowing, nil
 }
 
-func (s *FollowService) UnfollowUser(ctx context.Context, currentUserID, userID int64) error {
-	return s.store.Follow.UnfollowUser(ctx, currentUserID, userID)
+// UnfollowUser stops the user in ctx from following userID, or withdraws
+// their pending request to follow it. Unfollowing an account that is not
+// followed succeeds without changing anything.
+func (s *FollowService) UnfollowUser(ctx context.Context, userID int64) error {
+	followerID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return apperrors.ErrUserIDNotFound
+	}
+
+	withdrawn, err := withdrawFollowRequest(ctx, s.store, followerID, userID)
+	if err != nil {
+		return err
+	}
+	if !withdrawn {
+		unfollowed, err := s.store.Follow.Unfollow(ctx, followerID, userID)
+		if err != nil {
+			return err
+		}
+		if unfollowed {
+			s.timeline.Notify()
+		}
+	}
+	return s.federation.undoFollow(ctx, followerID, userID)
+}
+
+// GetFollowEvents returns up to limit follow and unfollow events recorded
+// after the event afterID, oldest first. Consumers such as notifications keep
+// the id of the last event they handled and resume from it.
+func (s *FollowService) GetFollowEvents(ctx context.Context, afterID int64, limit int) ([]*models.FollowEvent, error) {
+	return s.store.Follow.GetEvents(ctx, afterID, limit)
 }
 
 func (s *FollowService) GetFollowerCount(ctx context.Context, userID int64) (int64, error) {
@@ -30,6 +121,85 @@ func (s *FollowService) GetFollowingCount(ctx context.Context, userID int64) (in
 	return s.store.Follow.GetFollowingCount(ctx, userID)
 }
 
-func (s *FollowService) IsFollowing(ctx context.Context, currentUserID, userID int64) (bool, error) {
-	return s.store.Follow.IsFollowing(ctx, currentUserID, userID)
+// IsFollowing reports whether followerID follows userID.
+func (s *FollowService) IsFollowing(ctx context.Context, followerID, userID int64) (bool, error) {
+	return s.store.Follow.IsFollowing(ctx, followerID, userID)
+}
+
+// GetFollowers returns the users following user, most recent follow first.
+// The followers of a private account are only listed to its followers, and
+// users who blocked each other cannot see each other's lists.
+func (s *FollowService) GetFollowers(ctx context.Context, user *models.User, req models.CursorRequest) (*models.FollowListResponse, error) {
+	return s.list(ctx, user, req, func(viewerID int64, before *time.Time, beforeID int64, limit int) ([]*models.FollowEntry, error) {
+		return s.store.Follow.GetFollowers(ctx, user.ID, viewerID, before, beforeID, limit)
+	})
+}
+
+// GetFollowing returns the users followed by user, most recent follow first.
+// The followed users of a private account are only listed to its followers.
+func (s *FollowService) GetFollowing(ctx context.Context, user *models.User, req models.CursorRequest) (*models.FollowListResponse, error) {
+	return s.list(ctx, user, req, func(viewerID int64, before *time.Time, beforeID int64, limit int) ([]*models.FollowEntry, error) {
+		return s.store.Follow.GetFollowing(ctx, user.ID, viewerID, before, beforeID, limit)
+	})
+}
+
+func (s *FollowService) list(ctx context.Context, user *models.User, req models.CursorRequest, fetch func(viewerID int64, before *time.Time, beforeID int64, limit int) ([]*models.FollowEntry, error)) (*models.FollowListResponse, error) {
+	if err := Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	blocked, err := isBlocked(ctx, s.store, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, apperrors.ErrUserBlocked
+	}
+
+	viewerID, _ := utils.GetUserID(ctx)
+	if user.IsPrivate && viewerID != user.ID {
+		following, err := s.store.Follow.IsFollowing(ctx, viewerID, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !following {
+			return nil, apperrors.ErrPrivateAccount
+		}
+	}
+
+	var before *time.Time
+	var beforeID int64
+	if req.Cursor != "" {
+		t, id, err := utils.DecodeCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		before, beforeID = &t, id
+	}
+
+	entries, err := fetch(viewerID, before, beforeID, req.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	pagination := &models.CursorPaginationInfo{Limit: req.Limit}
+	if len(entries) > req.Limit {
+		entries = entries[:req.Limit]
+		last := entries[len(entries)-1]
+		pagination.HasMore = true
+		pagination.NextCursor = utils.EncodeCursor(last.FollowedAt, last.User.ID)
+	}
+
+	users := make([]*models.PublicUser, 0, len(entries))
+	for _, e := range entries {
+		users = append(users, &e.User)
+	}
+	if err := s.media.attachAvatars(ctx, users...); err != nil {
+		return nil, err
+	}
+
+	return &models.FollowListResponse{
+		Items:      entries,
+		Pagination: pagination,
+	}, nil
 }
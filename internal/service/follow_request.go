@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+)
+
+// GetFollowRequests returns the pending requests to follow the user in ctx,
+// newest first.
+func (s *FollowService) GetFollowRequests(ctx context.Context, req models.CursorRequest) (*models.FollowRequestsResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, apperrors.ErrUserIDNotFound
+	}
+
+	if err := Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var before *time.Time
+	var beforeID int64
+	if req.Cursor != "" {
+		t, id, err := utils.DecodeCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		before, beforeID = &t, id
+	}
+
+	requests, err := s.store.FollowRequest.GetPending(ctx, userID, before, beforeID, req.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	pagination := &models.CursorPaginationInfo{Limit: req.Limit}
+	if len(requests) > req.Limit {
+		requests = requests[:req.Limit]
+		last := requests[len(requests)-1]
+		pagination.HasMore = true
+		pagination.NextCursor = utils.EncodeCursor(last.RequestedAt, last.User.ID)
+	}
+
+	users := make([]*models.PublicUser, 0, len(requests))
+	for _, fr := range requests {
+		users = append(users, &fr.User)
+	}
+	if err := s.media.attachAvatars(ctx, users...); err != nil {
+		return nil, err
+	}
+
+	return &models.FollowRequestsResponse{
+		Items:      requests,
+		Pagination: pagination,
+	}, nil
+}
+
+// ApproveFollowRequest lets requesterID follow the user in ctx.
+func (s *FollowService) ApproveFollowRequest(ctx context.Context, requesterID int64) error {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return apperrors.ErrUserIDNotFound
+	}
+	if err := s.store.FollowRequest.Approve(ctx, userID, requesterID); err != nil {
+		return err
+	}
+	s.timeline.Notify()
+	return s.federation.acceptFollow(ctx, userID, requesterID)
+}
+
+// DenyFollowRequest drops the request of requesterID to follow the user in
+// ctx.
+func (s *FollowService) DenyFollowRequest(ctx context.Context, requesterID int64) error {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return apperrors.ErrUserIDNotFound
+	}
+	if err := s.store.FollowRequest.Delete(ctx, userID, requesterID); err != nil {
+		return err
+	}
+	return s.federation.rejectFollow(ctx, userID, requesterID)
+}
+
+// withdrawFollowRequest drops the pending request of requesterID to follow
+// userID and reports whether there was one.
+func withdrawFollowRequest(ctx context.Context, st store.Storage, requesterID, userID int64) (bool, error) {
+	err := st.FollowRequest.Delete(ctx, userID, requesterID)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, apperrors.ErrFollowRequestNotFound):
+		return false, nil
+	default:
+		return false, err
+	}
+}
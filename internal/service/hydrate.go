@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+)
+
+// hydratePosts fills in the fields of posts that are derived rather than read
+// from the posts table: mentions, attachments, link previews, polls and
+// rendered HTML.
+func hydratePosts(ctx context.Context, st store.Storage, media *MediaService, posts ...*models.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+	if err := attachPostMentions(ctx, st, posts...); err != nil {
+		return err
+	}
+	if err := media.attachPostAttachments(ctx, posts...); err != nil {
+		return err
+	}
+	if err := attachPostLinkPreviews(ctx, st, posts...); err != nil {
+		return err
+	}
+	if err := attachPostPolls(ctx, st, posts...); err != nil {
+		return err
+	}
+	for _, p := range posts {
+		renderPost(p)
+	}
+	return nil
+}
+
+func hydrateFeedItems(ctx context.Context, st store.Storage, media *MediaService, items []*models.FeedItem) error {
+	posts := make([]*models.Post, 0, len(items))
+	users := make([]*models.PublicUser, 0, len(items))
+	for _, item := range items {
+		posts = append(posts, item.Post)
+		users = append(users, item.Author)
+		if item.RepostedBy != nil {
+			users = append(users, item.RepostedBy)
+		}
+	}
+	if err := media.attachAvatars(ctx, users...); err != nil {
+		return err
+	}
+	return hydratePosts(ctx, st, media, posts...)
+}
+
+func hydrateComments(ctx context.Context, st store.Storage, media *MediaService, comments []*models.Comment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+	if err := attachCommentMentions(ctx, st, comments); err != nil {
+		return err
+	}
+	if err := media.attachCommentAttachments(ctx, comments); err != nil {
+		return err
+	}
+	users := make([]*models.PublicUser, 0, len(comments))
+	for _, c := range comments {
+		users = append(users, &c.User)
+	}
+	if err := media.attachAvatars(ctx, users...); err != nil {
+		return err
+	}
+	renderComments(comments)
+	return nil
+}
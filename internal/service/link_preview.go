@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+	"github.com/LikhithMar14/gopher-chat/pkg/linkpreview"
+	"github.com/LikhithMar14/gopher-chat/pkg/textparse"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxLinkPreviews is the number of URLs per post that get a preview.
+	maxLinkPreviews = 4
+	// maxLinkPreviewAttempts is how often a failing fetch is retried.
+	maxLinkPreviewAttempts = 3
+	// linkPreviewRetryDelay is the delay before the first retry, doubled on
+	// each further one.
+	linkPreviewRetryDelay = time.Minute
+	// staleLinkPreviewFetchAfter is how long a fetch may be in progress
+	// before another worker picks it up again.
+	staleLinkPreviewFetchAfter = 2 * time.Minute
+	// linkPreviewPollInterval is how often idle workers look for URLs queued
+	// by other instances.
+	linkPreviewPollInterval = 30 * time.Second
+)
+
+// LinkPreviewWorker fetches previews for the URLs found in posts in the
+// background, so creating a post never waits on a third-party site.
+type LinkPreviewWorker struct {
+	*queueWorker
+	store   store.Storage
+	fetcher *linkpreview.Fetcher
+	ttl     time.Duration
+}
+
+// NewLinkPreviewWorker returns a worker that refreshes cached previews once
+// they are older than ttl.
+func NewLinkPreviewWorker(store store.Storage, fetcher *linkpreview.Fetcher, ttl time.Duration, logger *zap.SugaredLogger, workers int) *LinkPreviewWorker {
+	w := &LinkPreviewWorker{
+		store:   store,
+		fetcher: fetcher,
+		ttl:     ttl,
+	}
+	w.queueWorker = newQueueWorker("link_preview", workers, linkPreviewPollInterval, logger, w.fetchNext)
+	return w
+}
+
+// syncPost links the post to previews of the URLs in its content and queues
+// the ones that still have to be fetched.
+func (w *LinkPreviewWorker) syncPost(ctx context.Context, post *models.Post) error {
+	urls := textparse.ExtractURLs(post.Content)
+	if len(urls) > maxLinkPreviews {
+		urls = urls[:maxLinkPreviews]
+	}
+
+	if err := w.store.LinkPreview.ReplaceForPost(ctx, post.ID, urls, w.ttl); err != nil {
+		return err
+	}
+	if len(urls) > 0 {
+		w.Notify()
+	}
+	return nil
+}
+
+func (w *LinkPreviewWorker) fetchNext(ctx context.Context) (bool, error) {
+	url, attempts, err := w.store.LinkPreview.ClaimNext(ctx, staleLinkPreviewFetchAfter)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	p, err := w.fetcher.Fetch(ctx, url)
+	if err != nil {
+		retry := attempts < maxLinkPreviewAttempts &&
+			!errors.Is(err, linkpreview.ErrForbiddenAddress) &&
+			!errors.Is(err, linkpreview.ErrUnsupportedURL) &&
+			!errors.Is(err, linkpreview.ErrNotHTML) &&
+			!errors.Is(err, linkpreview.ErrNoMetadata)
+
+		var retryAt *time.Time
+		if retry {
+			t := time.Now().Add(linkPreviewRetryDelay << (attempts - 1))
+			retryAt = &t
+		}
+		w.logger.Infow("Failed to fetch link preview", "url", url, "attempt", attempts, "retry", retry, "error", err)
+
+		return true, w.store.LinkPreview.MarkFailed(ctx, url, err.Error(), retryAt)
+	}
+
+	// The preview is cached under the URL as written in posts, not the one
+	// redirects ended up at.
+	return true, w.store.LinkPreview.Save(ctx, &models.LinkPreview{
+		URL:         url,
+		Title:       p.Title,
+		Description: p.Description,
+		ImageURL:    p.ImageURL,
+		SiteName:    p.SiteName,
+	})
+}
+
+func attachPostLinkPreviews(ctx context.Context, st store.Storage, posts ...*models.Post) error {
+	ids := make([]int64, 0, len(posts))
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+	}
+
+	previews, err := st.LinkPreview.GetForPosts(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range posts {
+		p.LinkPreviews = previews[p.ID]
+		if p.LinkPreviews == nil {
+			p.LinkPreviews = []models.LinkPreview{}
+		}
+	}
+	return nil
+}
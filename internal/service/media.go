@@ -0,0 +1,349 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	"github.com/LikhithMar14/gopher-chat/internal/utils/blob"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"github.com/LikhithMar14/gopher-chat/pkg/imaging"
+	"github.com/google/uuid"
+)
+
+// sniffLen is the number of leading bytes http.DetectContentType looks at.
+const sniffLen = 512
+
+// allowedMediaTypes maps the content types accepted for upload to the file
+// extension used for the stored blob.
+var allowedMediaTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+	"text/plain":      ".txt",
+}
+
+type MediaService struct {
+	store         store.Storage
+	blobs         blob.Store
+	signer        *blob.URLSigner
+	processor     *MediaProcessor
+	maxUploadSize int64
+}
+
+func NewMediaService(store store.Storage, blobs blob.Store, signer *blob.URLSigner, processor *MediaProcessor, maxUploadSize int64) *MediaService {
+	return &MediaService{
+		store:         store,
+		blobs:         blobs,
+		signer:        signer,
+		processor:     processor,
+		maxUploadSize: maxUploadSize,
+	}
+}
+
+// Download is the content of an attachment, or of one of its variants, being
+// served.
+type Download struct {
+	Filename    string
+	ContentType string
+	Size        int64
+	Body        io.ReadCloser
+}
+
+// Upload stores the file read from r for the user in ctx and records it as an
+// unlinked attachment. The content type is sniffed from the data rather than
+// trusted from the client. Images are queued for processing and can't be
+// downloaded until their metadata has been stripped.
+func (s *MediaService) Upload(ctx context.Context, filename string, r io.Reader, size int64) (*models.Attachment, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, apperrors.ErrUserIDNotFound
+	}
+	if size > s.maxUploadSize {
+		return nil, apperrors.ErrFileTooLarge
+	}
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	head = head[:n]
+	if n == 0 {
+		return nil, apperrors.ErrUnsupportedMediaType
+	}
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	ext, ok := allowedMediaTypes[contentType]
+	if !ok {
+		return nil, apperrors.ErrUnsupportedMediaType
+	}
+
+	attachment := &models.Attachment{
+		UserID:      userID,
+		StorageKey:  "attachments/" + uuid.New().String() + ext,
+		Filename:    sanitizeFilename(filename, ext),
+		ContentType: contentType,
+		Size:        size,
+		Status:      models.AttachmentReady,
+	}
+	if imaging.IsImage(contentType) {
+		attachment.Status = models.AttachmentPending
+	}
+
+	body := io.MultiReader(bytes.NewReader(head), r)
+	if err := s.blobs.Put(ctx, attachment.StorageKey, body, size, contentType); err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Attachment.Create(ctx, attachment); err != nil {
+		// Best effort: the blob is unreachable without its record.
+		_ = s.blobs.Delete(ctx, attachment.StorageKey)
+		return nil, err
+	}
+
+	if attachment.Status == models.AttachmentPending {
+		s.processor.Notify()
+	}
+
+	return s.sign(attachment), nil
+}
+
+// Open verifies a signed download URL and returns the content of the
+// attachment, or of the named variant when variant is not empty. The caller
+// must close the returned body.
+func (s *MediaService) Open(ctx context.Context, id int64, variant, expires, signature string) (*Download, error) {
+	if err := s.signer.Verify(id, variant, expires, signature); err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.store.Attachment.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.Status != models.AttachmentReady {
+		return nil, apperrors.ErrAttachmentNotReady
+	}
+
+	download := &Download{
+		Filename:    attachment.Filename,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+	}
+	key := attachment.StorageKey
+
+	if variant != "" {
+		v, err := s.store.Attachment.GetVariant(ctx, id, variant)
+		if err != nil {
+			return nil, err
+		}
+		key = v.StorageKey
+		download.ContentType = v.ContentType
+		download.Size = v.Size
+	}
+
+	download.Body, err = s.blobs.Get(ctx, key)
+	if err != nil {
+		if err == blob.ErrNotFound {
+			return nil, apperrors.ErrAttachmentNotFound
+		}
+		return nil, err
+	}
+
+	return download, nil
+}
+
+// pendingAttachments returns the attachments with the given ids that are
+// about to be linked, with their signed download URLs, ordered by id like the
+// attachments of posts.
+func (s *MediaService) pendingAttachments(ctx context.Context, ids []int64) ([]*models.Attachment, error) {
+	if len(ids) == 0 {
+		return []*models.Attachment{}, nil
+	}
+
+	attachments, err := s.store.Attachment.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachVariants(ctx, attachments); err != nil {
+		return nil, err
+	}
+	sort.Slice(attachments, func(i, j int) bool { return attachments[i].ID < attachments[j].ID })
+	return s.signAll(attachments), nil
+}
+
+// attachPostAttachments loads the attachments of posts and fills in their
+// signed download URLs.
+func (s *MediaService) attachPostAttachments(ctx context.Context, posts ...*models.Post) error {
+	ids := make([]int64, 0, len(posts))
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+	}
+
+	byPost, err := s.store.Attachment.GetForPosts(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if err := s.attachVariants(ctx, flatten(byPost)); err != nil {
+		return err
+	}
+	for _, p := range posts {
+		p.Attachments = s.signAll(byPost[p.ID])
+	}
+	return nil
+}
+
+// attachCommentAttachments loads the attachments of comments and fills in
+// their signed download URLs.
+func (s *MediaService) attachCommentAttachments(ctx context.Context, comments []*models.Comment) error {
+	ids := make([]int64, 0, len(comments))
+	for _, c := range comments {
+		ids = append(ids, c.ID)
+	}
+
+	byComment, err := s.store.Attachment.GetForComments(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if err := s.attachVariants(ctx, flatten(byComment)); err != nil {
+		return err
+	}
+	for _, c := range comments {
+		c.Attachments = s.signAll(byComment[c.ID])
+	}
+	return nil
+}
+
+// validateAvatar checks that the attachment can be used as the avatar of the
+// user: it must be an image the user uploaded that is not attached to a post
+// or comment and did not fail processing.
+func (s *MediaService) validateAvatar(ctx context.Context, userID, attachmentID int64) error {
+	a, err := s.store.Attachment.GetByID(ctx, attachmentID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrAttachmentNotFound) {
+			return apperrors.ErrInvalidAvatar
+		}
+		return err
+	}
+	if a.UserID != userID || a.PostID != nil || a.CommentID != nil ||
+		!imaging.IsImage(a.ContentType) || a.Status == models.AttachmentFailed {
+		return apperrors.ErrInvalidAvatar
+	}
+	return nil
+}
+
+// attachAvatars loads the avatars of users and fills in their signed download
+// URLs.
+func (s *MediaService) attachAvatars(ctx context.Context, users ...*models.PublicUser) error {
+	var ids []int64
+	for _, u := range users {
+		if u.AvatarID != nil {
+			ids = append(ids, *u.AvatarID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	avatars, err := s.store.Attachment.GetByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	if err := s.attachVariants(ctx, avatars); err != nil {
+		return err
+	}
+
+	byID := make(map[int64]*models.Attachment, len(avatars))
+	for _, a := range avatars {
+		byID[a.ID] = s.sign(a)
+	}
+	for _, u := range users {
+		if u.AvatarID != nil {
+			u.Avatar = byID[*u.AvatarID]
+		}
+	}
+	return nil
+}
+
+// attachVariants loads the variants of the processed images among
+// attachments.
+func (s *MediaService) attachVariants(ctx context.Context, attachments []*models.Attachment) error {
+	var ids []int64
+	for _, a := range attachments {
+		if a.Status == models.AttachmentReady && imaging.IsImage(a.ContentType) {
+			ids = append(ids, a.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	variants, err := s.store.Attachment.GetVariants(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, a := range attachments {
+		a.Variants = variants[a.ID]
+	}
+	return nil
+}
+
+func flatten(attachments map[int64][]*models.Attachment) []*models.Attachment {
+	var all []*models.Attachment
+	for _, list := range attachments {
+		all = append(all, list...)
+	}
+	return all
+}
+
+// sign fills in the download URLs of a ready attachment and its variants.
+func (s *MediaService) sign(a *models.Attachment) *models.Attachment {
+	if a.Status != models.AttachmentReady {
+		return a
+	}
+	a.URL = s.signer.Sign(a.ID, "")
+	for _, v := range a.Variants {
+		v.URL = s.signer.Sign(a.ID, v.Name)
+	}
+	return a
+}
+
+func (s *MediaService) signAll(attachments []*models.Attachment) []*models.Attachment {
+	if attachments == nil {
+		return []*models.Attachment{}
+	}
+	for _, a := range attachments {
+		s.sign(a)
+	}
+	return attachments
+}
+
+// sanitizeFilename keeps only the base name of the client supplied filename,
+// falling back to a generic name when nothing usable is left.
+func sanitizeFilename(name, ext string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		return "file" + ext
+	}
+	if len(name) > 255 {
+		name = name[:255]
+	}
+	return name
+}
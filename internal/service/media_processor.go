@@ -0,0 +1,134 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+	"github.com/LikhithMar14/gopher-chat/internal/utils/blob"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"github.com/LikhithMar14/gopher-chat/pkg/imaging"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxProcessingAttempts is how often processing an image is retried
+	// before the attachment is marked as failed.
+	maxProcessingAttempts = 3
+	// staleProcessingAfter is how long an attachment may stay in processing
+	// before another worker picks it up again.
+	staleProcessingAfter = 10 * time.Minute
+	// processingPollInterval is how often idle workers look for work they
+	// were not notified about, such as uploads handled by another instance.
+	processingPollInterval = 30 * time.Second
+)
+
+// MediaProcessor is the background worker that strips metadata from uploaded
+// images and generates their resized variants. Work is queued in the
+// attachments table, so pending images survive restarts and can be shared by
+// several API instances.
+type MediaProcessor struct {
+	*queueWorker
+	store store.Storage
+	blobs blob.Store
+}
+
+func NewMediaProcessor(store store.Storage, blobs blob.Store, logger *zap.SugaredLogger, workers int) *MediaProcessor {
+	p := &MediaProcessor{
+		store: store,
+		blobs: blobs,
+	}
+	p.queueWorker = newQueueWorker("media", workers, processingPollInterval, logger, p.processNext)
+	return p
+}
+
+// processNext processes one queued attachment and reports whether there was
+// one.
+func (p *MediaProcessor) processNext(ctx context.Context) (bool, error) {
+	attachment, err := p.store.Attachment.ClaimNext(ctx, staleProcessingAfter)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrAttachmentNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if err := p.process(ctx, attachment); err != nil {
+		retry := attachment.Attempts < maxProcessingAttempts &&
+			!errors.Is(err, imaging.ErrUnsupportedFormat) &&
+			!errors.Is(err, imaging.ErrTooManyPixels) &&
+			!errors.Is(err, imaging.ErrTooManyFrames)
+
+		p.logger.Warnw("Failed to process attachment", "attachment_id", attachment.ID, "attempt", attachment.Attempts, "retry", retry, "error", err)
+
+		if err := p.store.Attachment.MarkFailed(ctx, attachment.ID, err.Error(), retry); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+func (p *MediaProcessor) process(ctx context.Context, attachment *models.Attachment) error {
+	rc, err := p.blobs.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		return err
+	}
+	result, err := imaging.Process(rc)
+	rc.Close()
+	if err != nil {
+		return err
+	}
+
+	base := strings.TrimSuffix(attachment.StorageKey, path.Ext(attachment.StorageKey))
+	oldKey := attachment.StorageKey
+
+	// The original is replaced by its re-encoded copy, which may have a
+	// different format, e.g. WebP is stored as PNG.
+	original := result.Original
+	attachment.StorageKey = base + allowedMediaTypes[original.ContentType]
+	attachment.ContentType = original.ContentType
+	attachment.Size = int64(len(original.Data))
+	attachment.Width, attachment.Height = &original.Width, &original.Height
+	if err := p.put(ctx, attachment.StorageKey, original); err != nil {
+		return err
+	}
+
+	variants := make([]*models.AttachmentVariant, 0, len(result.Variants))
+	for _, img := range result.Variants {
+		v := &models.AttachmentVariant{
+			AttachmentID: attachment.ID,
+			Name:         img.Name,
+			StorageKey:   base + "_" + img.Name + allowedMediaTypes[img.ContentType],
+			ContentType:  img.ContentType,
+			Size:         int64(len(img.Data)),
+			Width:        img.Width,
+			Height:       img.Height,
+		}
+		if err := p.put(ctx, v.StorageKey, img); err != nil {
+			return err
+		}
+		variants = append(variants, v)
+	}
+
+	if err := p.store.Attachment.MarkProcessed(ctx, attachment, variants); err != nil {
+		return err
+	}
+
+	// Only drop the upload as received, metadata and all, once nothing
+	// refers to it anymore.
+	if oldKey != attachment.StorageKey {
+		if err := p.blobs.Delete(ctx, oldKey); err != nil && !errors.Is(err, blob.ErrNotFound) {
+			p.logger.Warnw("Failed to delete original upload", "attachment_id", attachment.ID, "key", oldKey, "error", err)
+		}
+	}
+	return nil
+}
+
+func (p *MediaProcessor) put(ctx context.Context, key string, img imaging.Image) error {
+	return p.blobs.Put(ctx, key, bytes.NewReader(img.Data), int64(len(img.Data)), img.ContentType)
+}
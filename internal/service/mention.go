@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"github.com/LikhithMar14/gopher-chat/pkg/textparse"
+)
+
+type MentionService struct {
+	store store.Storage
+	media *MediaService
+}
+
+func NewMentionService(store store.Storage, media *MediaService) *MentionService {
+	return &MentionService{store: store, media: media}
+}
+
+// GetMentions returns the posts that mention the user in ctx and that the user
+// is allowed to see, newest first.
+func (s *MentionService) GetMentions(ctx context.Context, req models.CursorRequest) (*models.MentionsResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, apperrors.ErrUserIDNotFound
+	}
+
+	if err := Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var before *time.Time
+	var beforeID int64
+	if req.Cursor != "" {
+		t, id, err := utils.DecodeCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		before, beforeID = &t, id
+	}
+
+	items, err := s.store.Mention.GetMentioningPosts(ctx, userID, before, beforeID, req.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	pagination := &models.CursorPaginationInfo{Limit: req.Limit}
+	if len(items) > req.Limit {
+		items = items[:req.Limit]
+		last := items[len(items)-1]
+		pagination.HasMore = true
+		pagination.NextCursor = utils.EncodeCursor(last.Post.CreatedAt, last.Post.ID)
+	}
+
+	if err := hydrateFeedItems(ctx, s.store, s.media, items); err != nil {
+		return nil, err
+	}
+
+	return &models.MentionsResponse{
+		Items:      items,
+		Pagination: pagination,
+	}, nil
+}
+
+// resolveMentions returns the existing users mentioned in text written by
+// authorID. Mentions of unknown usernames and of users who blocked the author,
+// or were blocked by them, are ignored.
+func resolveMentions(ctx context.Context, st store.Storage, authorID int64, text string) ([]models.User, error) {
+	usernames := textparse.ExtractMentions(text)
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+
+	users, err := st.User.GetByUsernames(ctx, usernames)
+	if err != nil {
+		return nil, err
+	}
+
+	blocked, err := st.Block.BlockedAmong(ctx, authorID, mentionedUserIDs(users))
+	if err != nil {
+		return nil, err
+	}
+	if len(blocked) == 0 {
+		return users, nil
+	}
+
+	allowed := users[:0]
+	for _, u := range users {
+		if !blocked[u.ID] {
+			allowed = append(allowed, u)
+		}
+	}
+	return allowed, nil
+}
+
+// setPostMentions sets the mentions found in the post content on the post,
+// to be saved along with it.
+func setPostMentions(ctx context.Context, st store.Storage, post *models.Post) error {
+	users, err := resolveMentions(ctx, st, post.UserID, post.Content)
+	if err != nil {
+		return err
+	}
+
+	post.Mentions = toMentions(users)
+	return nil
+}
+
+// setCommentMentions sets the mentions found in the comment content on the
+// comment, to be saved along with it.
+func setCommentMentions(ctx context.Context, st store.Storage, comment *models.Comment) error {
+	users, err := resolveMentions(ctx, st, comment.UserID, comment.Content)
+	if err != nil {
+		return err
+	}
+
+	comment.Mentions = toMentions(users)
+	return nil
+}
+
+func attachPostMentions(ctx context.Context, st store.Storage, posts ...*models.Post) error {
+	ids := make([]int64, 0, len(posts))
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+	}
+
+	mentions, err := st.Mention.GetForPosts(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range posts {
+		p.Mentions = mentionsOrEmpty(mentions[p.ID])
+	}
+	return nil
+}
+
+func attachCommentMentions(ctx context.Context, st store.Storage, comments []*models.Comment) error {
+	ids := make([]int64, 0, len(comments))
+	for _, c := range comments {
+		ids = append(ids, c.ID)
+	}
+
+	mentions, err := st.Mention.GetForComments(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range comments {
+		c.Mentions = mentionsOrEmpty(mentions[c.ID])
+	}
+	return nil
+}
+
+func mentionedUserIDs(users []models.User) []int64 {
+	ids := make([]int64, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+func toMentions(users []models.User) []models.Mention {
+	mentions := make([]models.Mention, 0, len(users))
+	for _, u := range users {
+		mentions = append(mentions, models.Mention{UserID: u.ID, Username: u.Username})
+	}
+	return mentions
+}
+
+func mentionsOrEmpty(mentions []models.Mention) []models.Mention {
+	if mentions == nil {
+		return []models.Mention{}
+	}
+	return mentions
+}
@@ -0,0 +1,431 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"github.com/LikhithMar14/gopher-chat/pkg/pubsub"
+	"go.uber.org/zap"
+)
+
+const (
+	// notificationStreamBuffer is the number of notifications buffered per
+	// stream before pushes to a slow client are dropped; they are picked up
+	// again by the next poll.
+	notificationStreamBuffer = 16
+	// notificationStreamPollInterval is how often streams look for
+	// notifications created by other instances.
+	notificationStreamPollInterval = 15 * time.Second
+	// notificationCatchUpSize is the number of missed notifications sent
+	// at once when a stream catches up.
+	notificationCatchUpSize = 50
+	// notificationEventBatchSize is the number of follow events turned into
+	// notifications in one transaction.
+	notificationEventBatchSize = 100
+	// notificationPollInterval is how often the worker looks for new follow
+	// events.
+	notificationPollInterval = 5 * time.Second
+)
+
+// NotificationService keeps the in-app notifications of users. Other services
+// report the actions users should hear about, such as comments, mentions and
+// likes, and the NotificationWorker reports follows from the follow events.
+// Notifications are pushed to the open streams of their recipients as they
+// are created. Actions of blocked or muted users, and of posts the recipient
+// can't see, never notify. Notifying happens after the action was committed
+// and is best effort: failures are logged and don't fail the action.
+type NotificationService struct {
+	store  store.Storage
+	media  *MediaService
+	broker *pubsub.Broker[int64, *models.Notification]
+	logger *zap.SugaredLogger
+}
+
+func NewNotificationService(store store.Storage, media *MediaService, logger *zap.SugaredLogger) *NotificationService {
+	return &NotificationService{
+		store:  store,
+		media:  media,
+		broker: pubsub.NewBroker[int64, *models.Notification](notificationStreamBuffer),
+		logger: logger,
+	}
+}
+
+// GetNotifications returns the notification groups of the user in ctx,
+// latest first, with the number of unread notifications.
+func (s *NotificationService) GetNotifications(ctx context.Context, req models.CursorRequest) (*models.NotificationsResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, apperrors.ErrUserIDNotFound
+	}
+
+	if err := Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var before *time.Time
+	var beforeID int64
+	if req.Cursor != "" {
+		t, id, err := utils.DecodeCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		before, beforeID = &t, id
+	}
+
+	groups, err := s.store.Notification.List(ctx, userID, before, beforeID, req.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	pagination := &models.CursorPaginationInfo{Limit: req.Limit}
+	if len(groups) > req.Limit {
+		groups = groups[:req.Limit]
+		last := groups[len(groups)-1]
+		pagination.HasMore = true
+		pagination.NextCursor = utils.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	if err := s.attachGroupActors(ctx, groups); err != nil {
+		return nil, err
+	}
+
+	unread, err := s.store.Notification.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.NotificationsResponse{
+		Items:       groups,
+		UnreadCount: unread,
+		Pagination:  pagination,
+	}, nil
+}
+
+// MarkRead marks the notification group with the given id, the id of its
+// latest notification, as read for the user in ctx.
+func (s *NotificationService) MarkRead(ctx context.Context, id int64) error {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return apperrors.ErrUserIDNotFound
+	}
+	if err := s.store.Notification.MarkRead(ctx, userID, id); err != nil {
+		if err == store.ErrNotFound {
+			return apperrors.ErrNotificationNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// MarkAllRead marks all notifications of the user in ctx as read.
+func (s *NotificationService) MarkAllRead(ctx context.Context) error {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return apperrors.ErrUserIDNotFound
+	}
+	return s.store.Notification.MarkAllRead(ctx, userID)
+}
+
+// Stream sends the notifications of the user in ctx to send as they are
+// created, until ctx is done or send fails. Notifications created after
+// lastID are sent first, so a client reconnecting with the id of the last
+// notification it received misses nothing; with a lastID of 0 only new
+// notifications are sent. Each notification is sent once, including those
+// committed after notifications with higher ids.
+func (s *NotificationService) Stream(ctx context.Context, lastID int64, send func(*models.Notification) error) error {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return apperrors.ErrUserIDNotFound
+	}
+
+	// Subscribe before catching up so nothing created in between is missed.
+	pushed, unsubscribe := s.broker.Subscribe(userID)
+	defer unsubscribe()
+
+	if lastID == 0 {
+		var err error
+		if lastID, err = s.store.Notification.LatestID(ctx, userID); err != nil {
+			return err
+		}
+	}
+
+	// Notifications may commit out of id order, so ids below the latest one
+	// sent are not skipped. The ids sent are remembered instead, and polls
+	// look again from the latest id sent one poll earlier, which leaves
+	// transactions a poll interval to commit.
+	sent := make(map[int64]bool)
+	deliver := func(n *models.Notification) error {
+		if sent[n.ID] {
+			return nil
+		}
+		if err := send(n); err != nil {
+			return err
+		}
+		sent[n.ID] = true
+		lastID = max(lastID, n.ID)
+		return nil
+	}
+	catchUp := func(since int64) error {
+		for {
+			missed, err := s.store.Notification.GetSince(ctx, userID, since, notificationCatchUpSize)
+			if err != nil {
+				return err
+			}
+			if err := s.attachActors(ctx, missed...); err != nil {
+				return err
+			}
+			for _, n := range missed {
+				if err := deliver(n); err != nil {
+					return err
+				}
+				since = n.ID
+			}
+			if len(missed) < notificationCatchUpSize {
+				return nil
+			}
+		}
+	}
+
+	floor := lastID
+	if err := catchUp(floor); err != nil {
+		return err
+	}
+	next := lastID
+
+	ticker := time.NewTicker(notificationStreamPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case n := <-pushed:
+			if err := deliver(n); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			if err := catchUp(floor); err != nil {
+				return err
+			}
+			// Later polls start after floor, so older ids are not needed.
+			for id := range sent {
+				if id <= floor {
+					delete(sent, id)
+				}
+			}
+			floor, next = next, lastID
+		}
+	}
+}
+
+// notifyMentions notifies the users mentioned in a post.
+func (s *NotificationService) notifyMentions(ctx context.Context, post *models.Post) {
+	s.notify(ctx, models.NotificationMention, post.UserID, mentionIDs(post.Mentions), &post.ID, nil)
+}
+
+// notifyComment notifies the users mentioned in a comment, the author of the
+// post and, of a reply, the others who commented on the post. Each of them
+// is notified once.
+func (s *NotificationService) notifyComment(ctx context.Context, post *models.Post, comment *models.Comment) {
+	mentioned := mentionIDs(comment.Mentions)
+	s.notify(ctx, models.NotificationMention, comment.UserID, mentioned, &post.ID, &comment.ID)
+
+	notified := make(map[int64]bool, len(mentioned)+1)
+	for _, id := range mentioned {
+		notified[id] = true
+	}
+	if !notified[post.UserID] {
+		s.notify(ctx, models.NotificationComment, comment.UserID, []int64{post.UserID}, &post.ID, &comment.ID)
+		notified[post.UserID] = true
+	}
+
+	commenters, err := s.store.Comment.GetCommenterIDs(ctx, post.ID)
+	if err != nil {
+		s.logger.Errorw("Failed to notify", "kind", models.NotificationReply, "actor", comment.UserID, "error", err)
+		return
+	}
+	participants := commenters[:0]
+	for _, id := range commenters {
+		if !notified[id] {
+			participants = append(participants, id)
+		}
+	}
+	s.notify(ctx, models.NotificationReply, comment.UserID, participants, &post.ID, &comment.ID)
+}
+
+// notifyLike notifies the author of a post that actorID liked it.
+func (s *NotificationService) notifyLike(ctx context.Context, actorID int64, post *models.Post) {
+	s.notify(ctx, models.NotificationLike, actorID, []int64{post.UserID}, &post.ID, nil)
+}
+
+// notifyFollowRequest notifies userID that requesterID asked to follow them.
+// The notification disappears once the request is approved, denied or
+// withdrawn.
+func (s *NotificationService) notifyFollowRequest(ctx context.Context, requesterID, userID int64) {
+	s.notify(ctx, models.NotificationFollowRequest, requesterID, []int64{userID}, nil, nil)
+}
+
+// notify creates and publishes the notifications of an action, logging
+// failures.
+func (s *NotificationService) notify(ctx context.Context, kind models.NotificationKind, actorID int64, recipientIDs []int64, postID, commentID *int64) {
+	created, err := s.store.Notification.Create(ctx, kind, actorID, recipientIDs, postID, commentID)
+	if err == nil {
+		err = s.publish(ctx, created)
+	}
+	if err != nil {
+		s.logger.Errorw("Failed to notify", "kind", kind, "actor", actorID, "error", err)
+	}
+}
+
+// publish pushes newly created notifications to the streams of their
+// recipients open on this instance.
+func (s *NotificationService) publish(ctx context.Context, notifications []*models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	if err := s.attachActors(ctx, notifications...); err != nil {
+		return err
+	}
+	for _, n := range notifications {
+		s.broker.Publish(n.UserID, n)
+	}
+	return nil
+}
+
+func (s *NotificationService) attachActors(ctx context.Context, notifications ...*models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(notifications))
+	for _, n := range notifications {
+		ids = append(ids, n.ActorID)
+	}
+	actors, err := s.getActors(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, n := range notifications {
+		n.Actor = actors[n.ActorID]
+	}
+	return nil
+}
+
+func (s *NotificationService) attachGroupActors(ctx context.Context, groups []*models.NotificationGroup) error {
+	var ids []int64
+	for _, g := range groups {
+		ids = append(ids, g.ActorIDs...)
+	}
+	actors, err := s.getActors(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		g.Actors = make([]*models.PublicUser, 0, len(g.ActorIDs))
+		for _, id := range g.ActorIDs {
+			if a, ok := actors[id]; ok {
+				g.Actors = append(g.Actors, a)
+			}
+		}
+		g.Summary = notificationSummary(g)
+	}
+	return nil
+}
+
+func (s *NotificationService) getActors(ctx context.Context, ids []int64) (map[int64]*models.PublicUser, error) {
+	actors, err := s.store.Notification.GetActors(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]*models.PublicUser, 0, len(actors))
+	for _, a := range actors {
+		users = append(users, a)
+	}
+	if err := s.media.attachAvatars(ctx, users...); err != nil {
+		return nil, err
+	}
+	return actors, nil
+}
+
+// notificationSummary describes a notification group in a sentence such as
+// "alice and 4 others liked your post".
+func notificationSummary(g *models.NotificationGroup) string {
+	var verb string
+	switch g.Kind {
+	case models.NotificationFollow:
+		verb = "followed you"
+	case models.NotificationFollowRequest:
+		verb = "asked to follow you"
+	case models.NotificationComment:
+		verb = "commented on your post"
+	case models.NotificationReply:
+		verb = "replied to a post you commented on"
+	case models.NotificationMention:
+		verb = "mentioned you"
+	case models.NotificationLike:
+		verb = "liked your post"
+	}
+
+	who := "Someone"
+	if len(g.Actors) > 0 {
+		who = actorName(g.Actors[0])
+	}
+	switch {
+	case g.ActorCount == 2 && len(g.Actors) > 1:
+		who += " and " + actorName(g.Actors[1])
+	case g.ActorCount == 2:
+		who += " and 1 other"
+	case g.ActorCount > 2:
+		who += fmt.Sprintf(" and %d others", g.ActorCount-1)
+	}
+	return who + " " + verb
+}
+
+func actorName(u *models.PublicUser) string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	return u.Username
+}
+
+func mentionIDs(mentions []models.Mention) []int64 {
+	ids := make([]int64, 0, len(mentions))
+	for _, m := range mentions {
+		ids = append(ids, m.UserID)
+	}
+	return ids
+}
+
+// NotificationWorker notifies users of their new followers from the follow
+// events in the background, covering every way a follow comes about: direct
+// follows, approved requests and follows from other servers.
+type NotificationWorker struct {
+	*queueWorker
+	store         store.Storage
+	notifications *NotificationService
+}
+
+func NewNotificationWorker(store store.Storage, notifications *NotificationService, logger *zap.SugaredLogger) *NotificationWorker {
+	w := &NotificationWorker{
+		store:         store,
+		notifications: notifications,
+	}
+	// The follow events are handled in order, so a single worker suffices.
+	w.queueWorker = newQueueWorker("notification", 1, notificationPollInterval, logger, w.applyNext)
+	return w
+}
+
+func (w *NotificationWorker) applyNext(ctx context.Context) (bool, error) {
+	created, handled, err := w.store.Notification.ApplyFollowEvents(ctx, notificationEventBatchSize)
+	if err != nil {
+		return false, err
+	}
+	if err := w.notifications.publish(ctx, created); err != nil {
+		return false, err
+	}
+	return handled > 0, nil
+}
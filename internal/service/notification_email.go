@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"github.com/LikhithMar14/gopher-chat/internal/utils/mailer"
+	"go.uber.org/zap"
+)
+
+const (
+	// notificationEmailMaxItems is the number of notifications listed in an
+	// email; the rest are only counted.
+	notificationEmailMaxItems = 50
+	dailyDigestPeriod         = 24 * time.Hour
+	weeklyDigestPeriod        = 7 * 24 * time.Hour
+	// maxNotificationEmailAttempts is how often emailing a notification is
+	// attempted before it is given up on.
+	maxNotificationEmailAttempts = 5
+	// notificationEmailRetryDelay is the delay before the first retry,
+	// doubled on each further one.
+	notificationEmailRetryDelay = time.Minute
+)
+
+// GetPreferences returns the notification settings of the user in ctx, with
+// the delivery of every kind of notification.
+func (s *NotificationService) GetPreferences(ctx context.Context) (*models.NotificationPreferences, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, apperrors.ErrUserIDNotFound
+	}
+
+	prefs, err := s.store.Notification.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, kind := range models.NotificationKinds {
+		if _, ok := prefs.Delivery[kind]; !ok {
+			prefs.Delivery[kind] = models.NotificationDeliveryInApp
+		}
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences changes the notification settings of the user in ctx
+// and returns them.
+func (s *NotificationService) UpdatePreferences(ctx context.Context, req models.UpdateNotificationPreferencesRequest) (*models.NotificationPreferences, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, apperrors.ErrUserIDNotFound
+	}
+
+	if err := Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	prefs, err := s.GetPreferences(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for kind, delivery := range req.Delivery {
+		prefs.Delivery[kind] = delivery
+	}
+	if req.Timezone != nil {
+		prefs.Timezone = *req.Timezone
+		if prefs.Timezone == "" {
+			prefs.Timezone = "UTC"
+		}
+	}
+	if req.QuietHoursStart != nil && req.QuietHoursEnd != nil {
+		prefs.QuietHoursStart = clockTime(*req.QuietHoursStart)
+		prefs.QuietHoursEnd = clockTime(*req.QuietHoursEnd)
+		if prefs.QuietHoursStart == nil || prefs.QuietHoursEnd == nil {
+			prefs.QuietHoursStart, prefs.QuietHoursEnd = nil, nil
+		}
+	}
+
+	if err := s.store.Notification.SavePreferences(ctx, userID, prefs); err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// clockTime normalizes a time of day such as "7:30" to "07:30". It returns
+// nil for the empty string.
+func clockTime(s string) *string {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return nil
+	}
+	formatted := t.Format("15:04")
+	return &formatted
+}
+
+// NotificationEmailWorker emails notifications to the users who chose to
+// hear about them by email: right away, or in daily and weekly digests of
+// the notifications they haven't read. Nothing is sent during the quiet
+// hours of a user; what is due in between goes out when they end.
+type NotificationEmailWorker struct {
+	*queueWorker
+	store         store.Storage
+	notifications *NotificationService
+	mailer        mailer.Client
+	frontendURL   string
+	isSandbox     bool
+}
+
+func NewNotificationEmailWorker(store store.Storage, notifications *NotificationService, mailer mailer.Client, frontendURL string, isSandbox bool, workers int, poll time.Duration, logger *zap.SugaredLogger) *NotificationEmailWorker {
+	w := &NotificationEmailWorker{
+		store:         store,
+		notifications: notifications,
+		mailer:        mailer,
+		frontendURL:   frontendURL,
+		isSandbox:     isSandbox,
+	}
+	w.queueWorker = newQueueWorker("notification email", workers, poll, logger, w.sendNext)
+	return w
+}
+
+// sendNext sends the next email due: notifications to email right away
+// first, then daily and weekly digests.
+func (w *NotificationEmailWorker) sendNext(ctx context.Context) (bool, error) {
+	claims := []func() (*models.NotificationEmail, error){
+		func() (*models.NotificationEmail, error) {
+			return w.store.Notification.ClaimEmail(ctx)
+		},
+		func() (*models.NotificationEmail, error) {
+			return w.store.Notification.ClaimDigest(ctx, models.NotificationDeliveryDaily, dailyDigestPeriod)
+		},
+		func() (*models.NotificationEmail, error) {
+			return w.store.Notification.ClaimDigest(ctx, models.NotificationDeliveryWeekly, weeklyDigestPeriod)
+		},
+	}
+	for _, claim := range claims {
+		email, err := claim()
+		if err == store.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		return true, w.send(ctx, email)
+	}
+	return false, nil
+}
+
+// send emails a batch of notifications. When sending fails the
+// notifications are released with backoff, to be emailed again with the
+// first email of their delivery due after that, until they were attempted
+// maxNotificationEmailAttempts times. They then stay marked as emailed and
+// are only shown in the app.
+func (w *NotificationEmailWorker) send(ctx context.Context, email *models.NotificationEmail) error {
+	if len(email.Notifications) == 0 {
+		return nil
+	}
+
+	err := w.sendEmail(ctx, email)
+	if err == nil {
+		return nil
+	}
+
+	retry := email.Attempts < maxNotificationEmailAttempts
+	w.logger.Infow("Failed to send notification email", "user_id", email.UserID, "delivery", email.Delivery,
+		"attempt", email.Attempts, "retry", retry, "error", err)
+	if !retry {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(email.Notifications))
+	for _, n := range email.Notifications {
+		ids = append(ids, n.ID)
+	}
+	retryAt := time.Now().Add(notificationEmailRetryDelay << (max(email.Attempts, 1) - 1))
+	return w.store.Notification.ReleaseEmail(ctx, ids, retryAt)
+}
+
+// notificationEmailItem is a notification as listed in an email.
+type notificationEmailItem struct {
+	Summary string
+	URL     string
+}
+
+func (w *NotificationEmailWorker) sendEmail(ctx context.Context, email *models.NotificationEmail) error {
+	user, err := w.store.User.GetByID(ctx, email.UserID)
+	if err != nil {
+		return err
+	}
+	if err := w.notifications.attachActors(ctx, email.Notifications...); err != nil {
+		return err
+	}
+
+	items := make([]notificationEmailItem, 0, min(len(email.Notifications), notificationEmailMaxItems))
+	// Latest first, like the inbox.
+	for i := len(email.Notifications) - 1; i >= 0 && len(items) < notificationEmailMaxItems; i-- {
+		n := email.Notifications[i]
+		group := &models.NotificationGroup{Kind: n.Kind, ActorCount: 1}
+		if n.Actor != nil {
+			group.Actors = []*models.PublicUser{n.Actor}
+		}
+		items = append(items, notificationEmailItem{
+			Summary: notificationSummary(group),
+			URL:     w.notificationURL(n),
+		})
+	}
+
+	template := mailer.NotificationTemplate
+	if email.Delivery != models.NotificationDeliveryEmail {
+		template = mailer.NotificationDigestTemplate
+	}
+	data := map[string]any{
+		"Username":         user.Username,
+		"Items":            items,
+		"Count":            len(email.Notifications),
+		"More":             len(email.Notifications) - len(items),
+		"Weekly":           email.Delivery == models.NotificationDeliveryWeekly,
+		"NotificationsURL": w.frontendURL + "/notifications",
+		"SettingsURL":      w.frontendURL + "/settings/notifications",
+	}
+	return w.mailer.Send(template, user.Username, user.Email, data, w.isSandbox)
+}
+
+// notificationURL links to what a notification is about: the post, or the
+// profile of a new follower.
+func (w *NotificationEmailWorker) notificationURL(n *models.Notification) string {
+	if n.PostID != nil {
+		return fmt.Sprintf("%s/posts/%d", w.frontendURL, *n.PostID)
+	}
+	return fmt.Sprintf("%s/users/%d", w.frontendURL, n.ActorID)
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"github.com/LikhithMar14/gopher-chat/internal/utils/mailer"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxOutboxAttempts is how often a failing outbox message is dispatched
+	// before it is given up on.
+	maxOutboxAttempts = 8
+	// outboxRetryDelay is the delay before the first retry, doubled on each
+	// further one.
+	outboxRetryDelay = 30 * time.Second
+	// staleOutboxMessageAfter is how long a message may be dispatched before
+	// another worker picks it up again. Sending an email may take several
+	// retries within the mailer.
+	staleOutboxMessageAfter = 5 * time.Minute
+	// outboxPollInterval is how often idle workers look for messages that are
+	// due again or were queued by other instances.
+	outboxPollInterval = 10 * time.Second
+)
+
+// OutboxDispatcher dispatches the outbox in the background: it sends the
+// emails and queues the webhook deliveries of the events written along with
+// the changes they are about, retrying with backoff while that fails.
+// Messages may be dispatched more than once when a worker stops halfway.
+type OutboxDispatcher struct {
+	*queueWorker
+	store     store.Storage
+	mailer    mailer.Client
+	isSandbox bool
+	webhooks  *WebhookWorker
+}
+
+func NewOutboxDispatcher(store store.Storage, mailer mailer.Client, isSandbox bool, webhooks *WebhookWorker, workers int, logger *zap.SugaredLogger) *OutboxDispatcher {
+	d := &OutboxDispatcher{
+		store:     store,
+		mailer:    mailer,
+		isSandbox: isSandbox,
+		webhooks:  webhooks,
+	}
+	d.queueWorker = newQueueWorker("outbox", workers, outboxPollInterval, logger, d.dispatchNext)
+	return d
+}
+
+func (d *OutboxDispatcher) dispatchNext(ctx context.Context) (bool, error) {
+	message, err := d.store.Outbox.Claim(ctx, staleOutboxMessageAfter)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	err = d.dispatch(ctx, message)
+	if err == nil {
+		return true, d.store.Outbox.Complete(ctx, message.ID)
+	}
+
+	var retryAt *time.Time
+	if message.Attempts < maxOutboxAttempts {
+		t := time.Now().Add(outboxRetryDelay << (message.Attempts - 1))
+		retryAt = &t
+	}
+	d.logger.Infow("Failed to dispatch outbox message", "id", message.ID, "kind", message.Kind,
+		"attempt", message.Attempts, "retry", retryAt != nil, "error", err)
+
+	return true, d.store.Outbox.Fail(ctx, message.ID, err.Error(), retryAt)
+}
+
+func (d *OutboxDispatcher) dispatch(ctx context.Context, message *models.OutboxMessage) error {
+	switch message.Kind {
+	case models.OutboxEmail:
+		var job models.OutboxEmailJob
+		if err := json.Unmarshal(message.Payload, &job); err != nil {
+			return err
+		}
+		return d.mailer.Send(job.Template, job.Username, job.Email, job.Data, d.isSandbox)
+
+	case models.OutboxEvent:
+		var payload models.WebhookPayload
+		if err := json.Unmarshal(message.Payload, &payload); err != nil {
+			return err
+		}
+		queued, err := d.store.Webhook.Enqueue(ctx, payload.Event, payload.ID, message.Payload)
+		if err != nil {
+			return err
+		}
+		if queued > 0 {
+			d.webhooks.Notify()
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown outbox message kind %q", message.Kind)
+	}
+}
+
+// OutboxService lets operators see and retry the outbox messages that were
+// given up on, such as invitation emails that failed while the mailer was
+// down.
+type OutboxService struct {
+	store      store.Storage
+	dispatcher *OutboxDispatcher
+}
+
+func NewOutboxService(store store.Storage, dispatcher *OutboxDispatcher) *OutboxService {
+	return &OutboxService{store: store, dispatcher: dispatcher}
+}
+
+// GetFailed returns the messages that were given up on, latest first.
+func (s *OutboxService) GetFailed(ctx context.Context, req models.CursorRequest) (*models.OutboxMessagesResponse, error) {
+	if err := Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var before *time.Time
+	var beforeID int64
+	if req.Cursor != "" {
+		t, id, err := utils.DecodeCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		before, beforeID = &t, id
+	}
+
+	messages, err := s.store.Outbox.ListFailed(ctx, before, beforeID, req.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	pagination := &models.CursorPaginationInfo{Limit: req.Limit}
+	if len(messages) > req.Limit {
+		messages = messages[:req.Limit]
+		last := messages[len(messages)-1]
+		pagination.HasMore = true
+		pagination.NextCursor = utils.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return &models.OutboxMessagesResponse{
+		Items:      messages,
+		Pagination: pagination,
+	}, nil
+}
+
+// Retry dispatches a message that was given up on again, with the full
+// number of attempts.
+func (s *OutboxService) Retry(ctx context.Context, id int64) (*models.OutboxMessage, error) {
+	message, err := s.store.Outbox.Retry(ctx, id)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return nil, apperrors.ErrOutboxMessageNotFound
+		}
+		return nil, err
+	}
+	s.dispatcher.Notify()
+	return message, nil
+}
+
+// newOutboxEmail returns a message sending the mailer template to a user.
+func newOutboxEmail(template, username, email string, data map[string]any) (*models.OutboxMessage, error) {
+	payload, err := json.Marshal(models.OutboxEmailJob{
+		Template: template,
+		Username: username,
+		Email:    email,
+		Data:     data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.OutboxMessage{Kind: models.OutboxEmail, Payload: payload}, nil
+}
+
+// newOutboxEvent returns a message delivering an event about data to the
+// webhooks subscribed to it. The payload is built now, so it describes data
+// as of the change.
+func newOutboxEvent(event models.WebhookEvent, data any) (*models.OutboxMessage, error) {
+	payload, err := json.Marshal(models.WebhookPayload{
+		ID:        uuid.NewString(),
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &models.OutboxMessage{Kind: models.OutboxEvent, Payload: payload}, nil
+}
+
+// eventMessages returns the messages callback of the store methods that write
+// a change along with the events about it. The callback returns an event about
+// the changed value, rendered with render.
+func eventMessages[T any](event models.WebhookEvent, render func(T) T) func(T) ([]*models.OutboxMessage, error) {
+	return func(data T) ([]*models.OutboxMessage, error) {
+		message, err := newOutboxEvent(event, render(data))
+		if err != nil {
+			return nil, err
+		}
+		return []*models.OutboxMessage{message}, nil
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+)
+
+type PollService struct {
+	store store.Storage
+}
+
+func NewPollService(store store.Storage) *PollService {
+	return &PollService{store: store}
+}
+
+// Vote records the choices of the user in ctx on the poll of post, replacing
+// any earlier vote. The post is expected to come from postsContextMiddleware,
+// which already checked that the user can see it.
+func (s *PollService) Vote(ctx context.Context, post *models.Post, req models.VotePollRequest) (*models.Poll, error) {
+	if err := Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, apperrors.ErrUserIDNotFound
+	}
+
+	poll, err := s.store.Poll.GetByPostID(ctx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !poll.MultipleChoice && len(req.OptionIDs) > 1 {
+		return nil, apperrors.ErrPollSingleChoice
+	}
+
+	if err := s.store.Poll.Vote(ctx, poll.ID, userID, req.OptionIDs); err != nil {
+		return nil, err
+	}
+
+	return s.getPoll(ctx, post)
+}
+
+// RetractVote removes the vote of the user in ctx from the poll of post.
+func (s *PollService) RetractVote(ctx context.Context, post *models.Post) (*models.Poll, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, apperrors.ErrUserIDNotFound
+	}
+
+	poll, err := s.store.Poll.GetByPostID(ctx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.store.Poll.RetractVote(ctx, poll.ID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.getPoll(ctx, post)
+}
+
+func (s *PollService) getPoll(ctx context.Context, post *models.Post) (*models.Poll, error) {
+	if err := attachPostPolls(ctx, s.store, post); err != nil {
+		return nil, err
+	}
+	if post.Poll == nil {
+		return nil, apperrors.ErrPollNotFound
+	}
+	return post.Poll, nil
+}
+
+// newPoll builds the poll for a post being created from req.
+func newPoll(req *models.CreatePollRequest, now time.Time) (*models.Poll, error) {
+	visibility := req.ResultsVisibility
+	if visibility == "" {
+		visibility = models.PollResultsAlways
+	}
+	if req.ClosesAt != nil && !req.ClosesAt.After(now) {
+		return nil, apperrors.ErrInvalidPollCloseTime
+	}
+	if visibility == models.PollResultsAfterClose && req.ClosesAt == nil {
+		return nil, apperrors.ErrInvalidPollCloseTime
+	}
+
+	poll := &models.Poll{
+		MultipleChoice:    req.MultipleChoice,
+		ResultsVisibility: visibility,
+		ClosesAt:          req.ClosesAt,
+	}
+	for i, text := range req.Options {
+		poll.Options = append(poll.Options, &models.PollOption{Position: i + 1, Text: text})
+	}
+	return poll, nil
+}
+
+// attachPostPolls loads the polls of posts as seen by the viewer in ctx.
+func attachPostPolls(ctx context.Context, st store.Storage, posts ...*models.Post) error {
+	ids := make([]int64, 0, len(posts))
+	for _, p := range posts {
+		ids = append(ids, p.ID)
+	}
+
+	viewerID, _ := utils.GetUserID(ctx)
+	polls, err := st.Poll.GetForPosts(ctx, ids, viewerID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, p := range posts {
+		p.Poll = polls[p.ID]
+		if p.Poll != nil {
+			applyPollVisibility(p.Poll, viewerID == p.UserID, now)
+		}
+	}
+	return nil
+}
+
+// applyPollVisibility marks the poll as closed when its close time has passed
+// and hides the tallies from viewers that may not see them yet.
+func applyPollVisibility(poll *models.Poll, isAuthor bool, now time.Time) {
+	poll.Closed = poll.ClosesAt != nil && !poll.ClosesAt.After(now)
+
+	var hidden bool
+	switch poll.ResultsVisibility {
+	case models.PollResultsAfterVote:
+		hidden = len(poll.MyVotes) == 0 && !poll.Closed
+	case models.PollResultsAfterClose:
+		hidden = !poll.Closed
+	}
+	if !hidden || isAuthor {
+		return
+	}
+
+	poll.ResultsHidden = true
+	poll.VoterCount = nil
+	for _, o := range poll.Options {
+		o.VoteCount = nil
+	}
+}
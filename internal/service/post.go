@@ -3,26 +3,43 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/LikhithMar14/gopher-chat/internal/models"
 	"github.com/LikhithMar14/gopher-chat/internal/store"
-	ctxutil "github.com/LikhithMar14/gopher-chat/pkg/context"
-	apperrors "github.com/LikhithMar14/gopher-chat/pkg/errors"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"github.com/LikhithMar14/gopher-chat/pkg/textparse"
+	"go.uber.org/zap"
 )
 
 type PostService struct {
-	store store.Storage
+	store         store.Storage
+	media         *MediaService
+	previews      *LinkPreviewWorker
+	timeline      *TimelineWorker
+	federation    *FederationService
+	notifications *NotificationService
+	webhooks      *WebhookService
+	logger        *zap.SugaredLogger
 }
 
-func NewPostService(store store.Storage) *PostService {
+func NewPostService(store store.Storage, media *MediaService, previews *LinkPreviewWorker, timeline *TimelineWorker, federation *FederationService, notifications *NotificationService, webhooks *WebhookService, logger *zap.SugaredLogger) *PostService {
 	return &PostService{
-		store: store,
+		store:         store,
+		media:         media,
+		previews:      previews,
+		timeline:      timeline,
+		federation:    federation,
+		notifications: notifications,
+		webhooks:      webhooks,
+		logger:        logger,
 	}
 }
 
 func (s *PostService) CreatePost(ctx context.Context, req models.CreatePostRequest) (*models.Post, error) {
 	var post models.Post
-	userID, ok := ctxutil.GetUserID(ctx)
+	userID, ok := utils.GetUserID(ctx)
 	if err := Validate.Struct(req); err != nil {
 		return nil, err
 	}
@@ -32,30 +49,144 @@ func (s *PostService) CreatePost(ctx context.Context, req models.CreatePostReque
 	post.Title = req.Title
 	post.Content = req.Content
 	post.UserID = userID
-	post.Tags = req.Tags
-	if err := s.store.Post.Create(ctx, &post); err != nil {
+	post.Tags = textparse.MergeTags(req.Tags, textparse.ExtractHashtags(req.Content))
+	post.Visibility = req.Visibility
+	if post.Visibility == "" {
+		post.Visibility = models.VisibilityPublic
+	}
+
+	var poll *models.Poll
+	if req.Poll != nil {
+		var err error
+		if poll, err = newPoll(req.Poll, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := setPostMentions(ctx, s.store, &post); err != nil {
+		return nil, err
+	}
+	attachments, err := s.media.pendingAttachments(ctx, req.AttachmentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// The post is stored with its attachments, poll and post.created event
+	// at once, so the event describes the whole post.
+	created := eventMessages(models.WebhookPostCreated, func(p *models.Post) *models.Post {
+		for _, a := range attachments {
+			a.PostID = &p.ID
+		}
+		p.Attachments = attachments
+		p.Poll = poll
+		return renderPost(p)
+	})
+	if err := s.store.Post.Create(ctx, &post, req.AttachmentIDs, poll, created); err != nil {
+		return nil, err
+	}
+	s.webhooks.published()
+
+	// The post is committed from here on, so failing side effects are
+	// logged rather than failing the request.
+	s.notifications.notifyMentions(ctx, &post)
+	if err := s.previews.syncPost(ctx, &post); err != nil {
+		s.logger.Errorw("Failed to queue link previews", "post", post.ID, "error", err)
+	}
+	s.timeline.Notify()
+	if err := s.federation.publishPost(ctx, &post); err != nil {
+		s.logger.Errorw("Failed to federate post", "post", post.ID, "error", err)
+	}
+	if err := hydratePosts(ctx, s.store, s.media, &post); err != nil {
 		return nil, err
 	}
 	return &post, nil
 }
 
+// GetPostByID returns the post with the given id if the viewer in ctx is
+// allowed to see it. Posts hidden from the viewer are reported as not found so
+// their existence is not leaked.
 func (s *PostService) GetPostByID(ctx context.Context, id int64) (*models.Post, error) {
 	post, err := s.store.Post.GetByID(ctx, id)
 	if err != nil {
 		switch {
-		case err == apperrors.ErrNotFound:
+		case err == store.ErrNotFound:
 			return nil, apperrors.ErrPostNotFound
 		default:
 			return nil, err
 		}
 	}
+
+	visible, err := s.CanViewPost(ctx, post)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, apperrors.ErrPostNotFound
+	}
+	if err := hydratePosts(ctx, s.store, s.media, post); err != nil {
+		return nil, err
+	}
 	return post, nil
 }
 
+// CanViewPost reports whether the viewer in ctx may see post. A missing
+// viewer is treated as anonymous and can only see public posts of public
+// accounts. Users who blocked each other cannot see each other's posts.
+func (s *PostService) CanViewPost(ctx context.Context, post *models.Post) (bool, error) {
+	return canViewPost(ctx, s.store, post)
+}
+
+func canViewPost(ctx context.Context, st store.Storage, post *models.Post) (bool, error) {
+	viewerID, ok := utils.GetUserID(ctx)
+	if ok && viewerID == post.UserID {
+		return true, nil
+	}
+
+	blocked, err := isBlocked(ctx, st, post.UserID)
+	if err != nil || blocked {
+		return false, err
+	}
+
+	switch post.Visibility {
+	case models.VisibilityPublic:
+		// Public posts of private accounts are only shown to followers.
+		author, err := st.User.GetByID(ctx, post.UserID)
+		if err != nil {
+			return false, err
+		}
+		if !author.IsPrivate {
+			return true, nil
+		}
+	case models.VisibilityFollowers:
+	default:
+		return false, nil
+	}
+
+	if !ok {
+		return false, nil
+	}
+	return st.Follow.IsFollowing(ctx, viewerID, post.UserID)
+}
+
 func (s *PostService) DeletePost(ctx context.Context, id int64) error {
-	if err := s.store.Post.Delete(ctx, id); err != nil {
+	post, err := s.store.Post.GetByID(ctx, id)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return apperrors.ErrPostNotFound
+		}
 		return err
 	}
+	deleted, err := newOutboxEvent(models.WebhookPostDeleted, renderPost(post))
+	if err != nil {
+		return err
+	}
+	if err := s.store.Post.Delete(ctx, id, []*models.OutboxMessage{deleted}); err != nil {
+		return err
+	}
+	s.webhooks.published()
+	if err := s.federation.retractPost(ctx, post); err != nil {
+		s.logger.Errorw("Failed to federate post deletion", "post", post.ID, "error", err)
+	}
 	return nil
 }
 
@@ -76,14 +207,24 @@ func (s *PostService) UpdatePost(ctx context.Context, req models.UpdatePostReque
 			if req.Title != nil {
 				p.Title = *req.Title
 			}
+			if req.Tags != nil {
+				p.Tags = *req.Tags
+			} else if req.Content != nil {
+				// Drop the hashtags of the old content, so hashtags removed
+				// from the content are removed from the tags as well.
+				p.Tags = textparse.RemoveTags(p.Tags, textparse.ExtractHashtags(p.Content))
+			}
 			if req.Content != nil {
 				p.Content = *req.Content
 			}
-			if req.Tags != nil {
-				p.Tags = *req.Tags
+			if req.Content != nil || req.Tags != nil {
+				p.Tags = textparse.MergeTags(p.Tags, textparse.ExtractHashtags(p.Content))
+			}
+			if req.Visibility != nil {
+				p.Visibility = *req.Visibility
 			}
-			return nil
-		})
+			return setPostMentions(ctx, s.store, p)
+		}, eventMessages(models.WebhookPostUpdated, renderPost))
 
 		if err != nil {
 			lastErr = err
@@ -96,6 +237,14 @@ func (s *PostService) UpdatePost(ctx context.Context, req models.UpdatePostReque
 		}
 
 		// Success - return the updated post
+		s.webhooks.published()
+		s.notifications.notifyMentions(ctx, post)
+		if err := s.previews.syncPost(ctx, post); err != nil {
+			s.logger.Errorw("Failed to queue link previews", "post", post.ID, "error", err)
+		}
+		if err := hydratePosts(ctx, s.store, s.media, post); err != nil {
+			return nil, err
+		}
 		return post, nil
 	}
 
@@ -104,6 +253,6 @@ func (s *PostService) UpdatePost(ctx context.Context, req models.UpdatePostReque
 }
 
 func (s *PostService) GetPostFromContext(ctx context.Context) (*models.Post, bool) {
-	post, ok := ctx.Value(ctxutil.PostIDKey).(*models.Post)
+	post, ok := ctx.Value(utils.PostIDKey).(*models.Post)
 	return post, ok
 }
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/pkg/markdown"
+)
+
+// renderPost fills in the sanitized HTML of the post content. Content is stored
+// as Markdown source and rendered on the way out, so changes to the allowlist
+// apply to existing content as well.
+func renderPost(post *models.Post) *models.Post {
+	if post != nil {
+		post.ContentHTML = markdown.Render(post.Content)
+	}
+	return post
+}
+
+func renderComment(comment *models.Comment) *models.Comment {
+	if comment != nil {
+		comment.ContentHTML = markdown.Render(comment.Content)
+	}
+	return comment
+}
+
+func renderComments(comments []*models.Comment) []*models.Comment {
+	for _, c := range comments {
+		renderComment(c)
+	}
+	return comments
+}
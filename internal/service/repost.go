@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+)
+
+type RepostService struct {
+	store    store.Storage
+	timeline *TimelineWorker
+}
+
+func NewRepostService(store store.Storage, timeline *TimelineWorker) *RepostService {
+	return &RepostService{store: store, timeline: timeline}
+}
+
+// Repost shares post into the followers' feeds of the user in ctx, optionally
+// as a quote post. Only public posts can be reposted; if the original is later
+// restricted or deleted its reposts disappear from feeds with it.
+func (s *RepostService) Repost(ctx context.Context, post *models.Post, req models.CreateRepostRequest) (*models.Repost, error) {
+	if err := Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, apperrors.ErrUserIDNotFound
+	}
+
+	if post.Visibility != models.VisibilityPublic {
+		return nil, apperrors.ErrRepostNotAllowed
+	}
+
+	repost := &models.Repost{
+		PostID: post.ID,
+		UserID: userID,
+		Quote:  req.Quote,
+	}
+	if err := s.store.Repost.Create(ctx, repost); err != nil {
+		return nil, err
+	}
+	s.timeline.Notify()
+
+	return repost, nil
+}
+
+// Unrepost removes the plain repost of post made by the user in ctx.
+func (s *RepostService) Unrepost(ctx context.Context, post *models.Post) error {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return apperrors.ErrUserIDNotFound
+	}
+
+	return s.store.Repost.DeletePlain(ctx, userID, post.ID)
+}
+
+// DeleteRepost removes a repost or quote post owned by the user in ctx.
+func (s *RepostService) DeleteRepost(ctx context.Context, repostID int64) error {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return apperrors.ErrUserIDNotFound
+	}
+
+	return s.store.Repost.Delete(ctx, userID, repostID)
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+)
+
+type SuggestionService struct {
+	store store.Storage
+	media *MediaService
+	ttl   time.Duration
+	size  int
+}
+
+func NewSuggestionService(store store.Storage, media *MediaService, ttl time.Duration, size int) *SuggestionService {
+	return &SuggestionService{store: store, media: media, ttl: ttl, size: size}
+}
+
+// GetSuggestions returns up to limit accounts for the user in ctx to follow,
+// best first. Suggestions are precomputed per user and recomputed when they
+// are older than the configured TTL.
+func (s *SuggestionService) GetSuggestions(ctx context.Context, limit int) (*models.SuggestionsResponse, error) {
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, apperrors.ErrUserIDNotFound
+	}
+
+	computedAt, err := s.store.Suggestion.LastComputed(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if computedAt == nil || time.Since(*computedAt) > s.ttl {
+		if err := s.store.Suggestion.Refresh(ctx, userID, s.size); err != nil {
+			return nil, err
+		}
+	}
+
+	suggestions, err := s.store.Suggestion.Get(ctx, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*models.PublicUser, 0, len(suggestions))
+	for _, fs := range suggestions {
+		users = append(users, &fs.User)
+	}
+	if err := s.media.attachAvatars(ctx, users...); err != nil {
+		return nil, err
+	}
+
+	return &models.SuggestionsResponse{Items: suggestions}, nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"github.com/LikhithMar14/gopher-chat/pkg/syndication"
+)
+
+// syndicationFeedSize is the number of recent posts carried by a feed.
+const syndicationFeedSize = 50
+
+// SyndicationService builds the Atom and RSS feeds of public posts read by
+// feed readers. Entries link to the posts on the frontend, and the feeds link
+// to themselves on the API.
+type SyndicationService struct {
+	store       store.Storage
+	media       *MediaService
+	apiURL      string
+	frontendURL string
+}
+
+func NewSyndicationService(store store.Storage, media *MediaService, apiURL, frontendURL string) *SyndicationService {
+	return &SyndicationService{
+		store:       store,
+		media:       media,
+		apiURL:      strings.TrimSuffix(apiURL, "/"),
+		frontendURL: strings.TrimSuffix(frontendURL, "/"),
+	}
+}
+
+// GetUserFeed returns the feed of the latest public posts of user, served as
+// the given format ("atom" or "rss"). Private accounts have no feed.
+func (s *SyndicationService) GetUserFeed(ctx context.Context, user *models.User, format string) (*syndication.Feed, error) {
+	if user.IsPrivate {
+		return nil, apperrors.ErrPrivateAccount
+	}
+
+	items, err := s.store.Post.GetPublic(ctx, user.ID, "", syndicationFeedSize)
+	if err != nil {
+		return nil, err
+	}
+
+	name := user.DisplayName
+	if name == "" {
+		name = user.Username
+	}
+	feed, err := s.buildFeed(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+	feed.ID = fmt.Sprintf("%s/users/%d", s.frontendURL, user.ID)
+	feed.Title = fmt.Sprintf("%s (@%s)", name, user.Username)
+	feed.Description = user.Bio
+	feed.Link = feed.ID
+	feed.SelfLink = fmt.Sprintf("%s/v1/users/%d/posts.%s", s.apiURL, user.ID, format)
+	if len(feed.Entries) == 0 {
+		feed.Updated = user.CreatedAt
+	}
+	return feed, nil
+}
+
+// GetTagFeed returns the feed of the latest public posts carrying tag, served
+// as the given format ("atom" or "rss").
+func (s *SyndicationService) GetTagFeed(ctx context.Context, tag string, format string) (*syndication.Feed, error) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	if tag == "" {
+		return nil, apperrors.ErrInvalidInput
+	}
+
+	items, err := s.store.Post.GetPublic(ctx, 0, tag, syndicationFeedSize)
+	if err != nil {
+		return nil, err
+	}
+
+	feed, err := s.buildFeed(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+	feed.ID = fmt.Sprintf("%s/tags/%s", s.frontendURL, url.PathEscape(tag))
+	feed.Title = "#" + tag
+	feed.Link = feed.ID
+	feed.SelfLink = fmt.Sprintf("%s/v1/tags/%s/posts.%s", s.apiURL, url.PathEscape(tag), format)
+	return feed, nil
+}
+
+// buildFeed renders the posts of items into feed entries. An entry is updated
+// whenever its post is edited.
+func (s *SyndicationService) buildFeed(ctx context.Context, items []*models.FeedItem) (*syndication.Feed, error) {
+	if err := hydrateFeedItems(ctx, s.store, s.media, items); err != nil {
+		return nil, err
+	}
+
+	feed := &syndication.Feed{Entries: make([]*syndication.Entry, 0, len(items))}
+	for _, item := range items {
+		post := item.Post
+		author := item.Author.DisplayName
+		if author == "" {
+			author = item.Author.Username
+		}
+		link := fmt.Sprintf("%s/posts/%d", s.frontendURL, post.ID)
+		updated := post.UpdatedAt
+		if updated.Before(post.CreatedAt) {
+			updated = post.CreatedAt
+		}
+		feed.Entries = append(feed.Entries, &syndication.Entry{
+			ID:         link,
+			Title:      post.Title,
+			Link:       link,
+			Author:     author,
+			Content:    post.ContentHTML,
+			Categories: post.Tags,
+			Published:  post.CreatedAt,
+			Updated:    updated,
+		})
+	}
+	return feed, nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+	"go.uber.org/zap"
+)
+
+const (
+	// timelineEventBatchSize is the number of follow events applied to the
+	// home timelines in one transaction.
+	timelineEventBatchSize = 100
+	// timelinePollInterval is how often idle workers look for posts and
+	// follow events recorded by other instances, or left pending by a failed
+	// fan-out.
+	timelinePollInterval = 10 * time.Second
+)
+
+// TimelineWorker maintains the materialized home timelines served by
+// FeedService. Posts and reposts are fanned out to their author and the
+// author's followers in the background after they are created, and follows
+// and unfollows are applied from the follow events, backfilling or pruning
+// the timelines of the followers. Deleted posts and reposts drop out of
+// timelines with their rows, and entries past the timeline window are
+// trimmed.
+type TimelineWorker struct {
+	*queueWorker
+	store        store.Storage
+	maxFanOut    int
+	backfillSize int
+}
+
+// NewTimelineWorker returns a worker that fans entries out to at most
+// maxFanOut followers, leaving more popular authors to be merged in when
+// timelines are read, and backfills up to backfillSize entries on follow.
+func NewTimelineWorker(store store.Storage, maxFanOut, backfillSize, workers int, logger *zap.SugaredLogger) *TimelineWorker {
+	w := &TimelineWorker{
+		store:        store,
+		maxFanOut:    maxFanOut,
+		backfillSize: backfillSize,
+	}
+	w.queueWorker = newQueueWorker("timeline", workers, timelinePollInterval, logger, w.applyNext)
+	return w
+}
+
+func (w *TimelineWorker) applyNext(ctx context.Context) (bool, error) {
+	handled, err := w.store.Timeline.ApplyFollowEvents(ctx, timelineEventBatchSize, w.backfillSize)
+	if err != nil {
+		return false, err
+	}
+	fanned, err := w.store.Timeline.FanOutNext(ctx, w.maxFanOut)
+	if err != nil {
+		return false, err
+	}
+	trimmed, err := w.store.Timeline.Trim(ctx)
+	if err != nil {
+		return false, err
+	}
+	return handled > 0 || fanned || trimmed > 0, nil
+}
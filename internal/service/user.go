@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/LikhithMar14/gopher-chat/internal/models"
 	"github.com/LikhithMar14/gopher-chat/internal/store"
@@ -11,74 +12,143 @@ import (
 )
 
 type UserService struct {
-	store store.Storage
+	store   store.Storage
+	follows *FollowService
+	media   *MediaService
 }
 
-func NewUserService(store store.Storage) *UserService {
+func NewUserService(store store.Storage, follows *FollowService, media *MediaService) *UserService {
 	return &UserService{
-		store: store,
+		store:   store,
+		follows: follows,
+		media:   media,
 	}
 }
 
-func (s *UserService) GetUsers(ctx context.Context) ([]models.User, error) {
+func (s *UserService) GetUsers(ctx context.Context) ([]models.PublicUser, error) {
 	users, err := s.store.User.GetAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get users: %w", err)
 	}
 
-	return users, nil
+	public := make([]models.PublicUser, len(users))
+	ptrs := make([]*models.PublicUser, len(users))
+	for i := range users {
+		public[i] = users[i].Public()
+		ptrs[i] = &public[i]
+	}
+	if err := s.media.attachAvatars(ctx, ptrs...); err != nil {
+		return nil, fmt.Errorf("failed to get avatars: %w", err)
+	}
+
+	return public, nil
 }
 
+// GetProfile returns the public profile of user. The post count only includes
+// the posts the current viewer is allowed to see.
+func (s *UserService) GetProfile(ctx context.Context, user *models.User) (*models.Profile, error) {
+	viewerID, _ := utils.GetUserID(ctx)
 
-func (s *UserService) GetUserByID(ctx context.Context, userID int64) (*models.User, error) {
-	user, err := s.store.User.GetByID(ctx, userID)
-	if err != nil {
-		switch {
-		case err == store.ErrNotFound:
-			return nil, apperrors.ErrUserNotFound
-		default:
-			return nil, fmt.Errorf("failed to get user by id: %w", err)
-		}
+	profile := &models.Profile{
+		PublicUser: user.Public(),
+		Bio:        user.Bio,
+		Links:      user.Links,
+		IsPrivate:  user.IsPrivate,
+	}
+	if profile.Links == nil {
+		profile.Links = []string{}
 	}
-	return user, nil
-}
 
-func (s *UserService) GetUserFromContext(ctx context.Context) (*models.User, bool) {
-	user, ok := ctx.Value(utils.UserIDKey).(*models.User)
+	var err error
+	if profile.FollowerCount, err = s.follows.GetFollowerCount(ctx, user.ID); err != nil {
+		return nil, fmt.Errorf("failed to count followers: %w", err)
+	}
+	if profile.FollowingCount, err = s.follows.GetFollowingCount(ctx, user.ID); err != nil {
+		return nil, fmt.Errorf("failed to count following: %w", err)
+	}
+	if profile.PostCount, err = s.store.Post.CountByUser(ctx, user.ID, viewerID); err != nil {
+		return nil, fmt.Errorf("failed to count posts: %w", err)
+	}
+	if err := s.media.attachAvatars(ctx, &profile.PublicUser); err != nil {
+		return nil, fmt.Errorf("failed to get avatar: %w", err)
+	}
 
-	return user, ok
+	return profile, nil
 }
 
-func (s *UserService) FollowUser(ctx context.Context, followerID int64, userID int64) error {
-	_, err := s.store.User.GetByID(ctx, userID)
+// UpdateProfile applies the fields set in req to the profile of the current
+// user and returns the updated profile.
+func (s *UserService) UpdateProfile(ctx context.Context, req *models.UpdateProfileRequest) (*models.Profile, error) {
+	if err := Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	userID, ok := utils.GetUserID(ctx)
+	if !ok {
+		return nil, apperrors.ErrUserIDNotFound
+	}
+
+	user, err := s.GetUserByID(ctx, userID)
 	if err != nil {
-		switch {
-		case err == store.ErrNotFound:
-			return apperrors.ErrUserNotFound
-		default:
-			return fmt.Errorf("failed to verify target user: %w", err)
+		return nil, err
+	}
+
+	if req.DisplayName != nil {
+		user.DisplayName = strings.TrimSpace(*req.DisplayName)
+	}
+	if req.Bio != nil {
+		user.Bio = strings.TrimSpace(*req.Bio)
+	}
+	if req.Links != nil {
+		user.Links = *req.Links
+	}
+	wasPrivate := user.IsPrivate
+	if req.IsPrivate != nil {
+		user.IsPrivate = *req.IsPrivate
+	}
+	if req.AvatarID != nil {
+		if *req.AvatarID == 0 {
+			user.AvatarID = nil
+		} else {
+			if err := s.media.validateAvatar(ctx, userID, *req.AvatarID); err != nil {
+				return nil, err
+			}
+			user.AvatarID = req.AvatarID
+		}
+	}
+
+	if err := s.store.User.UpdateProfile(ctx, user); err != nil {
+		if err == store.ErrNotFound {
+			return nil, apperrors.ErrUserNotFound
 		}
+		return nil, fmt.Errorf("failed to update profile: %w", err)
 	}
 
-	if err := s.store.User.FollowUser(ctx, userID, followerID); err != nil {
-		return fmt.Errorf("failed to follow user: %w", err)
+	// Nobody is left to approve pending requests once the account is public.
+	if wasPrivate && !user.IsPrivate {
+		if err := s.store.FollowRequest.ApproveAll(ctx, user.ID); err != nil {
+			return nil, fmt.Errorf("failed to approve follow requests: %w", err)
+		}
 	}
-	return nil
+
+	return s.GetProfile(ctx, user)
 }
 
-func (s *UserService) UnfollowUser(ctx context.Context, followerID int64, userID int64) error {
-	_, err := s.store.User.GetByID(ctx, userID)
+func (s *UserService) GetUserByID(ctx context.Context, userID int64) (*models.User, error) {
+	user, err := s.store.User.GetByID(ctx, userID)
 	if err != nil {
 		switch {
 		case err == store.ErrNotFound:
-			return apperrors.ErrUserNotFound
+			return nil, apperrors.ErrUserNotFound
 		default:
-			return fmt.Errorf("failed to verify target user: %w", err)
+			return nil, fmt.Errorf("failed to get user by id: %w", err)
 		}
 	}
+	return user, nil
+}
 
-	if err := s.store.User.UnfollowUser(ctx, userID, followerID); err != nil {
-		return err
-	}
-	return nil
+func (s *UserService) GetUserFromContext(ctx context.Context) (*models.User, bool) {
+	user, ok := ctx.Value(utils.UserIDKey).(*models.User)
+
+	return user, ok
 }
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/LikhithMar14/gopher-chat/internal/store"
+	"github.com/LikhithMar14/gopher-chat/internal/utils"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"github.com/LikhithMar14/gopher-chat/pkg/webhook"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxWebhookAttempts is how often a failing webhook delivery is tried
+	// before it is given up on.
+	maxWebhookAttempts = 10
+	// webhookRetryDelay is the delay before the first retry of a webhook
+	// delivery, doubled on each further one up to maxWebhookRetryDelay.
+	webhookRetryDelay    = 30 * time.Second
+	maxWebhookRetryDelay = 6 * time.Hour
+	// staleWebhookDeliveryAfter is how long a webhook delivery may be in
+	// progress before another worker picks it up again.
+	staleWebhookDeliveryAfter = 5 * time.Minute
+	// webhookPollInterval is how often idle workers look for deliveries that
+	// are due again, queued by other instances, or follow events.
+	webhookPollInterval = 10 * time.Second
+	// webhookFollowEventBatchSize is the number of follow events turned into
+	// deliveries in one transaction.
+	webhookFollowEventBatchSize = 100
+)
+
+// WebhookService manages the webhooks events are delivered to, and their
+// delivery logs. Other services report events with publish, or write them
+// to the outbox along with their changes; follows are picked up from the
+// follow events by the WebhookWorker.
+type WebhookService struct {
+	store  store.Storage
+	worker *WebhookWorker
+	outbox *OutboxDispatcher
+}
+
+func NewWebhookService(store store.Storage, worker *WebhookWorker, outbox *OutboxDispatcher) *WebhookService {
+	return &WebhookService{store: store, worker: worker, outbox: outbox}
+}
+
+// CreateWebhook registers a webhook and returns it with the secret its
+// deliveries are signed with, which isn't shown again.
+func (s *WebhookService) CreateWebhook(ctx context.Context, req models.CreateWebhookRequest) (*models.Webhook, error) {
+	if err := Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		return nil, err
+	}
+	hook := &models.Webhook{
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      req.Events,
+		Description: req.Description,
+		Active:      true,
+	}
+	if hook.Events == nil {
+		hook.Events = []models.WebhookEvent{}
+	}
+	if err := s.store.Webhook.Create(ctx, hook); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+func (s *WebhookService) GetWebhooks(ctx context.Context) ([]*models.Webhook, error) {
+	return s.store.Webhook.List(ctx)
+}
+
+func (s *WebhookService) GetWebhook(ctx context.Context, id int64) (*models.Webhook, error) {
+	hook, err := s.store.Webhook.GetByID(ctx, id)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return nil, apperrors.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return hook, nil
+}
+
+// UpdateWebhook changes the given fields of a webhook. Deliveries queued
+// while a webhook is inactive are sent once it is active again.
+func (s *WebhookService) UpdateWebhook(ctx context.Context, id int64, req models.UpdateWebhookRequest) (*models.Webhook, error) {
+	if err := Validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	hook, err := s.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.URL != nil {
+		hook.URL = *req.URL
+	}
+	if req.Events != nil {
+		hook.Events = *req.Events
+		if hook.Events == nil {
+			hook.Events = []models.WebhookEvent{}
+		}
+	}
+	if req.Description != nil {
+		hook.Description = *req.Description
+	}
+	if req.Active != nil {
+		hook.Active = *req.Active
+	}
+
+	if err := s.store.Webhook.Update(ctx, hook); err != nil {
+		if err == store.ErrNotFound {
+			return nil, apperrors.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	if hook.Active {
+		s.worker.Notify()
+	}
+	return hook, nil
+}
+
+// DeleteWebhook removes a webhook with its delivery log.
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id int64) error {
+	if err := s.store.Webhook.Delete(ctx, id); err != nil {
+		if err == store.ErrNotFound {
+			return apperrors.ErrWebhookNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// GetDeliveries returns the delivery log of a webhook, latest first.
+func (s *WebhookService) GetDeliveries(ctx context.Context, webhookID int64, req models.CursorRequest) (*models.WebhookDeliveriesResponse, error) {
+	if err := Validate.Struct(req); err != nil {
+		return nil, err
+	}
+	if _, err := s.GetWebhook(ctx, webhookID); err != nil {
+		return nil, err
+	}
+
+	var before *time.Time
+	var beforeID int64
+	if req.Cursor != "" {
+		t, id, err := utils.DecodeCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		before, beforeID = &t, id
+	}
+
+	deliveries, err := s.store.Webhook.ListDeliveries(ctx, webhookID, before, beforeID, req.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	pagination := &models.CursorPaginationInfo{Limit: req.Limit}
+	if len(deliveries) > req.Limit {
+		deliveries = deliveries[:req.Limit]
+		last := deliveries[len(deliveries)-1]
+		pagination.HasMore = true
+		pagination.NextCursor = utils.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return &models.WebhookDeliveriesResponse{
+		Items:      deliveries,
+		Pagination: pagination,
+	}, nil
+}
+
+// Redeliver queues a new delivery of the event of a past delivery of a
+// webhook, with the same payload and event id.
+func (s *WebhookService) Redeliver(ctx context.Context, webhookID, deliveryID int64) (*models.WebhookDelivery, error) {
+	delivery, err := s.store.Webhook.Redeliver(ctx, webhookID, deliveryID)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return nil, apperrors.ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+	s.worker.Notify()
+	return delivery, nil
+}
+
+// published wakes the outbox dispatcher after events were written to the
+// outbox along with the changes they are about.
+func (s *WebhookService) published() {
+	s.outbox.Notify()
+}
+
+// WebhookWorker delivers events to webhooks in the background, retrying
+// with exponential backoff while they fail, and turns follow events into
+// follow.created and follow.deleted events.
+type WebhookWorker struct {
+	*queueWorker
+	store  store.Storage
+	client *webhook.Client
+}
+
+func NewWebhookWorker(store store.Storage, client *webhook.Client, workers int, logger *zap.SugaredLogger) *WebhookWorker {
+	w := &WebhookWorker{
+		store:  store,
+		client: client,
+	}
+	w.queueWorker = newQueueWorker("webhook", workers, webhookPollInterval, logger, w.deliverNext)
+	return w
+}
+
+func (w *WebhookWorker) deliverNext(ctx context.Context) (bool, error) {
+	handled, err := w.store.Webhook.EnqueueFollowEvents(ctx, webhookFollowEventBatchSize)
+	if err != nil {
+		return false, err
+	}
+
+	delivery, err := w.store.Webhook.ClaimDelivery(ctx, staleWebhookDeliveryAfter)
+	if err != nil {
+		if err == store.ErrNotFound {
+			return handled > 0, nil
+		}
+		return false, err
+	}
+
+	resp, err := w.client.Send(ctx, webhook.Delivery{
+		URL:    delivery.URL,
+		Secret: delivery.Secret,
+		Event:  string(delivery.Event),
+		ID:     delivery.ID,
+		Body:   delivery.Payload,
+	})
+
+	retry := true
+	if err != nil {
+		reason := err.Error()
+		delivery.Error = &reason
+		retry = !errors.Is(err, webhook.ErrUnsupportedURL) && !errors.Is(err, webhook.ErrForbiddenAddress)
+	} else {
+		durationMS := int(resp.Duration.Milliseconds())
+		delivery.ResponseStatus = &resp.StatusCode
+		delivery.ResponseBody = &resp.Body
+		delivery.DurationMS = &durationMS
+		if resp.OK() {
+			delivery.Status = models.WebhookDeliverySucceeded
+		}
+		retry = resp.Temporary()
+	}
+
+	var retryAt *time.Time
+	if delivery.Status != models.WebhookDeliverySucceeded && retry && delivery.Attempts < maxWebhookAttempts {
+		t := time.Now().Add(webhookBackoff(delivery.Attempts))
+		retryAt = &t
+	}
+	if delivery.Status != models.WebhookDeliverySucceeded {
+		w.logger.Infow("Failed to deliver webhook", "webhook", delivery.WebhookID, "delivery", delivery.ID,
+			"attempt", delivery.Attempts, "retry", retryAt != nil, "status", delivery.ResponseStatus, "error", err)
+	}
+	return true, w.store.Webhook.SaveAttempt(ctx, delivery, retryAt)
+}
+
+// webhookBackoff is the delay before retrying a delivery after attempts
+// failed attempts.
+func webhookBackoff(attempts int) time.Duration {
+	delay := webhookRetryDelay
+	for i := 1; i < attempts && delay < maxWebhookRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxWebhookRetryDelay)
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// queueWorker runs a pool of goroutines draining a table-backed job queue.
+// next claims and handles a single job and reports whether there was one.
+// Workers sleep when the queue is empty until they are notified of new work
+// or the poll interval elapses, which also picks up jobs queued by other
+// instances.
+type queueWorker struct {
+	name    string
+	workers int
+	poll    time.Duration
+	wake    chan struct{}
+	next    func(context.Context) (bool, error)
+	logger  *zap.SugaredLogger
+}
+
+func newQueueWorker(name string, workers int, poll time.Duration, logger *zap.SugaredLogger, next func(context.Context) (bool, error)) *queueWorker {
+	return &queueWorker{
+		name:    name,
+		workers: max(1, workers),
+		poll:    poll,
+		wake:    make(chan struct{}, 1),
+		next:    next,
+		logger:  logger,
+	}
+}
+
+// Notify wakes an idle worker after a job has been queued.
+func (w *queueWorker) Notify() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run processes jobs until ctx is cancelled.
+func (w *queueWorker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < w.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (w *queueWorker) work(ctx context.Context) {
+	ticker := time.NewTicker(w.poll)
+	defer ticker.Stop()
+
+	for {
+		// Drain the queue before going back to sleep.
+		for ctx.Err() == nil {
+			processed, err := w.next(ctx)
+			if err != nil {
+				w.logger.Errorw("Background job failed", "worker", w.name, "error", err)
+				break
+			}
+			if !processed {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.wake:
+		case <-ticker.C:
+		}
+	}
+}
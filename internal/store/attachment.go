@@ -0,0 +1,306 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"github.com/lib/pq"
+)
+
+type AttachmentStorage struct {
+	db *sql.DB
+}
+
+const attachmentColumns = `id, user_id, post_id, comment_id, storage_key, filename, content_type, size_bytes, status, width, height, created_at`
+
+func (s *AttachmentStorage) Create(ctx context.Context, a *models.Attachment) error {
+	query := `
+		INSERT INTO attachments (user_id, storage_key, filename, content_type, size_bytes, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.db.QueryRowContext(ctx, query, a.UserID, a.StorageKey, a.Filename, a.ContentType, a.Size, a.Status).Scan(&a.ID, &a.CreatedAt)
+}
+
+func (s *AttachmentStorage) GetByID(ctx context.Context, id int64) (*models.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE id = $1`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	a, err := scanAttachment(s.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.ErrAttachmentNotFound
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+// GetByIDs returns the attachments with the given ids. Unknown ids are
+// skipped.
+func (s *AttachmentStorage) GetByIDs(ctx context.Context, ids []int64) ([]*models.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE id = ANY($1)`
+	byID, err := s.getAttachments(ctx, query, ids, func(a *models.Attachment) int64 { return a.ID })
+	if err != nil {
+		return nil, err
+	}
+
+	attachments := make([]*models.Attachment, 0, len(byID))
+	for _, list := range byID {
+		attachments = append(attachments, list...)
+	}
+	return attachments, nil
+}
+
+// linkAttachments attaches the user's unlinked uploads ids to a post or
+// comment in tx by setting column (always a constant supplied by the caller)
+// to targetID. Either all of the attachments are linked or
+// ErrAttachmentNotFound is returned.
+func linkAttachments(ctx context.Context, tx *sql.Tx, column string, userID, targetID int64, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query := `
+		UPDATE attachments SET ` + column + ` = $1
+		WHERE id = ANY($2) AND user_id = $3 AND post_id IS NULL AND comment_id IS NULL
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	res, err := tx.ExecContext(ctx, query, targetID, pq.Array(ids), userID)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != int64(len(ids)) {
+		return apperrors.ErrAttachmentNotFound
+	}
+	return nil
+}
+
+// GetForPosts returns the attachments of each of the given posts keyed by
+// post id.
+func (s *AttachmentStorage) GetForPosts(ctx context.Context, postIDs []int64) (map[int64][]*models.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE post_id = ANY($1) ORDER BY id`
+	return s.getAttachments(ctx, query, postIDs, func(a *models.Attachment) int64 { return *a.PostID })
+}
+
+// GetForComments returns the attachments of each of the given comments keyed
+// by comment id.
+func (s *AttachmentStorage) GetForComments(ctx context.Context, commentIDs []int64) (map[int64][]*models.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE comment_id = ANY($1) ORDER BY id`
+	return s.getAttachments(ctx, query, commentIDs, func(a *models.Attachment) int64 { return *a.CommentID })
+}
+
+func (s *AttachmentStorage) getAttachments(ctx context.Context, query string, ids []int64, key func(*models.Attachment) int64) (map[int64][]*models.Attachment, error) {
+	attachments := make(map[int64][]*models.Attachment)
+	if len(ids) == 0 {
+		return attachments, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		k := key(a)
+		attachments[k] = append(attachments[k], a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// ClaimNext marks the oldest pending attachment as processing and returns it.
+// Attachments left processing for longer than staleAfter, for example by a
+// worker that crashed, are claimed again. ErrAttachmentNotFound is returned
+// when there is nothing to process.
+func (s *AttachmentStorage) ClaimNext(ctx context.Context, staleAfter time.Duration) (*models.Attachment, error) {
+	query := `
+		UPDATE attachments SET status = 'processing', processing_started_at = NOW(), attempts = attempts + 1
+		WHERE id = (
+			SELECT id FROM attachments
+			WHERE status = 'pending'
+			   OR (status = 'processing' AND processing_started_at < NOW() - make_interval(secs => $1))
+			ORDER BY id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING ` + attachmentColumns + `, attempts
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var attempts int
+	a, err := scanAttachment(s.db.QueryRowContext(ctx, query, staleAfter.Seconds()), &attempts)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.ErrAttachmentNotFound
+		}
+		return nil, err
+	}
+	a.Attempts = attempts
+	return a, nil
+}
+
+// MarkProcessed stores the result of processing an attachment: the storage
+// key, type and size of the re-encoded original, its dimensions and its
+// variants, replacing any variants from an earlier attempt.
+func (s *AttachmentStorage) MarkProcessed(ctx context.Context, a *models.Attachment, variants []*models.AttachmentVariant) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE attachments
+			SET status = 'ready', storage_key = $2, content_type = $3, size_bytes = $4, width = $5, height = $6,
+				processing_error = NULL, processed_at = NOW()
+			WHERE id = $1
+		`, a.ID, a.StorageKey, a.ContentType, a.Size, a.Width, a.Height)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM attachment_variants WHERE attachment_id = $1`, a.ID); err != nil {
+			return err
+		}
+
+		for _, v := range variants {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO attachment_variants (attachment_id, name, storage_key, content_type, size_bytes, width, height)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+			`, a.ID, v.Name, v.StorageKey, v.ContentType, v.Size, v.Width, v.Height)
+			if err != nil {
+				return err
+			}
+		}
+
+		a.Status = models.AttachmentReady
+		return nil
+	})
+}
+
+// MarkFailed records why processing an attachment failed. With retry the
+// attachment goes back to pending, otherwise it is failed for good.
+func (s *AttachmentStorage) MarkFailed(ctx context.Context, id int64, reason string, retry bool) error {
+	status := models.AttachmentFailed
+	if retry {
+		status = models.AttachmentPending
+	}
+
+	query := `UPDATE attachments SET status = $2, processing_error = $3 WHERE id = $1`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, id, status, reason)
+	return err
+}
+
+// GetVariants returns the variants of each of the given attachments keyed by
+// attachment id, smallest first.
+func (s *AttachmentStorage) GetVariants(ctx context.Context, attachmentIDs []int64) (map[int64][]*models.AttachmentVariant, error) {
+	variants := make(map[int64][]*models.AttachmentVariant)
+	if len(attachmentIDs) == 0 {
+		return variants, nil
+	}
+
+	query := `
+		SELECT attachment_id, name, storage_key, content_type, size_bytes, width, height
+		FROM attachment_variants
+		WHERE attachment_id = ANY($1)
+		ORDER BY attachment_id, width * height
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, pq.Array(attachmentIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v models.AttachmentVariant
+		if err := rows.Scan(&v.AttachmentID, &v.Name, &v.StorageKey, &v.ContentType, &v.Size, &v.Width, &v.Height); err != nil {
+			return nil, err
+		}
+		variants[v.AttachmentID] = append(variants[v.AttachmentID], &v)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return variants, nil
+}
+
+func (s *AttachmentStorage) GetVariant(ctx context.Context, attachmentID int64, name string) (*models.AttachmentVariant, error) {
+	query := `
+		SELECT attachment_id, name, storage_key, content_type, size_bytes, width, height
+		FROM attachment_variants
+		WHERE attachment_id = $1 AND name = $2
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var v models.AttachmentVariant
+	err := s.db.QueryRowContext(ctx, query, attachmentID, name).Scan(&v.AttachmentID, &v.Name, &v.StorageKey, &v.ContentType, &v.Size, &v.Width, &v.Height)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.ErrAttachmentNotFound
+		}
+		return nil, err
+	}
+	return &v, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanAttachment scans the attachmentColumns of row followed by extra.
+func scanAttachment(row rowScanner, extra ...any) (*models.Attachment, error) {
+	var a models.Attachment
+	var postID, commentID sql.NullInt64
+	var width, height sql.NullInt32
+
+	dest := []any{&a.ID, &a.UserID, &postID, &commentID, &a.StorageKey, &a.Filename, &a.ContentType, &a.Size, &a.Status, &width, &height, &a.CreatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	if width.Valid && height.Valid {
+		w, h := int(width.Int32), int(height.Int32)
+		a.Width, a.Height = &w, &h
+	}
+	if postID.Valid {
+		a.PostID = &postID.Int64
+	}
+	if commentID.Valid {
+		a.CommentID = &commentID.Int64
+	}
+
+	return &a, nil
+}
@@ -68,7 +68,9 @@ func (s *AuthStorage) handleUserCreationError(err error) error {
 	return err
 }
 
-func (s *AuthStorage) CreateAndInvite(ctx context.Context, user *models.User, token string, invitationExp time.Duration) error {
+// CreateAndInvite creates a user with an invitation, along with the outbox
+// messages returned by messages for the created user.
+func (s *AuthStorage) CreateAndInvite(ctx context.Context, user *models.User, token string, invitationExp time.Duration, messages func(*models.User) ([]*models.OutboxMessage, error)) error {
 	return withTx(ctx, s.db, func(tx *sql.Tx) error {
 		log.Println("Inside CreateAndInvite")
 		if err := s.createUser(ctx, tx, user); err != nil {
@@ -79,7 +81,11 @@ func (s *AuthStorage) CreateAndInvite(ctx context.Context, user *models.User, to
 			return err
 		}
 
-		return nil
+		outbox, err := messages(user)
+		if err != nil {
+			return err
+		}
+		return addToOutbox(ctx, tx, outbox)
 	})
 }
 
@@ -133,20 +139,19 @@ func (s *AuthStorage) GetUserFromInvitationToken(ctx context.Context, hashedToke
 	return &user, nil
 }
 
-func (s *AuthStorage) ActivateUser(ctx context.Context, userID int64) error {
-	query := `UPDATE users SET activated = true WHERE id = $1`
-	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
-	defer cancel()
-
-	_, err := s.db.ExecContext(ctx, query, userID)
-	return err
-}
-
-func (s *AuthStorage) DeleteInvitationToken(ctx context.Context, hashedToken string) error {
-	query := `DELETE FROM user_invitations WHERE encode(token, 'hex') = $1`
-	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
-	defer cancel()
+// ActivateUser activates a user and deletes their invitation, along with
+// queueing messages.
+func (s *AuthStorage) ActivateUser(ctx context.Context, userID int64, hashedToken string, messages []*models.OutboxMessage) error {
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+		defer cancel()
 
-	_, err := s.db.ExecContext(ctx, query, hashedToken)
-	return err
+		if _, err := tx.ExecContext(ctx, `UPDATE users SET activated = true WHERE id = $1`, userID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_invitations WHERE encode(token, 'hex') = $1`, hashedToken); err != nil {
+			return err
+		}
+		return addToOutbox(ctx, tx, messages)
+	})
 }
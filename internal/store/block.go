@@ -0,0 +1,198 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"github.com/lib/pq"
+)
+
+type BlockStorage struct {
+	db *sql.DB
+}
+
+// Block records that blockerID blocked blockedID and removes the follow
+// relations and pending follow requests between the two users.
+func (s *BlockStorage) Block(ctx context.Context, blockerID, blockedID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO user_blocks (blocker_id, blocked_id) VALUES ($1, $2)
+			ON CONFLICT (blocker_id, blocked_id) DO NOTHING
+		`, blockerID, blockedID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			WITH changed AS (
+				DELETE FROM followers
+				WHERE (user_id = $1 AND follower_id = $2) OR (user_id = $2 AND follower_id = $1)
+				RETURNING user_id, follower_id
+			)`+insertFollowEvents(models.FollowEventUnfollow), blockerID, blockedID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			DELETE FROM follow_requests
+			WHERE (user_id = $1 AND requester_id = $2) OR (user_id = $2 AND requester_id = $1)
+		`, blockerID, blockedID)
+		return err
+	})
+}
+
+// Unblock removes the block of blockedID by blockerID. Follow relations
+// removed by the block are not restored.
+func (s *BlockStorage) Unblock(ctx context.Context, blockerID, blockedID int64) error {
+	query := `DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2`
+	return s.delete(ctx, query, blockerID, blockedID, apperrors.ErrBlockNotFound)
+}
+
+// Mute hides the posts of mutedID from the feed of muterID.
+func (s *BlockStorage) Mute(ctx context.Context, muterID, mutedID int64) error {
+	query := `
+		INSERT INTO user_mutes (muter_id, muted_id) VALUES ($1, $2)
+		ON CONFLICT (muter_id, muted_id) DO NOTHING
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, muterID, mutedID)
+	return err
+}
+
+func (s *BlockStorage) Unmute(ctx context.Context, muterID, mutedID int64) error {
+	query := `DELETE FROM user_mutes WHERE muter_id = $1 AND muted_id = $2`
+	return s.delete(ctx, query, muterID, mutedID, apperrors.ErrMuteNotFound)
+}
+
+func (s *BlockStorage) delete(ctx context.Context, query string, userID, otherID int64, notFound error) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, query, userID, otherID)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// IsBlocked reports whether either user blocked the other.
+func (s *BlockStorage) IsBlocked(ctx context.Context, userID, otherID int64) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM user_blocks
+			WHERE (blocker_id = $1 AND blocked_id = $2) OR (blocker_id = $2 AND blocked_id = $1)
+		)
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var blocked bool
+	if err := s.db.QueryRowContext(ctx, query, userID, otherID).Scan(&blocked); err != nil {
+		return false, err
+	}
+	return blocked, nil
+}
+
+// BlockedAmong returns the users among ids that blocked userID or that userID
+// blocked.
+func (s *BlockStorage) BlockedAmong(ctx context.Context, userID int64, ids []int64) (map[int64]bool, error) {
+	blocked := make(map[int64]bool)
+	if len(ids) == 0 {
+		return blocked, nil
+	}
+
+	query := `
+		SELECT blocked_id FROM user_blocks WHERE blocker_id = $1 AND blocked_id = ANY($2)
+		UNION
+		SELECT blocker_id FROM user_blocks WHERE blocked_id = $1 AND blocker_id = ANY($2)
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, userID, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		blocked[id] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return blocked, nil
+}
+
+// GetBlocked returns up to limit users blocked by userID, most recent block
+// first, starting strictly after the (before, beforeID) keyset position when
+// before is non-nil.
+func (s *BlockStorage) GetBlocked(ctx context.Context, userID int64, before *time.Time, beforeID int64, limit int) ([]*models.UserListEntry, error) {
+	return s.list(ctx, "user_blocks", "blocker_id", "blocked_id", userID, before, beforeID, limit)
+}
+
+// GetMuted returns up to limit users muted by userID, most recent mute first,
+// starting strictly after the (before, beforeID) keyset position when before
+// is non-nil.
+func (s *BlockStorage) GetMuted(ctx context.Context, userID int64, before *time.Time, beforeID int64, limit int) ([]*models.UserListEntry, error) {
+	return s.list(ctx, "user_mutes", "muter_id", "muted_id", userID, before, beforeID, limit)
+}
+
+// list returns the users in otherColumn of table where ownColumn is userID.
+// The table and columns are constants supplied by the caller.
+func (s *BlockStorage) list(ctx context.Context, table, ownColumn, otherColumn string, userID int64, before *time.Time, beforeID int64, limit int) ([]*models.UserListEntry, error) {
+	query := `
+		SELECT u.id, u.username, u.display_name, u.avatar_attachment_id, u.created_at, t.created_at
+		FROM ` + table + ` t
+		INNER JOIN users u ON u.id = t.` + otherColumn + `
+		WHERE t.` + ownColumn + ` = $1
+			AND ($2::timestamptz IS NULL OR (t.created_at, u.id) < ($2, $3))
+		ORDER BY t.created_at DESC, u.id DESC
+		LIMIT $4
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, userID, before, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*models.UserListEntry{}
+	for rows.Next() {
+		var e models.UserListEntry
+		err := rows.Scan(&e.User.ID, &e.User.Username, &e.User.DisplayName, &e.User.AvatarID, &e.User.CreatedAt, &e.Since)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
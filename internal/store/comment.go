@@ -17,7 +17,10 @@ type CreateCommentRequest struct {
 	UserID int64
 	Content string
 }
-func (s *CommentStorage) Create(ctx context.Context, comment *models.Comment) (*models.Comment, error) {
+// Create stores a comment along with its mentions, the user's unlinked
+// uploads attachmentIDs and the outbox messages returned by messages for the
+// created comment. Either all of it is stored or none.
+func (s *CommentStorage) Create(ctx context.Context, comment *models.Comment, attachmentIDs []int64, messages func(*models.Comment) ([]*models.OutboxMessage, error)) (*models.Comment, error) {
 query := `
 		INSERT INTO comments (post_id, user_id, content)
 		VALUES ($1, $2, $3)
@@ -26,10 +29,26 @@ query := `
 	ctx,cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	row := s.db.QueryRowContext(ctx, query, comment.PostID, comment.UserID, comment.Content)
-
 	var c models.Comment
-	err := row.Scan(&c.ID, &c.PostID, &c.UserID, &c.Content, &c.CreatedAt, &c.UpdatedAt)
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		row := tx.QueryRowContext(ctx, query, comment.PostID, comment.UserID, comment.Content)
+		if err := row.Scan(&c.ID, &c.PostID, &c.UserID, &c.Content, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return err
+		}
+		c.Mentions = comment.Mentions
+		if err := createCommentMentions(ctx, tx, &c); err != nil {
+			return err
+		}
+		if err := linkAttachments(ctx, tx, "comment_id", c.UserID, c.ID, attachmentIDs); err != nil {
+			return err
+		}
+
+		outbox, err := messages(&c)
+		if err != nil {
+			return err
+		}
+		return addToOutbox(ctx, tx, outbox)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -40,7 +59,7 @@ query := `
 
 func (s *CommentStorage) GetByPostID(ctx context.Context, postID int64) ([]*models.Comment, error) {
 	query := `
-		SELECT c.id, c.post_id, c.user_id, c.content, c.created_at, c.updated_at, u.username, u.id
+		SELECT c.id, c.post_id, c.user_id, c.content, c.created_at, c.updated_at, u.username, u.id, u.display_name, u.avatar_attachment_id, u.created_at
 		FROM comments c
 		JOIN users u ON u.id = c.user_id
 		WHERE c.post_id = $1
@@ -57,9 +76,9 @@ func (s *CommentStorage) GetByPostID(ctx context.Context, postID int64) ([]*mode
 
 	for rows.Next(){
 		var c models.Comment
-		c.User = models.User{}
+		c.User = models.PublicUser{}
 
-		err := rows.Scan(&c.ID, &c.PostID, &c.UserID, &c.Content, &c.CreatedAt, &c.UpdatedAt, &c.User.Username, &c.User.ID)
+		err := rows.Scan(&c.ID, &c.PostID, &c.UserID, &c.Content, &c.CreatedAt, &c.UpdatedAt, &c.User.Username, &c.User.ID, &c.User.DisplayName, &c.User.AvatarID, &c.User.CreatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -68,3 +87,35 @@ func (s *CommentStorage) GetByPostID(ctx context.Context, postID int64) ([]*mode
 
 	return comments, nil
 }
+
+// GetCommenterIDs returns the ids of the users who commented on the post.
+func (s *CommentStorage) GetCommenterIDs(ctx context.Context, postID int64) ([]int64, error) {
+	query := `SELECT DISTINCT user_id FROM comments WHERE post_id = $1`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (s *CommentStorage) Delete(ctx context.Context, id int64) error {
+	query := `DELETE FROM comments WHERE id = $1`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, id)
+	return err
+}
@@ -0,0 +1,134 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/lib/pq"
+)
+
+type EngagementStorage struct {
+	db *sql.DB
+}
+
+// ToggleLike likes the post for the user, or removes the like if it already
+// exists. The counter on posts is maintained by a trigger on post_likes, so the
+// returned count is consistent with concurrent toggles from other users.
+func (s *EngagementStorage) ToggleLike(ctx context.Context, userID, postID int64) (bool, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var liked bool
+	var count int64
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		var err error
+		liked, err = toggleRow(ctx, tx, "post_likes", userID, postID)
+		if err != nil {
+			return err
+		}
+		return tx.QueryRowContext(ctx, `SELECT like_count FROM posts WHERE id = $1`, postID).Scan(&count)
+	})
+	if err != nil {
+		return false, 0, err
+	}
+
+	return liked, count, nil
+}
+
+func (s *EngagementStorage) ToggleBookmark(ctx context.Context, userID, postID int64) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var bookmarked bool
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		var err error
+		bookmarked, err = toggleRow(ctx, tx, "post_bookmarks", userID, postID)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return bookmarked, nil
+}
+
+// toggleRow deletes the (user_id, post_id) row from table or inserts it when
+// nothing was deleted, reporting whether the row exists afterwards. table is
+// always a constant supplied by the caller.
+func toggleRow(ctx context.Context, tx *sql.Tx, table string, userID, postID int64) (bool, error) {
+	res, err := tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE user_id = $1 AND post_id = $2`, userID, postID)
+	if err != nil {
+		return false, err
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if deleted > 0 {
+		return false, nil
+	}
+
+	query := `INSERT INTO ` + table + ` (user_id, post_id) VALUES ($1, $2) ON CONFLICT (user_id, post_id) DO NOTHING`
+	if _, err := tx.ExecContext(ctx, query, userID, postID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetBookmarks returns up to limit bookmarked posts of the user, newest
+// bookmark first, starting strictly after the (before, beforeID) keyset
+// position when before is non-nil.
+func (s *EngagementStorage) GetBookmarks(ctx context.Context, userID int64, before *time.Time, beforeID int64, limit int) ([]*models.FeedItem, error) {
+	query := `
+		SELECT
+			p.id, p.user_id, p.title, p.content, p.tags, p.visibility, p.created_at, p.updated_at, p.version,
+			u.id, u.username, u.display_name, u.avatar_attachment_id, u.created_at,
+			p.like_count,
+			EXISTS (SELECT 1 FROM post_likes pl WHERE pl.post_id = p.id AND pl.user_id = $1),
+			b.created_at
+		FROM post_bookmarks b
+		INNER JOIN posts p ON p.id = b.post_id
+		INNER JOIN users u ON u.id = p.user_id
+		WHERE b.user_id = $1
+			AND ($2::timestamptz IS NULL OR (b.created_at, b.post_id) < ($2, $3))
+			AND ` + visiblePostClause("p", 1) + `
+		ORDER BY b.created_at DESC, b.post_id DESC
+		LIMIT $4
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, userID, before, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*models.FeedItem{}
+	for rows.Next() {
+		var post models.Post
+		var author models.PublicUser
+		var bookmarkedAt time.Time
+		item := &models.FeedItem{Post: &post, Author: &author, BookmarkedByMe: true}
+
+		err := rows.Scan(
+			&post.ID, &post.UserID, &post.Title, &post.Content, pq.Array(&post.Tags), &post.Visibility,
+			&post.CreatedAt, &post.UpdatedAt, &post.Version,
+			&author.ID, &author.Username, &author.DisplayName, &author.AvatarID, &author.CreatedAt,
+			&item.LikeCount, &item.LikedByMe, &bookmarkedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		item.BookmarkedAt = &bookmarkedAt
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
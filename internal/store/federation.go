@@ -0,0 +1,378 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/lib/pq"
+)
+
+type FederationStorage struct {
+	db *sql.DB
+}
+
+// GetKeys returns the key pair of a local account, or ErrNotFound before one
+// has been created.
+func (s *FederationStorage) GetKeys(ctx context.Context, userID int64) (*models.ActorKeys, error) {
+	query := `SELECT user_id, public_key_pem, private_key_pem FROM actor_keys WHERE user_id = $1`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var keys models.ActorKeys
+	if err := s.db.QueryRowContext(ctx, query, userID).Scan(&keys.UserID, &keys.PublicKeyPEM, &keys.PrivateKeyPEM); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &keys, nil
+}
+
+// CreateKeys stores the key pair of a local account unless it already has
+// one, and returns the pair that is stored.
+func (s *FederationStorage) CreateKeys(ctx context.Context, keys *models.ActorKeys) (*models.ActorKeys, error) {
+	query := `
+		INSERT INTO actor_keys (user_id, public_key_pem, private_key_pem)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO NOTHING
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	if _, err := s.db.ExecContext(ctx, query, keys.UserID, keys.PublicKeyPEM, keys.PrivateKeyPEM); err != nil {
+		return nil, err
+	}
+	return s.GetKeys(ctx, keys.UserID)
+}
+
+const remoteActorColumns = `
+	ra.user_id, ra.uri, ra.key_id, ra.public_key_pem, ra.inbox, COALESCE(ra.shared_inbox, ''), COALESCE(ra.profile_url, ''),
+	u.username, u.domain, u.display_name, u.bio, u.is_private, ra.fetched_at
+`
+
+func (s *FederationStorage) getRemoteActor(ctx context.Context, where string, arg any) (*models.RemoteActor, error) {
+	query := `SELECT ` + remoteActorColumns + ` FROM remote_actors ra JOIN users u ON u.id = ra.user_id WHERE ` + where
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var a models.RemoteActor
+	err := s.db.QueryRowContext(ctx, query, arg).Scan(
+		&a.UserID, &a.URI, &a.KeyID, &a.PublicKeyPEM, &a.Inbox, &a.SharedInbox, &a.ProfileURL,
+		&a.Username, &a.Domain, &a.DisplayName, &a.Bio, &a.IsPrivate, &a.FetchedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+// GetRemoteActor returns the remote account stored as the user userID, or
+// ErrNotFound for local accounts.
+func (s *FederationStorage) GetRemoteActor(ctx context.Context, userID int64) (*models.RemoteActor, error) {
+	return s.getRemoteActor(ctx, `ra.user_id = $1`, userID)
+}
+
+// GetRemoteActorByURI returns the remote account with the given actor id.
+func (s *FederationStorage) GetRemoteActorByURI(ctx context.Context, uri string) (*models.RemoteActor, error) {
+	return s.getRemoteActor(ctx, `ra.uri = $1`, uri)
+}
+
+// GetRemoteActorByKeyID returns the remote account owning the given key.
+func (s *FederationStorage) GetRemoteActorByKeyID(ctx context.Context, keyID string) (*models.RemoteActor, error) {
+	return s.getRemoteActor(ctx, `ra.key_id = $1`, keyID)
+}
+
+// SaveRemoteActor stores a fetched remote account, creating the user it is
+// stored as the first time it is seen and refreshing its profile and key
+// afterwards. actor.UserID is set to that user.
+func (s *FederationStorage) SaveRemoteActor(ctx context.Context, actor *models.RemoteActor) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `SELECT user_id FROM remote_actors WHERE uri = $1 FOR UPDATE`, actor.URI).Scan(&actor.UserID)
+		switch {
+		case err == sql.ErrNoRows:
+			err = tx.QueryRowContext(ctx, `
+				INSERT INTO users (username, domain, display_name, bio, is_private)
+				VALUES ($1, $2, $3, $4, $5)
+				RETURNING id
+			`, actor.Username+"@"+actor.Domain, actor.Domain, actor.DisplayName, actor.Bio, actor.IsPrivate).Scan(&actor.UserID)
+			if err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO remote_actors (user_id, uri, key_id, public_key_pem, inbox, shared_inbox, profile_url)
+				VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''))
+			`, actor.UserID, actor.URI, actor.KeyID, actor.PublicKeyPEM, actor.Inbox, actor.SharedInbox, actor.ProfileURL)
+			return err
+		case err != nil:
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			UPDATE users SET display_name = $2, bio = $3, is_private = $4, updated_at = NOW()
+			WHERE id = $1
+		`, actor.UserID, actor.DisplayName, actor.Bio, actor.IsPrivate)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `
+			UPDATE remote_actors
+			SET key_id = $2, public_key_pem = $3, inbox = $4, shared_inbox = NULLIF($5, ''), profile_url = NULLIF($6, ''), fetched_at = NOW()
+			WHERE user_id = $1
+		`, actor.UserID, actor.KeyID, actor.PublicKeyPEM, actor.Inbox, actor.SharedInbox, actor.ProfileURL)
+		return err
+	})
+}
+
+// DeleteRemoteActor removes a remote account along with its posts.
+func (s *FederationStorage) DeleteRemoteActor(ctx context.Context, userID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM posts WHERE user_id = $1`, userID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = $1 AND domain IS NOT NULL`, userID)
+		return err
+	})
+}
+
+// GetFollowerInboxes returns the inboxes of the remote followers of userID,
+// using the shared inbox of their server where there is one so each server
+// receives an activity once.
+func (s *FederationStorage) GetFollowerInboxes(ctx context.Context, userID int64) ([]string, error) {
+	query := `
+		SELECT DISTINCT COALESCE(ra.shared_inbox, ra.inbox)
+		FROM followers f
+		JOIN remote_actors ra ON ra.user_id = f.follower_id
+		WHERE f.user_id = $1
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var inboxes []string
+	for rows.Next() {
+		var inbox string
+		if err := rows.Scan(&inbox); err != nil {
+			return nil, err
+		}
+		inboxes = append(inboxes, inbox)
+	}
+	return inboxes, rows.Err()
+}
+
+// HasLocalFollowers reports whether any local account follows userID.
+func (s *FederationStorage) HasLocalFollowers(ctx context.Context, userID int64) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM followers f
+			JOIN users u ON u.id = f.follower_id
+			WHERE f.user_id = $1 AND u.domain IS NULL
+		)
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var exists bool
+	err := s.db.QueryRowContext(ctx, query, userID).Scan(&exists)
+	return exists, err
+}
+
+// SaveFollowActivity records the Follow activity behind followerID following,
+// or asking to follow, userID.
+func (s *FederationStorage) SaveFollowActivity(ctx context.Context, userID, followerID int64, activityID string) error {
+	query := `
+		INSERT INTO follow_activities (user_id, follower_id, activity_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, follower_id) DO UPDATE SET activity_id = EXCLUDED.activity_id, created_at = NOW()
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, userID, followerID, activityID)
+	return err
+}
+
+// GetFollowActivity returns the id of the Follow activity recorded for
+// followerID following userID, or ErrNotFound when both are local.
+func (s *FederationStorage) GetFollowActivity(ctx context.Context, userID, followerID int64) (string, error) {
+	query := `SELECT activity_id FROM follow_activities WHERE user_id = $1 AND follower_id = $2`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var id string
+	if err := s.db.QueryRowContext(ctx, query, userID, followerID).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return id, nil
+}
+
+// GetFollowByActivity returns the followed account and the follower of a
+// recorded Follow activity.
+func (s *FederationStorage) GetFollowByActivity(ctx context.Context, activityID string) (int64, int64, error) {
+	query := `SELECT user_id, follower_id FROM follow_activities WHERE activity_id = $1`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var userID, followerID int64
+	if err := s.db.QueryRowContext(ctx, query, activityID).Scan(&userID, &followerID); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, 0, ErrNotFound
+		}
+		return 0, 0, err
+	}
+	return userID, followerID, nil
+}
+
+// DeleteFollowActivity forgets the Follow activity of followerID following
+// userID.
+func (s *FederationStorage) DeleteFollowActivity(ctx context.Context, userID, followerID int64) error {
+	query := `DELETE FROM follow_activities WHERE user_id = $1 AND follower_id = $2`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, userID, followerID)
+	return err
+}
+
+// CreateRemotePost stores a post of a remote account under the id of its
+// object. It reports false without error when the object was already
+// stored, leaving post.ID unset.
+func (s *FederationStorage) CreateRemotePost(ctx context.Context, post *models.Post, apID string) (bool, error) {
+	query := `
+		INSERT INTO posts (content, title, user_id, tags, visibility, ap_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		ON CONFLICT (ap_id) DO NOTHING
+		RETURNING id, created_at, updated_at, version
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.QueryRowContext(ctx, query, post.Content, post.Title, post.UserID, pq.Array(post.Tags), post.Visibility, apID, post.CreatedAt).
+		Scan(&post.ID, &post.CreatedAt, &post.UpdatedAt, &post.Version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteRemotePost deletes the post stored for the object apID, provided it
+// was written by authorID. Deleting a post that is not stored succeeds.
+func (s *FederationStorage) DeleteRemotePost(ctx context.Context, authorID int64, apID string) error {
+	query := `DELETE FROM posts WHERE ap_id = $1 AND user_id = $2`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, apID, authorID)
+	return err
+}
+
+// SetLike likes or unlikes a post for the user. Unlike ToggleLike it is
+// idempotent, as activities may be delivered more than once.
+func (s *FederationStorage) SetLike(ctx context.Context, userID, postID int64, liked bool) error {
+	query := `DELETE FROM post_likes WHERE user_id = $1 AND post_id = $2`
+	if liked {
+		query = `INSERT INTO post_likes (user_id, post_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	}
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, userID, postID)
+	return err
+}
+
+// EnqueueDeliveries queues an activity of senderID for delivery to each of
+// the inboxes.
+func (s *FederationStorage) EnqueueDeliveries(ctx context.Context, senderID int64, inboxes []string, activity []byte) error {
+	if len(inboxes) == 0 {
+		return nil
+	}
+	query := `
+		INSERT INTO federation_deliveries (sender_id, inbox, activity)
+		SELECT $1, unnest($2::text[]), $3
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, senderID, pq.Array(inboxes), string(activity))
+	return err
+}
+
+// ClaimDelivery marks the delivery that is due the longest as in progress
+// and returns it, with the number of attempts made so far including this
+// one. Deliveries left in progress for longer than staleAfter are claimed
+// again. ErrNotFound is returned when nothing is due.
+func (s *FederationStorage) ClaimDelivery(ctx context.Context, staleAfter time.Duration) (*models.Delivery, error) {
+	query := `
+		UPDATE federation_deliveries SET status = 'delivering', started_at = NOW(), attempts = attempts + 1
+		WHERE id = (
+			SELECT id FROM federation_deliveries
+			WHERE (status = 'pending' AND next_attempt_at <= NOW())
+			   OR (status = 'delivering' AND started_at < NOW() - make_interval(secs => $1))
+			ORDER BY next_attempt_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING id, sender_id, inbox, activity, attempts
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var d models.Delivery
+	var activity string
+	err := s.db.QueryRowContext(ctx, query, staleAfter.Seconds()).Scan(&d.ID, &d.SenderID, &d.Inbox, &activity, &d.Attempts)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	d.Activity = []byte(activity)
+	return &d, nil
+}
+
+// CompleteDelivery removes a delivered activity from the queue.
+func (s *FederationStorage) CompleteDelivery(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, `DELETE FROM federation_deliveries WHERE id = $1`, id)
+	return err
+}
+
+// FailDelivery records why a delivery failed. It is retried at retryAt, or
+// given up on when retryAt is nil.
+func (s *FederationStorage) FailDelivery(ctx context.Context, id int64, reason string, retryAt *time.Time) error {
+	query := `
+		UPDATE federation_deliveries
+		SET status = CASE WHEN $3::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+			next_attempt_at = COALESCE($3, next_attempt_at),
+			error = $2
+		WHERE id = $1
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, id, reason, retryAt)
+	return err
+}
@@ -3,30 +3,118 @@ package store
 import (
 	"context"
 	"database/sql"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"github.com/lib/pq"
 )
 
 type FollowStorage struct {
 	db *sql.DB
 }
 
-func (s *FollowStorage) FollowUser(ctx context.Context, currentUserID int64, userID int64) error {
-	query := `INSERT INTO followers (user_id, follower_id) VALUES ($1, $2) ON CONFLICT (user_id, follower_id) DO NOTHING`
-	if _, err := s.db.ExecContext(ctx, query, currentUserID, userID); err != nil {
-		return err
+// insertFollowEvents returns a statement that records a follow event of kind
+// for every row of the "changed" CTE it is appended to.
+func insertFollowEvents(kind models.FollowEventKind) string {
+	return `
+		INSERT INTO follow_events (user_id, follower_id, kind)
+		SELECT user_id, follower_id, '` + string(kind) + `' FROM changed
+	`
+}
+
+// claimFollowEvents locks up to limit follow events that the consumer
+// recording its progress in the marker column hasn't handled yet, skipping
+// those locked by other instances, and returns their ids in order. Events are
+// picked by marker rather than by position, as ids are taken before commit
+// and an event can show up after events with higher ids were handled.
+func claimFollowEvents(ctx context.Context, tx *sql.Tx, marker string, limit int) ([]int64, error) {
+	query := `
+		SELECT id FROM follow_events
+		WHERE NOT ` + marker + `
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`
+	rows, err := tx.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
 }
 
-func (s *FollowStorage) UnfollowUser(ctx context.Context, currentUserID int64, userID int64) error {
-	query := `DELETE FROM followers WHERE user_id = $1 AND follower_id = $2`
-	if _, err := s.db.ExecContext(ctx, query, currentUserID, userID); err != nil {
-		return err
+// markFollowEvents records that the consumer of marker handled the events.
+func markFollowEvents(ctx context.Context, tx *sql.Tx, marker string, ids []int64) error {
+	_, err := tx.ExecContext(ctx, `UPDATE follow_events SET `+marker+` = TRUE WHERE id = ANY($1)`, pq.Array(ids))
+	return err
+}
+
+// Follow makes followerID follow userID and records a follow event. It
+// reports false without error when followerID already follows userID.
+func (s *FollowStorage) Follow(ctx context.Context, followerID, userID int64) (bool, error) {
+	query := `
+		WITH changed AS (
+			INSERT INTO followers (user_id, follower_id) VALUES ($1, $2)
+			RETURNING user_id, follower_id
+		)` + insertFollowEvents(models.FollowEventFollow)
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, userID, followerID)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code {
+			case "23505":
+				return false, nil
+			case "23503":
+				return false, apperrors.ErrUserNotFound
+			case "23514":
+				return false, apperrors.ErrSelfFollow
+			}
+		}
+		return false, err
 	}
-	return nil
+	return true, nil
+}
+
+// Unfollow stops followerID from following userID and records an unfollow
+// event. It reports false without error when followerID did not follow
+// userID.
+func (s *FollowStorage) Unfollow(ctx context.Context, followerID, userID int64) (bool, error) {
+	query := `
+		WITH changed AS (
+			DELETE FROM followers WHERE user_id = $1 AND follower_id = $2
+			RETURNING user_id, follower_id
+		)` + insertFollowEvents(models.FollowEventUnfollow)
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, query, userID, followerID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
 }
 
 func (s *FollowStorage) GetFollowerCount(ctx context.Context, userID int64) (int64, error) {
 	query := `SELECT COUNT(*) FROM followers WHERE user_id = $1`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
 	var count int64
 	if err := s.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
 		return 0, err
@@ -36,6 +124,9 @@ func (s *FollowStorage) GetFollowerCount(ctx context.Context, userID int64) (int
 
 func (s *FollowStorage) GetFollowingCount(ctx context.Context, userID int64) (int64, error) {
 	query := `SELECT COUNT(*) FROM followers WHERE follower_id = $1`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
 	var count int64
 	if err := s.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
 		return 0, err
@@ -43,11 +134,110 @@ func (s *FollowStorage) GetFollowingCount(ctx context.Context, userID int64) (in
 	return count, nil
 }
 
-func (s *FollowStorage) IsFollowing(ctx context.Context, currentUserID, userID int64) (bool, error) {
+// IsFollowing reports whether followerID follows userID.
+func (s *FollowStorage) IsFollowing(ctx context.Context, followerID, userID int64) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM followers WHERE user_id = $1 AND follower_id = $2)`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
 	var exists bool
-	if err := s.db.QueryRowContext(ctx, query, userID, currentUserID).Scan(&exists); err != nil {
+	if err := s.db.QueryRowContext(ctx, query, userID, followerID).Scan(&exists); err != nil {
 		return false, err
 	}
 	return exists, nil
 }
+
+// GetEvents returns up to limit follow events with an id greater than
+// afterID, oldest first, so consumers can resume from the last event they
+// handled.
+func (s *FollowStorage) GetEvents(ctx context.Context, afterID int64, limit int) ([]*models.FollowEvent, error) {
+	query := `
+		SELECT id, user_id, follower_id, kind, created_at
+		FROM follow_events
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*models.FollowEvent{}
+	for rows.Next() {
+		var e models.FollowEvent
+		if err := rows.Scan(&e.ID, &e.UserID, &e.FollowerID, &e.Kind, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// GetFollowers returns up to limit users following userID, most recent follow
+// first, starting strictly after the (before, beforeID) keyset position when
+// before is non-nil. The flags on each entry are relative to viewerID.
+func (s *FollowStorage) GetFollowers(ctx context.Context, userID, viewerID int64, before *time.Time, beforeID int64, limit int) ([]*models.FollowEntry, error) {
+	return s.list(ctx, "follower_id", "user_id", userID, viewerID, before, beforeID, limit)
+}
+
+// GetFollowing returns up to limit users followed by userID, most recent
+// follow first, starting strictly after the (before, beforeID) keyset position
+// when before is non-nil. The flags on each entry are relative to viewerID.
+func (s *FollowStorage) GetFollowing(ctx context.Context, userID, viewerID int64, before *time.Time, beforeID int64, limit int) ([]*models.FollowEntry, error) {
+	return s.list(ctx, "user_id", "follower_id", userID, viewerID, before, beforeID, limit)
+}
+
+// list returns the users on the other side of the follow relations where
+// ownColumn is userID. Both columns are constants supplied by the caller.
+func (s *FollowStorage) list(ctx context.Context, otherColumn, ownColumn string, userID, viewerID int64, before *time.Time, beforeID int64, limit int) ([]*models.FollowEntry, error) {
+	query := `
+		SELECT
+			u.id, u.username, u.display_name, u.avatar_attachment_id, u.created_at,
+			f.created_at,
+			EXISTS (SELECT 1 FROM followers v WHERE v.user_id = $2 AND v.follower_id = u.id),
+			EXISTS (SELECT 1 FROM followers v WHERE v.user_id = u.id AND v.follower_id = $2)
+		FROM followers f
+		INNER JOIN users u ON u.id = f.` + otherColumn + `
+		WHERE f.` + ownColumn + ` = $1
+			AND ($3::timestamptz IS NULL OR (f.created_at, u.id) < ($3, $4))
+		ORDER BY f.created_at DESC, u.id DESC
+		LIMIT $5
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, userID, viewerID, before, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*models.FollowEntry{}
+	for rows.Next() {
+		var e models.FollowEntry
+		err := rows.Scan(
+			&e.User.ID, &e.User.Username, &e.User.DisplayName, &e.User.AvatarID, &e.User.CreatedAt,
+			&e.FollowedAt, &e.FollowsYou, &e.YouFollow,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,148 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+)
+
+type FollowRequestStorage struct {
+	db *sql.DB
+}
+
+// Create records a request by requesterID to follow userID. Repeating a
+// pending request keeps the original one.
+func (s *FollowRequestStorage) Create(ctx context.Context, userID, requesterID int64) error {
+	query := `
+		INSERT INTO follow_requests (user_id, requester_id) VALUES ($1, $2)
+		ON CONFLICT (user_id, requester_id) DO NOTHING
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, userID, requesterID)
+	return err
+}
+
+// Delete removes the request by requesterID to follow userID. It returns
+// ErrFollowRequestNotFound when there is no such request.
+func (s *FollowRequestStorage) Delete(ctx context.Context, userID, requesterID int64) error {
+	query := `DELETE FROM follow_requests WHERE user_id = $1 AND requester_id = $2`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, query, userID, requesterID)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return apperrors.ErrFollowRequestNotFound
+	}
+	return nil
+}
+
+// Approve turns the request by requesterID to follow userID into a follow.
+func (s *FollowRequestStorage) Approve(ctx context.Context, userID, requesterID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `DELETE FROM follow_requests WHERE user_id = $1 AND requester_id = $2`, userID, requesterID)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return apperrors.ErrFollowRequestNotFound
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			WITH changed AS (
+				INSERT INTO followers (user_id, follower_id) VALUES ($1, $2)
+				ON CONFLICT (user_id, follower_id) DO NOTHING
+				RETURNING user_id, follower_id
+			)`+insertFollowEvents(models.FollowEventFollow), userID, requesterID)
+		return err
+	})
+}
+
+// ApproveAll turns every pending request to follow userID into a follow.
+func (s *FollowRequestStorage) ApproveAll(ctx context.Context, userID int64) error {
+	query := `
+		WITH approved AS (
+			DELETE FROM follow_requests WHERE user_id = $1
+			RETURNING user_id, requester_id
+		), changed AS (
+			INSERT INTO followers (user_id, follower_id)
+			SELECT user_id, requester_id FROM approved
+			ON CONFLICT (user_id, follower_id) DO NOTHING
+			RETURNING user_id, follower_id
+		)` + insertFollowEvents(models.FollowEventFollow)
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, userID)
+	return err
+}
+
+// Exists reports whether requesterID has a pending request to follow userID.
+func (s *FollowRequestStorage) Exists(ctx context.Context, userID, requesterID int64) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM follow_requests WHERE user_id = $1 AND requester_id = $2)`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, query, userID, requesterID).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// GetPending returns up to limit pending requests to follow userID, newest
+// first, starting strictly after the (before, beforeID) keyset position when
+// before is non-nil.
+func (s *FollowRequestStorage) GetPending(ctx context.Context, userID int64, before *time.Time, beforeID int64, limit int) ([]*models.FollowRequest, error) {
+	query := `
+		SELECT u.id, u.username, u.display_name, u.avatar_attachment_id, u.created_at, fr.created_at
+		FROM follow_requests fr
+		INNER JOIN users u ON u.id = fr.requester_id
+		WHERE fr.user_id = $1
+			AND ($2::timestamptz IS NULL OR (fr.created_at, fr.requester_id) < ($2, $3))
+		ORDER BY fr.created_at DESC, fr.requester_id DESC
+		LIMIT $4
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, userID, before, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := []*models.FollowRequest{}
+	for rows.Next() {
+		var fr models.FollowRequest
+		err := rows.Scan(&fr.User.ID, &fr.User.Username, &fr.User.DisplayName, &fr.User.AvatarID, &fr.User.CreatedAt, &fr.RequestedAt)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, &fr)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
@@ -0,0 +1,161 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/lib/pq"
+)
+
+type LinkPreviewStorage struct {
+	db *sql.DB
+}
+
+// ReplaceForPost links the post to the previews of urls, in order, replacing
+// its previous links. URLs seen for the first time are queued for fetching,
+// as are cached previews fetched longer than refreshAfter ago.
+func (s *LinkPreviewStorage) ReplaceForPost(ctx context.Context, postID int64, urls []string, refreshAfter time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		if len(urls) > 0 {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO link_previews (url)
+				SELECT unnest($1::text[])
+				ON CONFLICT (url) DO UPDATE SET status = 'pending', attempts = 0, next_attempt_at = NOW()
+				WHERE link_previews.status IN ('ready', 'failed')
+				  AND link_previews.fetched_at < NOW() - make_interval(secs => $2)
+			`, pq.Array(urls), refreshAfter.Seconds())
+			if err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM post_link_previews WHERE post_id = $1`, postID); err != nil {
+			return err
+		}
+
+		if len(urls) == 0 {
+			return nil
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO post_link_previews (post_id, url, position)
+			SELECT $1, t.url, t.position
+			FROM unnest($2::text[]) WITH ORDINALITY AS t(url, position)
+		`, postID, pq.Array(urls))
+		return err
+	})
+}
+
+// ClaimNext marks the oldest due preview as being fetched and returns its
+// URL and the number of attempts made so far, including this one. Previews
+// left fetching for longer than staleAfter are claimed again. ErrNotFound is
+// returned when there is nothing to fetch.
+func (s *LinkPreviewStorage) ClaimNext(ctx context.Context, staleAfter time.Duration) (string, int, error) {
+	query := `
+		UPDATE link_previews SET status = 'fetching', fetch_started_at = NOW(), attempts = attempts + 1
+		WHERE url = (
+			SELECT url FROM link_previews
+			WHERE (status = 'pending' AND next_attempt_at <= NOW())
+			   OR (status = 'fetching' AND fetch_started_at < NOW() - make_interval(secs => $1))
+			ORDER BY created_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING url, attempts
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var url string
+	var attempts int
+	err := s.db.QueryRowContext(ctx, query, staleAfter.Seconds()).Scan(&url, &attempts)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", 0, ErrNotFound
+		}
+		return "", 0, err
+	}
+	return url, attempts, nil
+}
+
+// Save stores a fetched preview.
+func (s *LinkPreviewStorage) Save(ctx context.Context, preview *models.LinkPreview) error {
+	query := `
+		UPDATE link_previews
+		SET status = 'ready', title = NULLIF($2, ''), description = NULLIF($3, ''), image_url = NULLIF($4, ''),
+			site_name = NULLIF($5, ''), error = NULL, fetched_at = NOW()
+		WHERE url = $1
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, preview.URL, preview.Title, preview.Description, preview.ImageURL, preview.SiteName)
+	return err
+}
+
+// MarkFailed records why fetching a preview failed. The fetch is retried at
+// retryAt, or the preview is failed until it is refreshed when it is nil. A
+// previously fetched preview keeps being served while a refresh is retried.
+func (s *LinkPreviewStorage) MarkFailed(ctx context.Context, url string, reason string, retryAt *time.Time) error {
+	query := `
+		UPDATE link_previews
+		SET status = CASE WHEN $3::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+			error = $2,
+			next_attempt_at = COALESCE($3, next_attempt_at),
+			fetched_at = CASE WHEN $3::timestamptz IS NULL THEN NOW() ELSE fetched_at END
+		WHERE url = $1
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, url, reason, retryAt)
+	return err
+}
+
+// GetForPosts returns the available previews of each of the given posts keyed
+// by post id, in the order the URLs appear in the post.
+func (s *LinkPreviewStorage) GetForPosts(ctx context.Context, postIDs []int64) (map[int64][]models.LinkPreview, error) {
+	previews := make(map[int64][]models.LinkPreview)
+	if len(postIDs) == 0 {
+		return previews, nil
+	}
+
+	query := `
+		SELECT plp.post_id, lp.url, COALESCE(lp.title, ''), COALESCE(lp.description, ''),
+			COALESCE(lp.image_url, ''), COALESCE(lp.site_name, '')
+		FROM post_link_previews plp
+		JOIN link_previews lp ON lp.url = plp.url
+		WHERE plp.post_id = ANY($1)
+		  AND lp.status <> 'failed'
+		  AND (lp.title IS NOT NULL OR lp.description IS NOT NULL OR lp.image_url IS NOT NULL)
+		ORDER BY plp.post_id, plp.position
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, pq.Array(postIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID int64
+		var p models.LinkPreview
+		if err := rows.Scan(&postID, &p.URL, &p.Title, &p.Description, &p.ImageURL, &p.SiteName); err != nil {
+			return nil, err
+		}
+		previews[postID] = append(previews[postID], p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return previews, nil
+}
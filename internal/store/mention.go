@@ -0,0 +1,165 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/lib/pq"
+)
+
+type MentionStorage struct {
+	db *sql.DB
+}
+
+// replacePostMentions makes post.Mentions the complete set of users
+// mentioned by post, in the transaction writing the post.
+func replacePostMentions(ctx context.Context, tx *sql.Tx, post *models.Post) error {
+	userIDs := make([]int64, 0, len(post.Mentions))
+	for _, m := range post.Mentions {
+		userIDs = append(userIDs, m.UserID)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mentions WHERE post_id = $1 AND NOT (mentioned_user_id = ANY($2))`, post.ID, pq.Array(userIDs)); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO mentions (mentioned_user_id, author_id, post_id)
+		SELECT unnest($1::BIGINT[]), $2, $3
+		ON CONFLICT (post_id, mentioned_user_id) WHERE post_id IS NOT NULL DO NOTHING
+	`
+	_, err := tx.ExecContext(ctx, query, pq.Array(userIDs), post.UserID, post.ID)
+	return err
+}
+
+// createCommentMentions saves comment.Mentions in the transaction writing
+// the comment.
+func createCommentMentions(ctx context.Context, tx *sql.Tx, comment *models.Comment) error {
+	if len(comment.Mentions) == 0 {
+		return nil
+	}
+	userIDs := make([]int64, 0, len(comment.Mentions))
+	for _, m := range comment.Mentions {
+		userIDs = append(userIDs, m.UserID)
+	}
+
+	query := `
+		INSERT INTO mentions (mentioned_user_id, author_id, comment_id)
+		SELECT unnest($1::BIGINT[]), $2, $3
+		ON CONFLICT (comment_id, mentioned_user_id) WHERE comment_id IS NOT NULL DO NOTHING
+	`
+	_, err := tx.ExecContext(ctx, query, pq.Array(userIDs), comment.UserID, comment.ID)
+	return err
+}
+
+// GetForPosts returns the mentions of each of the given posts keyed by post id.
+func (s *MentionStorage) GetForPosts(ctx context.Context, postIDs []int64) (map[int64][]models.Mention, error) {
+	query := `
+		SELECT m.post_id, u.id, u.username
+		FROM mentions m
+		INNER JOIN users u ON u.id = m.mentioned_user_id
+		WHERE m.post_id = ANY($1)
+		ORDER BY m.id
+	`
+	return s.getMentions(ctx, query, postIDs)
+}
+
+// GetForComments returns the mentions of each of the given comments keyed by
+// comment id.
+func (s *MentionStorage) GetForComments(ctx context.Context, commentIDs []int64) (map[int64][]models.Mention, error) {
+	query := `
+		SELECT m.comment_id, u.id, u.username
+		FROM mentions m
+		INNER JOIN users u ON u.id = m.mentioned_user_id
+		WHERE m.comment_id = ANY($1)
+		ORDER BY m.id
+	`
+	return s.getMentions(ctx, query, commentIDs)
+}
+
+func (s *MentionStorage) getMentions(ctx context.Context, query string, ids []int64) (map[int64][]models.Mention, error) {
+	mentions := make(map[int64][]models.Mention)
+	if len(ids) == 0 {
+		return mentions, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var m models.Mention
+		if err := rows.Scan(&id, &m.UserID, &m.Username); err != nil {
+			return nil, err
+		}
+		mentions[id] = append(mentions[id], m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return mentions, nil
+}
+
+// GetMentioningPosts returns up to limit posts visible to the user that mention
+// them, newest first, starting strictly after the (before, beforeID) keyset
+// position when before is non-nil.
+func (s *MentionStorage) GetMentioningPosts(ctx context.Context, userID int64, before *time.Time, beforeID int64, limit int) ([]*models.FeedItem, error) {
+	query := `
+		SELECT
+			p.id, p.user_id, p.title, p.content, p.tags, p.visibility, p.created_at, p.updated_at, p.version,
+			u.id, u.username, u.display_name, u.avatar_attachment_id, u.created_at,
+			p.like_count,
+			EXISTS (SELECT 1 FROM post_likes pl WHERE pl.post_id = p.id AND pl.user_id = $1),
+			EXISTS (SELECT 1 FROM post_bookmarks pb WHERE pb.post_id = p.id AND pb.user_id = $1)
+		FROM mentions m
+		INNER JOIN posts p ON p.id = m.post_id
+		INNER JOIN users u ON u.id = p.user_id
+		WHERE m.mentioned_user_id = $1
+			AND ($2::timestamptz IS NULL OR (p.created_at, p.id) < ($2, $3))
+			AND ` + visiblePostClause("p", 1) + `
+		ORDER BY p.created_at DESC, p.id DESC
+		LIMIT $4
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, userID, before, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*models.FeedItem{}
+	for rows.Next() {
+		var post models.Post
+		var author models.PublicUser
+		item := &models.FeedItem{Post: &post, Author: &author}
+
+		err := rows.Scan(
+			&post.ID, &post.UserID, &post.Title, &post.Content, pq.Array(&post.Tags), &post.Visibility,
+			&post.CreatedAt, &post.UpdatedAt, &post.Version,
+			&author.ID, &author.Username, &author.DisplayName, &author.AvatarID, &author.CreatedAt,
+			&item.LikeCount, &item.LikedByMe, &item.BookmarkedByMe,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
@@ -0,0 +1,298 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/lib/pq"
+)
+
+type NotificationStorage struct {
+	db *sql.DB
+}
+
+const notificationColumns = `id, user_id, actor_id, kind, post_id, comment_id, created_at, read_at`
+
+// notificationGroupKey groups likes of the same post and follows by the UTC
+// day they were made on, keeping read and unread notifications apart. Other
+// notifications are not grouped.
+const notificationGroupKey = `CASE WHEN n.kind IN ('like', 'follow')
+		THEN n.kind || ':' || COALESCE(n.post_id, 0) || ':' || (n.read_at IS NULL)::TEXT
+			|| ':' || (n.created_at AT TIME ZONE 'UTC')::DATE::TEXT
+		ELSE n.id::TEXT END`
+
+// notificationListWindow is how far back notification lists reach.
+const notificationListWindow = `INTERVAL '90 days'`
+
+// allowedNotificationClause returns a SQL predicate that holds when the
+// local account given by the SQL expression user may be notified of an
+// action of actor, of kind, about post. Actions of users blocked in either
+// direction or muted by the recipient are left out, as are posts the
+// recipient can't see and follow requests that are no longer pending. It is
+// checked both when notifications are created and when they are read, so
+// later blocks, mutes and visibility changes are honoured.
+func allowedNotificationClause(user, actor, kind, post string) string {
+	return fmt.Sprintf(`(EXISTS (SELECT 1 FROM users nu WHERE nu.id = %[1]s AND nu.domain IS NULL)
+		AND NOT EXISTS (
+			SELECT 1 FROM user_blocks nb
+			WHERE (nb.blocker_id = %[1]s AND nb.blocked_id = %[2]s)
+				OR (nb.blocker_id = %[2]s AND nb.blocked_id = %[1]s)
+		)
+		AND NOT EXISTS (SELECT 1 FROM user_mutes nm WHERE nm.muter_id = %[1]s AND nm.muted_id = %[2]s)
+		AND (%[3]s <> 'follow_request' OR EXISTS (
+			SELECT 1 FROM follow_requests nfr WHERE nfr.user_id = %[1]s AND nfr.requester_id = %[2]s
+		))
+		AND (%[4]s IS NULL OR EXISTS (
+			SELECT 1 FROM posts np WHERE np.id = %[4]s AND %[5]s
+		)))`, user, actor, kind, post, visiblePostClauseFor("np", user))
+}
+
+// Create notifies each of the recipients of an action of actorID about the
+// post and comment, if any. Recipients that may not be notified, the actor
+// itself and recipients already notified of the same action are skipped. It
+// returns the notifications created.
+func (s *NotificationStorage) Create(ctx context.Context, kind models.NotificationKind, actorID int64, recipientIDs []int64, postID, commentID *int64) ([]*models.Notification, error) {
+	if len(recipientIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `
+		INSERT INTO notifications (user_id, actor_id, kind, post_id, comment_id)
+		SELECT DISTINCT r.id, $2::BIGINT, $3::TEXT, $4::BIGINT, $5::BIGINT
+		FROM unnest($1::BIGINT[]) AS r(id)
+		WHERE r.id <> $2 AND ` + allowedNotificationClause("r.id", "$2::BIGINT", "$3::TEXT", "$4::BIGINT") + `
+		ON CONFLICT DO NOTHING
+		RETURNING ` + notificationColumns
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, pq.Array(recipientIDs), actorID, kind, postID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	return scanNotifications(rows)
+}
+
+// ApplyFollowEvents notifies users of the follows among up to limit follow
+// events not handled yet, provided the follow still holds. It
+// returns the notifications created and the number of events handled.
+func (s *NotificationStorage) ApplyFollowEvents(ctx context.Context, limit int) ([]*models.Notification, int, error) {
+	var created []*models.Notification
+	var handled int
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+		defer cancel()
+
+		// Concurrent instances skip the events being handled by others.
+		ids, err := claimFollowEvents(ctx, tx, "notified", limit)
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		handled = len(ids)
+
+		query := `
+			INSERT INTO notifications (user_id, actor_id, kind)
+			SELECT DISTINCT fe.user_id, fe.follower_id, 'follow'
+			FROM follow_events fe
+			WHERE fe.id = ANY($1) AND fe.kind = 'follow'
+				AND EXISTS (SELECT 1 FROM followers f WHERE f.user_id = fe.user_id AND f.follower_id = fe.follower_id)
+				AND ` + allowedNotificationClause("fe.user_id", "fe.follower_id", "'follow'", "NULL::BIGINT") + `
+			ON CONFLICT DO NOTHING
+			RETURNING ` + notificationColumns
+		rows, err := tx.QueryContext(ctx, query, pq.Array(ids))
+		if err != nil {
+			return err
+		}
+		if created, err = scanNotifications(rows); err != nil {
+			return err
+		}
+
+		return markFollowEvents(ctx, tx, "notified", ids)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return created, handled, nil
+}
+
+// List returns up to limit notification groups of userID whose latest
+// notification comes before the cursor (before, beforeID), latest first.
+// Only notifications of the last notificationListWindow are listed. Groups
+// don't span days, so notifications after the day of the cursor are left
+// out before grouping.
+func (s *NotificationStorage) List(ctx context.Context, userID int64, before *time.Time, beforeID int64, limit int) ([]*models.NotificationGroup, error) {
+	query := `
+		WITH visible AS (
+			SELECT n.id, n.actor_id, ` + notificationGroupKey + ` AS group_key
+			FROM notifications n
+			WHERE n.user_id = $1
+				AND n.created_at > NOW() - ` + notificationListWindow + `
+				AND ($2::timestamptz IS NULL
+					OR n.created_at < (($2::timestamptz AT TIME ZONE 'UTC')::DATE + 1)::TIMESTAMP AT TIME ZONE 'UTC')
+				AND ` + allowedNotificationClause("n.user_id", "n.actor_id", "n.kind", "n.post_id") + `
+		), groups AS (
+			SELECT MAX(id) AS latest_id, COUNT(DISTINCT actor_id) AS actor_count,
+				(ARRAY_AGG(actor_id ORDER BY id DESC))[1:3] AS actor_ids
+			FROM visible
+			GROUP BY group_key
+		)
+		SELECT n.id, n.kind, n.post_id, n.comment_id, n.created_at, n.read_at IS NOT NULL, g.actor_count, g.actor_ids
+		FROM groups g
+		INNER JOIN notifications n ON n.id = g.latest_id
+		WHERE $2::timestamptz IS NULL OR (n.created_at, n.id) < ($2, $3)
+		ORDER BY n.created_at DESC, n.id DESC
+		LIMIT $4
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, userID, before, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []*models.NotificationGroup{}
+	for rows.Next() {
+		var g models.NotificationGroup
+		err := rows.Scan(&g.ID, &g.Kind, &g.PostID, &g.CommentID, &g.CreatedAt, &g.Read, &g.ActorCount, pq.Array(&g.ActorIDs))
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, &g)
+	}
+	return groups, rows.Err()
+}
+
+// CountUnread returns the number of unread notifications of userID.
+func (s *NotificationStorage) CountUnread(ctx context.Context, userID int64) (int64, error) {
+	query := `
+		SELECT COUNT(*) FROM notifications n
+		WHERE n.user_id = $1 AND n.read_at IS NULL
+			AND ` + allowedNotificationClause("n.user_id", "n.actor_id", "n.kind", "n.post_id")
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var count int64
+	err := s.db.QueryRowContext(ctx, query, userID).Scan(&count)
+	return count, err
+}
+
+// GetSince returns up to limit notifications of userID created after the
+// notification afterID, oldest first.
+func (s *NotificationStorage) GetSince(ctx context.Context, userID, afterID int64, limit int) ([]*models.Notification, error) {
+	query := `
+		SELECT ` + notificationColumns + `
+		FROM notifications n
+		WHERE n.user_id = $1 AND n.id > $2
+			AND ` + allowedNotificationClause("n.user_id", "n.actor_id", "n.kind", "n.post_id") + `
+		ORDER BY n.id
+		LIMIT $3
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, userID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanNotifications(rows)
+}
+
+// LatestID returns the id of the latest notification of userID, or 0 when
+// there is none.
+func (s *NotificationStorage) LatestID(ctx context.Context, userID int64) (int64, error) {
+	query := `SELECT COALESCE(MAX(id), 0) FROM notifications WHERE user_id = $1`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var id int64
+	err := s.db.QueryRowContext(ctx, query, userID).Scan(&id)
+	return id, err
+}
+
+// MarkRead marks the group of notifications of userID whose latest
+// notification is id as read, grouped as by notificationGroupKey.
+// ErrNotFound is returned when userID has no notification id.
+func (s *NotificationStorage) MarkRead(ctx context.Context, userID, id int64) error {
+	query := `
+		WITH target AS (
+			SELECT id, kind, post_id, created_at FROM notifications WHERE id = $2 AND user_id = $1
+		), marked AS (
+			UPDATE notifications n SET read_at = NOW()
+			FROM target t
+			WHERE n.user_id = $1 AND n.read_at IS NULL AND n.id <= t.id
+				AND (n.id = t.id OR (t.kind IN ('like', 'follow') AND n.kind = t.kind AND n.post_id IS NOT DISTINCT FROM t.post_id
+					AND (n.created_at AT TIME ZONE 'UTC')::DATE = (t.created_at AT TIME ZONE 'UTC')::DATE))
+			RETURNING n.id
+		)
+		SELECT EXISTS (SELECT 1 FROM target)
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var found bool
+	if err := s.db.QueryRowContext(ctx, query, userID, id).Scan(&found); err != nil {
+		return err
+	}
+	if !found {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// MarkAllRead marks every notification of userID as read.
+func (s *NotificationStorage) MarkAllRead(ctx context.Context, userID int64) error {
+	query := `UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, userID)
+	return err
+}
+
+// GetActors returns the public profiles of the users with the given ids,
+// keyed by id. Unknown ids are skipped.
+func (s *NotificationStorage) GetActors(ctx context.Context, ids []int64) (map[int64]*models.PublicUser, error) {
+	actors := make(map[int64]*models.PublicUser, len(ids))
+	if len(ids) == 0 {
+		return actors, nil
+	}
+
+	query := `SELECT id, username, display_name, avatar_attachment_id, created_at FROM users WHERE id = ANY($1)`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u models.PublicUser
+		if err := rows.Scan(&u.ID, &u.Username, &u.DisplayName, &u.AvatarID, &u.CreatedAt); err != nil {
+			return nil, err
+		}
+		actors[u.ID] = &u
+	}
+	return actors, rows.Err()
+}
+
+func scanNotifications(rows *sql.Rows) ([]*models.Notification, error) {
+	defer rows.Close()
+
+	var notifications []*models.Notification
+	for rows.Next() {
+		var n models.Notification
+		err := rows.Scan(&n.ID, &n.UserID, &n.ActorID, &n.Kind, &n.PostID, &n.CommentID, &n.CreatedAt, &n.ReadAt)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, &n)
+	}
+	return notifications, rows.Err()
+}
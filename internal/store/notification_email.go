@@ -0,0 +1,222 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/lib/pq"
+)
+
+// quietHoursClause holds while the user of the notification_settings row s
+// is in their quiet hours, in their timezone.
+const quietHoursClause = `(s.quiet_hours_start IS NOT NULL AND CASE
+		WHEN s.quiet_hours_start <= s.quiet_hours_end
+			THEN (NOW() AT TIME ZONE s.timezone)::TIME >= s.quiet_hours_start AND (NOW() AT TIME ZONE s.timezone)::TIME < s.quiet_hours_end
+		ELSE (NOW() AT TIME ZONE s.timezone)::TIME >= s.quiet_hours_start OR (NOW() AT TIME ZONE s.timezone)::TIME < s.quiet_hours_end
+	END)`
+
+// pendingEmailClause holds for the notifications n of the user s that are
+// to be emailed with delivery $1 and haven't been read or emailed yet.
+// Notifications from before the user chose the delivery are left out, as are
+// those waiting for a retry after emailing them failed.
+const pendingEmailClause = `n.user_id = s.user_id AND n.read_at IS NULL AND n.emailed_at IS NULL
+	AND (n.email_next_attempt_at IS NULL OR n.email_next_attempt_at <= NOW())
+	AND EXISTS (
+		SELECT 1 FROM notification_preferences p
+		WHERE p.user_id = n.user_id AND p.kind = n.kind AND p.delivery = $1 AND n.created_at >= p.updated_at
+	)`
+
+// digestColumns are the columns recording when the last digest of each
+// delivery was sent.
+var digestColumns = map[models.NotificationDelivery]string{
+	models.NotificationDeliveryDaily:  "last_daily_digest_at",
+	models.NotificationDeliveryWeekly: "last_weekly_digest_at",
+}
+
+// GetPreferences returns the notification settings of userID and the
+// deliveries it chose. Kinds without a delivery are missing from Delivery.
+func (s *NotificationStorage) GetPreferences(ctx context.Context, userID int64) (*models.NotificationPreferences, error) {
+	prefs := &models.NotificationPreferences{
+		Delivery: make(map[models.NotificationKind]models.NotificationDelivery),
+		Timezone: "UTC",
+	}
+
+	query := `
+		SELECT timezone, to_char(quiet_hours_start, 'HH24:MI'), to_char(quiet_hours_end, 'HH24:MI')
+		FROM notification_settings WHERE user_id = $1
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.QueryRowContext(ctx, query, userID).Scan(&prefs.Timezone, &prefs.QuietHoursStart, &prefs.QuietHoursEnd)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	query = `SELECT kind, delivery FROM notification_preferences WHERE user_id = $1`
+	rows, err := s.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var kind models.NotificationKind
+		var delivery models.NotificationDelivery
+		if err := rows.Scan(&kind, &delivery); err != nil {
+			return nil, err
+		}
+		prefs.Delivery[kind] = delivery
+	}
+	return prefs, rows.Err()
+}
+
+// SavePreferences replaces the notification settings of userID and sets the
+// deliveries in prefs. The first digests of a user are sent a day and a
+// week after its settings are first saved.
+func (s *NotificationStorage) SavePreferences(ctx context.Context, userID int64, prefs *models.NotificationPreferences) error {
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+		defer cancel()
+
+		query := `
+			INSERT INTO notification_settings (user_id, timezone, quiet_hours_start, quiet_hours_end)
+			VALUES ($1, $2, $3::TIME, $4::TIME)
+			ON CONFLICT (user_id) DO UPDATE SET
+				timezone = EXCLUDED.timezone,
+				quiet_hours_start = EXCLUDED.quiet_hours_start,
+				quiet_hours_end = EXCLUDED.quiet_hours_end
+		`
+		if _, err := tx.ExecContext(ctx, query, userID, prefs.Timezone, prefs.QuietHoursStart, prefs.QuietHoursEnd); err != nil {
+			return err
+		}
+
+		if len(prefs.Delivery) == 0 {
+			return nil
+		}
+		kinds := make([]string, 0, len(prefs.Delivery))
+		deliveries := make([]string, 0, len(prefs.Delivery))
+		for kind, delivery := range prefs.Delivery {
+			kinds = append(kinds, string(kind))
+			deliveries = append(deliveries, string(delivery))
+		}
+
+		// updated_at only moves when the delivery changes.
+		query = `
+			INSERT INTO notification_preferences (user_id, kind, delivery)
+			SELECT $1, k.kind, k.delivery
+			FROM unnest($2::TEXT[], $3::TEXT[]) AS k(kind, delivery)
+			ON CONFLICT (user_id, kind) DO UPDATE SET delivery = EXCLUDED.delivery, updated_at = NOW()
+			WHERE notification_preferences.delivery <> EXCLUDED.delivery
+		`
+		_, err := tx.ExecContext(ctx, query, userID, pq.Array(kinds), pq.Array(deliveries))
+		return err
+	})
+}
+
+// ClaimEmail marks the notifications of one user that are due to be emailed
+// right away as emailed and returns them. Users in their quiet hours are
+// skipped until the quiet hours end. ErrNotFound is returned when there is
+// nothing to email.
+func (s *NotificationStorage) ClaimEmail(ctx context.Context) (*models.NotificationEmail, error) {
+	return s.claimEmail(ctx, models.NotificationDeliveryEmail, "", time.Time{})
+}
+
+// ClaimDigest marks the notifications of the digest of one user due for a
+// digest with delivery, daily or weekly, as emailed and returns them. A
+// digest is due once period has passed since the last one, outside of the
+// user's quiet hours. ErrNotFound is returned when no digest is due.
+func (s *NotificationStorage) ClaimDigest(ctx context.Context, delivery models.NotificationDelivery, period time.Duration) (*models.NotificationEmail, error) {
+	column, ok := digestColumns[delivery]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return s.claimEmail(ctx, delivery, column, time.Now().Add(-period))
+}
+
+// claimEmail claims the pending notifications of delivery of one user. For
+// digests, digestColumn names the column recording the user's last digest,
+// which must be no later than lastBefore, and is moved to now. The
+// notifications recipients may no longer be notified of, for instance after
+// a block, are claimed but not returned.
+func (s *NotificationStorage) claimEmail(ctx context.Context, delivery models.NotificationDelivery, digestColumn string, lastBefore time.Time) (*models.NotificationEmail, error) {
+	email := &models.NotificationEmail{Delivery: delivery}
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+		defer cancel()
+
+		where := "NOT " + quietHoursClause
+		order := "s.user_id"
+		args := []any{delivery}
+		if digestColumn != "" {
+			where += " AND s." + digestColumn + " <= $2"
+			order = "s." + digestColumn
+			args = append(args, lastBefore)
+		}
+		query := `
+			SELECT s.user_id FROM notification_settings s
+			WHERE ` + where + `
+				AND EXISTS (SELECT 1 FROM notifications n WHERE ` + pendingEmailClause + `)
+			ORDER BY ` + order + `
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		`
+		if err := tx.QueryRowContext(ctx, query, args...).Scan(&email.UserID); err != nil {
+			if err == sql.ErrNoRows {
+				return ErrNotFound
+			}
+			return err
+		}
+
+		if digestColumn != "" {
+			query = `UPDATE notification_settings SET ` + digestColumn + ` = NOW() WHERE user_id = $1`
+			if _, err := tx.ExecContext(ctx, query, email.UserID); err != nil {
+				return err
+			}
+		}
+
+		query = `
+			UPDATE notifications n SET emailed_at = NOW(), email_attempts = n.email_attempts + 1
+			FROM notification_settings s
+			WHERE s.user_id = $2 AND ` + pendingEmailClause + `
+			RETURNING n.id, n.user_id, n.actor_id, n.kind, n.post_id, n.comment_id, n.created_at, n.read_at,
+				n.email_attempts, ` + allowedNotificationClause("n.user_id", "n.actor_id", "n.kind", "n.post_id")
+		rows, err := tx.QueryContext(ctx, query, delivery, email.UserID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var n models.Notification
+			var attempts int
+			var allowed bool
+			err := rows.Scan(&n.ID, &n.UserID, &n.ActorID, &n.Kind, &n.PostID, &n.CommentID, &n.CreatedAt, &n.ReadAt, &attempts, &allowed)
+			if err != nil {
+				return err
+			}
+			if allowed {
+				email.Attempts = max(email.Attempts, attempts)
+				email.Notifications = append(email.Notifications, &n)
+			}
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return email, nil
+}
+
+// ReleaseEmail marks the notifications with the given ids as not emailed,
+// after emailing them failed, so they are emailed again from retryAt on.
+func (s *NotificationStorage) ReleaseEmail(ctx context.Context, ids []int64, retryAt time.Time) error {
+	query := `UPDATE notifications SET emailed_at = NULL, email_next_attempt_at = $2 WHERE id = ANY($1)`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, pq.Array(ids), retryAt)
+	return err
+}
@@ -0,0 +1,156 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+)
+
+type OutboxStorage struct {
+	db *sql.DB
+}
+
+// Add queues messages outside of any other change.
+func (s *OutboxStorage) Add(ctx context.Context, messages ...*models.OutboxMessage) error {
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		return addToOutbox(ctx, tx, messages)
+	})
+}
+
+// addToOutbox queues messages in tx, so they are only dispatched when the
+// change they are about is committed.
+func addToOutbox(ctx context.Context, tx *sql.Tx, messages []*models.OutboxMessage) error {
+	query := `INSERT INTO outbox (kind, payload) VALUES ($1, $2) RETURNING id`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	for _, m := range messages {
+		if err := tx.QueryRowContext(ctx, query, m.Kind, string(m.Payload)).Scan(&m.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Claim marks the next due message as being dispatched and returns it, or
+// ErrNotFound when none is due. Messages dispatched for longer than
+// staleAfter are assumed abandoned and claimed again.
+func (s *OutboxStorage) Claim(ctx context.Context, staleAfter time.Duration) (*models.OutboxMessage, error) {
+	query := `
+		UPDATE outbox SET status = 'dispatching', started_at = NOW(), attempts = attempts + 1
+		WHERE id = (
+			SELECT id FROM outbox
+			WHERE (status = 'pending' AND next_attempt_at <= NOW())
+			   OR (status = 'dispatching' AND started_at < NOW() - make_interval(secs => $1))
+			ORDER BY next_attempt_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING id, kind, payload, attempts
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var m models.OutboxMessage
+	var payload string
+	err := s.db.QueryRowContext(ctx, query, staleAfter.Seconds()).Scan(&m.ID, &m.Kind, &payload, &m.Attempts)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	m.Payload = []byte(payload)
+	return &m, nil
+}
+
+// Complete removes a dispatched message.
+func (s *OutboxStorage) Complete(ctx context.Context, id int64) error {
+	query := `DELETE FROM outbox WHERE id = $1`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// Fail records why dispatching a message failed. It is retried at retryAt,
+// or kept as failed when it is nil.
+func (s *OutboxStorage) Fail(ctx context.Context, id int64, reason string, retryAt *time.Time) error {
+	query := `
+		UPDATE outbox
+		SET status = CASE WHEN $3::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+			next_attempt_at = COALESCE($3, next_attempt_at),
+			error = $2
+		WHERE id = $1
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, query, id, reason, retryAt)
+	return err
+}
+
+const outboxMessageColumns = `id, kind, payload, attempts, error, created_at`
+
+// ListFailed returns up to limit messages that were given up on, created
+// before the cursor (before, beforeID), latest first.
+func (s *OutboxStorage) ListFailed(ctx context.Context, before *time.Time, beforeID int64, limit int) ([]*models.OutboxMessage, error) {
+	query := `
+		SELECT ` + outboxMessageColumns + `
+		FROM outbox
+		WHERE status = 'failed' AND ($1::timestamptz IS NULL OR (created_at, id) < ($1, $2))
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, before, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []*models.OutboxMessage{}
+	for rows.Next() {
+		m, err := scanOutboxMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+// Retry queues a message that was given up on again, with its attempts
+// reset, and returns it. ErrNotFound is returned when no message id failed.
+func (s *OutboxStorage) Retry(ctx context.Context, id int64) (*models.OutboxMessage, error) {
+	query := `
+		UPDATE outbox SET status = 'pending', attempts = 0, next_attempt_at = NOW()
+		WHERE id = $1 AND status = 'failed'
+		RETURNING ` + outboxMessageColumns
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	m, err := scanOutboxMessage(s.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+func scanOutboxMessage(row interface{ Scan(...any) error }) (*models.OutboxMessage, error) {
+	var m models.OutboxMessage
+	var payload string
+	if err := row.Scan(&m.ID, &m.Kind, &payload, &m.Attempts, &m.Error, &m.CreatedAt); err != nil {
+		return nil, err
+	}
+	m.Payload = []byte(payload)
+	return &m, nil
+}
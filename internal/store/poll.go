@@ -0,0 +1,212 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"github.com/lib/pq"
+)
+
+type PollStorage struct {
+	db *sql.DB
+}
+
+// createPoll stores the poll and its options in tx, filling in their ids.
+func createPoll(ctx context.Context, tx *sql.Tx, poll *models.Poll) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	err := tx.QueryRowContext(ctx, `
+		INSERT INTO polls (post_id, multiple_choice, results_visibility, closes_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, poll.PostID, poll.MultipleChoice, poll.ResultsVisibility, poll.ClosesAt).Scan(&poll.ID, &poll.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	for _, o := range poll.Options {
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO poll_options (poll_id, position, text)
+			VALUES ($1, $2, $3)
+			RETURNING id
+		`, poll.ID, o.Position, o.Text).Scan(&o.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetByPostID returns the poll of a post with its options but without the
+// voter's choices.
+func (s *PollStorage) GetByPostID(ctx context.Context, postID int64) (*models.Poll, error) {
+	polls, err := s.GetForPosts(ctx, []int64{postID}, 0)
+	if err != nil {
+		return nil, err
+	}
+	poll, ok := polls[postID]
+	if !ok {
+		return nil, apperrors.ErrPollNotFound
+	}
+	return poll, nil
+}
+
+// GetForPosts returns the polls of the given posts keyed by post id, with
+// their tallies and the options voterID voted for. Tallies are always
+// filled in; hiding them is up to the caller.
+func (s *PollStorage) GetForPosts(ctx context.Context, postIDs []int64, voterID int64) (map[int64]*models.Poll, error) {
+	polls := make(map[int64]*models.Poll)
+	if len(postIDs) == 0 {
+		return polls, nil
+	}
+
+	query := `
+		SELECT p.id, p.post_id, p.multiple_choice, p.results_visibility, p.closes_at, p.created_at,
+			(SELECT COUNT(DISTINCT v.user_id) FROM poll_votes v WHERE v.poll_id = p.id),
+			o.id, o.position, o.text, o.vote_count
+		FROM polls p
+		JOIN poll_options o ON o.poll_id = p.id
+		WHERE p.post_id = ANY($1)
+		ORDER BY p.id, o.position
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, pq.Array(postIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byID := make(map[int64]*models.Poll)
+	for rows.Next() {
+		var p models.Poll
+		var o models.PollOption
+		var closesAt sql.NullTime
+		var voters, votes int64
+
+		err := rows.Scan(&p.ID, &p.PostID, &p.MultipleChoice, &p.ResultsVisibility, &closesAt, &p.CreatedAt,
+			&voters, &o.ID, &o.Position, &o.Text, &votes)
+		if err != nil {
+			return nil, err
+		}
+
+		poll, ok := byID[p.ID]
+		if !ok {
+			poll = &p
+			if closesAt.Valid {
+				poll.ClosesAt = &closesAt.Time
+			}
+			poll.VoterCount = &voters
+			poll.MyVotes = []int64{}
+			byID[p.ID] = poll
+			polls[p.PostID] = poll
+		}
+		o.VoteCount = &votes
+		poll.Options = append(poll.Options, &o)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if voterID == 0 || len(byID) == 0 {
+		return polls, nil
+	}
+
+	pollIDs := make([]int64, 0, len(byID))
+	for id := range byID {
+		pollIDs = append(pollIDs, id)
+	}
+
+	voteRows, err := s.db.QueryContext(ctx, `
+		SELECT poll_id, option_id FROM poll_votes
+		WHERE poll_id = ANY($1) AND user_id = $2
+		ORDER BY option_id
+	`, pq.Array(pollIDs), voterID)
+	if err != nil {
+		return nil, err
+	}
+	defer voteRows.Close()
+
+	for voteRows.Next() {
+		var pollID, optionID int64
+		if err := voteRows.Scan(&pollID, &optionID); err != nil {
+			return nil, err
+		}
+		byID[pollID].MyVotes = append(byID[pollID].MyVotes, optionID)
+	}
+
+	return polls, voteRows.Err()
+}
+
+// Vote replaces the votes of the user on the poll with optionIDs. The poll
+// row is locked so a vote can't race with the poll closing.
+func (s *PollStorage) Vote(ctx context.Context, pollID, userID int64, optionIDs []int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		if err := lockOpenPoll(ctx, tx, pollID); err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM poll_votes WHERE poll_id = $1 AND user_id = $2`, pollID, userID); err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO poll_votes (poll_id, option_id, user_id)
+			SELECT $1, o.id, $3 FROM poll_options o
+			WHERE o.poll_id = $1 AND o.id = ANY($2)
+		`, pollID, pq.Array(optionIDs), userID)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows != int64(len(optionIDs)) {
+			return apperrors.ErrPollOptionNotFound
+		}
+		return nil
+	})
+}
+
+// RetractVote removes the votes of the user on an open poll.
+func (s *PollStorage) RetractVote(ctx context.Context, pollID, userID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		if err := lockOpenPoll(ctx, tx, pollID); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, `DELETE FROM poll_votes WHERE poll_id = $1 AND user_id = $2`, pollID, userID)
+		return err
+	})
+}
+
+// lockOpenPoll locks an open poll for the rest of tx. The lock is exclusive
+// so concurrent votes of a user can't both replace their previous votes and
+// leave two votes on a single-choice poll.
+func lockOpenPoll(ctx context.Context, tx *sql.Tx, pollID int64) error {
+	var closesAt sql.NullTime
+	err := tx.QueryRowContext(ctx, `SELECT closes_at FROM polls WHERE id = $1 FOR UPDATE`, pollID).Scan(&closesAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return apperrors.ErrPollNotFound
+		}
+		return err
+	}
+	if closesAt.Valid && !closesAt.Time.After(time.Now()) {
+		return apperrors.ErrPollClosed
+	}
+	return nil
+}
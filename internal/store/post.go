@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/LikhithMar14/gopher-chat/internal/models"
@@ -15,24 +16,72 @@ type PostStorage struct {
 	db *sql.DB
 }
 
-func (s *PostStorage) Create(ctx context.Context, post *models.Post) error {
-	query := `INSERT INTO posts (content, title, user_id ,tags)
-	 VALUES ($1, $2, $3, $4) RETURNING id, created_at, updated_at, version`
+// visiblePostClause returns a SQL predicate that limits the posts aliased as
+// alias to the ones the viewer bound at placeholder $viewerArg is allowed to
+// see. Public posts of private accounts are only visible to followers, and
+// posts are hidden in both directions between users who blocked each other. A
+// viewer id of 0 is anonymous and only matches public posts of public
+// accounts.
+func visiblePostClause(alias string, viewerArg int) string {
+	return visiblePostClauseFor(alias, fmt.Sprintf("$%d", viewerArg))
+}
+
+// visiblePostClauseFor is visiblePostClause for a viewer given by the SQL
+// expression viewer, such as a column of the recipients of a notification.
+func visiblePostClauseFor(alias, viewer string) string {
+	return fmt.Sprintf(`((%[1]s.user_id = %[2]s
+		OR (%[1]s.visibility = 'public' AND NOT EXISTS (
+			SELECT 1 FROM users vu WHERE vu.id = %[1]s.user_id AND vu.is_private
+		))
+		OR (%[1]s.visibility IN ('public', 'followers') AND EXISTS (
+			SELECT 1 FROM followers vf
+			WHERE vf.user_id = %[1]s.user_id AND vf.follower_id = %[2]s
+		)))
+		AND NOT EXISTS (
+			SELECT 1 FROM user_blocks vb
+			WHERE (vb.blocker_id = %[1]s.user_id AND vb.blocked_id = %[2]s)
+				OR (vb.blocker_id = %[2]s AND vb.blocked_id = %[1]s.user_id)
+		))`, alias, viewer)
+}
+
+// Create stores a post along with its mentions, the user's unlinked uploads
+// attachmentIDs, the poll unless it is nil, and the outbox messages returned
+// by messages for the created post. Either all of it is stored or none.
+func (s *PostStorage) Create(ctx context.Context, post *models.Post, attachmentIDs []int64, poll *models.Poll, messages func(*models.Post) ([]*models.OutboxMessage, error)) error {
+	query := `INSERT INTO posts (content, title, user_id ,tags, visibility)
+	 VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at, version`
 
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
 	defer cancel()
-	if err := s.db.QueryRowContext(ctx, query, post.Content, post.Title, post.UserID, post.Tags).Scan(&post.ID, &post.CreatedAt, &post.UpdatedAt, &post.Version); err != nil {
-		return err
-	}
-
-	return nil
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, query, post.Content, post.Title, post.UserID, pq.Array(post.Tags), post.Visibility).Scan(&post.ID, &post.CreatedAt, &post.UpdatedAt, &post.Version); err != nil {
+			return err
+		}
+		if err := replacePostMentions(ctx, tx, post); err != nil {
+			return err
+		}
+		if err := linkAttachments(ctx, tx, "post_id", post.UserID, post.ID, attachmentIDs); err != nil {
+			return err
+		}
+		if poll != nil {
+			poll.PostID = post.ID
+			if err := createPoll(ctx, tx, poll); err != nil {
+				return err
+			}
+		}
 
+		outbox, err := messages(post)
+		if err != nil {
+			return err
+		}
+		return addToOutbox(ctx, tx, outbox)
+	})
 }
 
 func (s *PostStorage) GetByID(ctx context.Context, id int64) (*models.Post, error) {
 	var post models.Post
 	query := `
-		SELECT id, user_id, title, content, tags, created_at, updated_at, version
+		SELECT id, user_id, title, content, tags, visibility, created_at, updated_at, version
 		FROM posts
 		WHERE id = $1
 
@@ -40,7 +89,7 @@ func (s *PostStorage) GetByID(ctx context.Context, id int64) (*models.Post, erro
 
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
 	defer cancel()
-	if err := s.db.QueryRowContext(ctx, query, id).Scan(&post.ID, &post.UserID, &post.Title, &post.Content, pq.Array(&post.Tags), &post.CreatedAt, &post.UpdatedAt, &post.Version); err != nil {
+	if err := s.db.QueryRowContext(ctx, query, id).Scan(&post.ID, &post.UserID, &post.Title, &post.Content, pq.Array(&post.Tags), &post.Visibility, &post.CreatedAt, &post.UpdatedAt, &post.Version); err != nil {
 		switch {
 		case err == sql.ErrNoRows:
 			return nil, ErrNotFound
@@ -52,7 +101,8 @@ func (s *PostStorage) GetByID(ctx context.Context, id int64) (*models.Post, erro
 	return &post, nil
 }
 
-func (s *PostStorage) Delete(ctx context.Context, id int64) error {
+// Delete deletes a post along with adding the outbox messages.
+func (s *PostStorage) Delete(ctx context.Context, id int64, messages []*models.OutboxMessage) error {
 	query := `
 		DELETE FROM posts
 		WHERE id = $1
@@ -60,30 +110,32 @@ func (s *PostStorage) Delete(ctx context.Context, id int64) error {
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
 	defer cancel()
 
-	res, err := s.db.ExecContext(ctx, query, id)
-	if err != nil {
-		return err
-	}
-	rows, err := res.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if rows == 0 {
-		return apperrors.ErrPostNotFound
-	}
-	return nil
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return apperrors.ErrPostNotFound
+		}
+		return addToOutbox(ctx, tx, messages)
+	})
 }
 
 func (s *PostStorage) Update(ctx context.Context, post *models.Post) error {
 	query := `
 	UPDATE posts
-	SET title=$1, content=$2, tags=$3, updated_at=$4, version=version+1
-	WHERE id=$5 AND version=$6
+	SET title=$1, content=$2, tags=$3, visibility=$4, updated_at=$5, version=version+1
+	WHERE id=$6 AND version=$7
 	RETURNING version
 	`
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
 	defer cancel()
-	err := s.db.QueryRowContext(ctx, query, post.Title, post.Content, pq.Array(post.Tags), time.Now(), post.ID, post.Version).Scan(&post.Version)
+	err := s.db.QueryRowContext(ctx, query, post.Title, post.Content, pq.Array(post.Tags), post.Visibility, time.Now(), post.ID, post.Version).Scan(&post.Version)
 
 	if err != nil {
 		switch {
@@ -107,8 +159,10 @@ func (s *PostStorage) Update(ctx context.Context, post *models.Post) error {
 	return nil
 }
 
-// UpdateWithOptimisticLocking fetches the latest version and applies updates
-func (s *PostStorage) UpdateWithOptimisticLocking(ctx context.Context, id int64, updateFn func(*models.Post) error) (*models.Post, error) {
+// UpdateWithOptimisticLocking fetches the latest version and applies updates.
+// The mentions of the post are replaced with those updateFn leaves on it, and
+// the outbox messages returned by messages for the updated post are added.
+func (s *PostStorage) UpdateWithOptimisticLocking(ctx context.Context, id int64, updateFn func(*models.Post) error, messages func(*models.Post) ([]*models.OutboxMessage, error)) (*models.Post, error) {
 	// Start a transaction for consistency
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -119,13 +173,13 @@ func (s *PostStorage) UpdateWithOptimisticLocking(ctx context.Context, id int64,
 	// Fetch the latest version with FOR UPDATE to prevent concurrent modifications
 	var post models.Post
 	query := `
-		SELECT id, user_id, title, content, tags, created_at, updated_at, version
+		SELECT id, user_id, title, content, tags, visibility, created_at, updated_at, version
 		FROM posts
 		WHERE id = $1
 		FOR UPDATE
 	`
 
-	if err := tx.QueryRowContext(ctx, query, id).Scan(&post.ID, &post.UserID, &post.Title, &post.Content, pq.Array(&post.Tags), &post.CreatedAt, &post.UpdatedAt, &post.Version); err != nil {
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&post.ID, &post.UserID, &post.Title, &post.Content, pq.Array(&post.Tags), &post.Visibility, &post.CreatedAt, &post.UpdatedAt, &post.Version); err != nil {
 		switch {
 		case err == sql.ErrNoRows:
 			return nil, apperrors.ErrPostNotFound
@@ -142,12 +196,12 @@ func (s *PostStorage) UpdateWithOptimisticLocking(ctx context.Context, id int64,
 	// Update with the fresh version
 	updateQuery := `
 		UPDATE posts
-		SET title=$1, content=$2, tags=$3, updated_at=$4, version=version+1
-		WHERE id=$5 AND version=$6
+		SET title=$1, content=$2, tags=$3, visibility=$4, updated_at=$5, version=version+1
+		WHERE id=$6 AND version=$7
 		RETURNING version, updated_at
 	`
 
-	if err := tx.QueryRowContext(ctx, updateQuery, post.Title, post.Content, pq.Array(post.Tags), time.Now(), post.ID, post.Version).Scan(&post.Version, &post.UpdatedAt); err != nil {
+	if err := tx.QueryRowContext(ctx, updateQuery, post.Title, post.Content, pq.Array(post.Tags), post.Visibility, time.Now(), post.ID, post.Version).Scan(&post.Version, &post.UpdatedAt); err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
 			return nil, apperrors.ErrVersionConflict
@@ -156,6 +210,18 @@ func (s *PostStorage) UpdateWithOptimisticLocking(ctx context.Context, id int64,
 		}
 	}
 
+	if err := replacePostMentions(ctx, tx, &post); err != nil {
+		return nil, err
+	}
+
+	outbox, err := messages(&post)
+	if err != nil {
+		return nil, err
+	}
+	if err := addToOutbox(ctx, tx, outbox); err != nil {
+		return nil, err
+	}
+
 	// Commit the transaction
 	if err := tx.Commit(); err != nil {
 		return nil, err
@@ -164,42 +230,169 @@ func (s *PostStorage) UpdateWithOptimisticLocking(ctx context.Context, id int64,
 	return &post, nil
 }
 
+// CountByUser returns the number of posts of the author that the viewer is
+// allowed to see.
+func (s *PostStorage) CountByUser(ctx context.Context, authorID, viewerID int64) (int64, error) {
+	query := `SELECT COUNT(*) FROM posts p WHERE p.user_id = $1 AND ` + visiblePostClause("p", 2)
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var count int64
+	if err := s.db.QueryRowContext(ctx, query, authorID, viewerID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// feedRangeClause restricts the feed entries e to those strictly after the
+// position bound at $2, $3 and strictly before the one bound at $4, $5, when
+// they are set.
+const feedRangeClause = `
+	($2::timestamptz IS NULL OR (e.activity_at, e.post_id) > ($2, $3))
+	AND ($4::timestamptz IS NULL OR (e.activity_at, e.post_id) < ($4, $5))
+`
+
+// feedRangeArgs returns the viewer and the range arguments of feedRangeClause.
+func feedRangeArgs(userID int64, since, max *models.FeedPosition) []any {
+	args := []any{userID, nil, int64(0), nil, int64(0)}
+	if since != nil {
+		args[1], args[2] = since.ActivityAt, since.PostID
+	}
+	if max != nil {
+		args[3], args[4] = max.ActivityAt, max.PostID
+	}
+	return args
+}
+
+// feedCountQuery counts the home feed entries of the viewer in the range of
+// feedRangeClause.
+func feedCountQuery() string {
+	return timelineEntriesCTE + `
+		SELECT COUNT(*)
+		FROM feed_entries e
+		INNER JOIN posts p ON p.id = e.post_id
+		WHERE ` + visiblePostClause("p", 1) + ` AND ` + feedRangeClause
+}
 
-func (s *PostStorage) GetFeed(ctx context.Context, userID int64, page, pageSize int) ([]*models.FeedItem, int64, error) {
+// GetFeed returns a page of the home feed of the user, newest first. When
+// since or max are set only the entries after since and before max are
+// included.
+func (s *PostStorage) GetFeed(ctx context.Context, userID int64, since, max *models.FeedPosition, page, pageSize int) ([]*models.FeedItem, int64, error) {
 	offset := (page - 1) * pageSize
 
-	// Query to get posts from followed users with author information
-	query := `
-		SELECT 
-			p.id, p.user_id, p.title, p.content, p.tags, p.created_at, p.updated_at, p.version,
-			u.id, u.username, u.email, u.created_at, u.updated_at
-		FROM posts p
-		INNER JOIN users u ON p.user_id = u.id
-		INNER JOIN followers f ON p.user_id = f.user_id
-		WHERE f.follower_id = $1
-		ORDER BY p.created_at DESC
-		LIMIT $2 OFFSET $3
+	// Query to get the home timeline entries with author and reposter
+	// information. Visibility is checked against the original post,
+	// so restricting or deleting it also removes its reposts.
+	query := timelineEntriesCTE + `
+		SELECT ` + feedItemColumns + `
+		FROM feed_entries e
+		` + feedItemJoins + `
+		WHERE ` + visiblePostClause("p", 1) + ` AND ` + feedRangeClause + `
+		ORDER BY e.activity_at DESC, e.post_id DESC
+		LIMIT $6 OFFSET $7
+	`
+
+	countArgs := feedRangeArgs(userID, since, max)
+	args := append(feedRangeArgs(userID, since, max), pageSize, offset)
+	return s.queryFeed(ctx, feedCountQuery(), countArgs, query, args)
+}
+
+// CountFeedSince returns the number of home feed entries of the user after
+// since.
+func (s *PostStorage) CountFeedSince(ctx context.Context, userID int64, since *models.FeedPosition) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var count int64
+	if err := s.db.QueryRowContext(ctx, feedCountQuery(), feedRangeArgs(userID, since, nil)...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// rankedCandidatesCTE extends timelineEntriesCTE with the candidates of the
+// ranked feed: the entries with activity after $2 and up to $3.
+const rankedCandidatesCTE = timelineEntriesCTE + `, candidates AS (
+		SELECT post_id, repost_id, activity_at FROM feed_entries WHERE activity_at > $2 AND activity_at <= $3
+	)
+`
+
+// GetRankedFeed returns up to limit home timeline entries of the
+// ranking.Window before asOf ordered by score, starting after the entry at
+// after unless it is nil. Entries are scored as of asOf, counting only the
+// likes, comments and interactions made by then, so the pages of one asOf
+// rank alike. Author and tag affinity are derived from the posts the viewer
+// liked or commented on in the 30 days before asOf. The scores are set on
+// the returned items.
+func (s *PostStorage) GetRankedFeed(ctx context.Context, userID int64, ranking models.FeedRanking, asOf time.Time, after *models.RankedFeedPosition, limit int) ([]*models.FeedItem, int64, error) {
+	since := asOf.Add(-ranking.Window)
+
+	query := rankedCandidatesCTE + `, interactions AS (
+			SELECT pl.post_id FROM post_likes pl
+			WHERE pl.user_id = $1 AND pl.created_at > $3 - INTERVAL '30 days' AND pl.created_at <= $3
+			UNION ALL
+			SELECT c.post_id FROM comments c
+			WHERE c.user_id = $1 AND c.created_at > $3 - INTERVAL '30 days' AND c.created_at <= $3
+		), author_affinity AS (
+			SELECT p.user_id, COUNT(*) AS interactions
+			FROM interactions i
+			INNER JOIN posts p ON p.id = i.post_id
+			GROUP BY p.user_id
+		), tag_affinity AS (
+			SELECT t.tag, COUNT(*) AS interactions
+			FROM interactions i
+			INNER JOIN posts p ON p.id = i.post_id
+			CROSS JOIN LATERAL unnest(p.tags) AS t(tag)
+			GROUP BY t.tag
+		), scored AS (
+			SELECT e.post_id, e.repost_id, e.activity_at,
+				$4::float8 * power(0.5, EXTRACT(EPOCH FROM $3::timestamptz - e.activity_at) / $5::float8)
+				+ $6::float8 * ln(1
+					+ (SELECT COUNT(*) FROM post_likes pl WHERE pl.post_id = p.id AND pl.created_at <= $3)
+					+ (SELECT COUNT(*) FROM comments c WHERE c.post_id = p.id AND c.created_at <= $3))
+				+ $7::float8 * ln(1 + COALESCE(aa.interactions, 0))
+				+ $8::float8 * ln(1 + COALESCE((SELECT SUM(ta.interactions) FROM tag_affinity ta WHERE ta.tag = ANY(p.tags)), 0))
+				AS score
+			FROM candidates e
+			INNER JOIN posts p ON p.id = e.post_id
+			LEFT JOIN author_affinity aa ON aa.user_id = p.user_id
+		)
+		SELECT ` + feedItemColumns + `, e.score
+		FROM scored e
+		` + feedItemJoins + `
+		WHERE ` + visiblePostClause("p", 1) + `
+			AND ($9::float8 IS NULL OR (e.score, e.activity_at, e.post_id) < ($9, $10, $11))
+		ORDER BY e.score DESC, e.activity_at DESC, e.post_id DESC
+		LIMIT $12
 	`
 
-	// Count query for pagination
-	countQuery := `
+	countQuery := rankedCandidatesCTE + `
 		SELECT COUNT(*)
-		FROM posts p
-		INNER JOIN followers f ON p.user_id = f.user_id
-		WHERE f.follower_id = $1
+		FROM candidates e
+		INNER JOIN posts p ON p.id = e.post_id
+		WHERE ` + visiblePostClause("p", 1) + `
 	`
 
+	args := []any{
+		userID, since, asOf,
+		ranking.RecencyWeight, ranking.HalfLife.Seconds(),
+		ranking.EngagementWeight, ranking.AuthorWeight, ranking.TagWeight,
+		nil, nil, int64(0),
+		limit,
+	}
+	if after != nil {
+		args[8], args[9], args[10] = after.Score, after.ActivityAt, after.PostID
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
 	defer cancel()
 
-	// Get total count for pagination
 	var totalCount int64
-	if err := s.db.QueryRowContext(ctx, countQuery, userID).Scan(&totalCount); err != nil {
+	if err := s.db.QueryRowContext(ctx, countQuery, userID, since, asOf).Scan(&totalCount); err != nil {
 		return nil, 0, err
 	}
 
-	// Get feed items
-	rows, err := s.db.QueryContext(ctx, query, userID, pageSize, offset)
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -207,27 +400,172 @@ func (s *PostStorage) GetFeed(ctx context.Context, userID int64, page, pageSize
 
 	var feedItems []*models.FeedItem
 	for rows.Next() {
-		var post models.Post
-		var author models.User
-
-		err := rows.Scan(
-			&post.ID, &post.UserID, &post.Title, &post.Content, pq.Array(&post.Tags),
-			&post.CreatedAt, &post.UpdatedAt, &post.Version,
-			&author.ID, &author.Username, &author.Email, &author.CreatedAt, &author.UpdatedAt,
-		)
-		if err != nil {
+		item, repost := newFeedItemRow()
+		if err := rows.Scan(append(feedItemDest(item, repost), &item.Score)...); err != nil {
 			return nil, 0, err
 		}
+		repost.attach(item)
+		feedItems = append(feedItems, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return feedItems, totalCount, nil
+}
 
-		feedItems = append(feedItems, &models.FeedItem{
-			Post:   &post,
-			Author: &author,
-		})
+// GetPublic returns up to limit of the most recent posts that anyone may see,
+// newest first: public posts of public accounts. authorID and tag restrict
+// the posts to those of one author and carrying a tag, compared
+// case-insensitively against the lower cased stored tags, unless they are
+// zero.
+func (s *PostStorage) GetPublic(ctx context.Context, authorID int64, tag string, limit int) ([]*models.FeedItem, error) {
+	query := `
+		SELECT ` + feedItemColumns + `
+		FROM (
+			SELECT id AS post_id, NULL::BIGINT AS repost_id
+			FROM posts
+			WHERE visibility = 'public'
+				AND ($2::BIGINT = 0 OR user_id = $2)
+				AND ($3::TEXT = '' OR tags @> ARRAY[lower($3)])
+		) e
+		` + feedItemJoins + `
+		WHERE ` + visiblePostClause("p", 1) + `
+		ORDER BY p.created_at DESC, p.id DESC
+		LIMIT $4
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	// The posts are served to anonymous readers, bound as viewer 0.
+	rows, err := s.db.QueryContext(ctx, query, int64(0), authorID, tag, limit)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
+
+	return scanFeedItems(rows)
+}
+
+// feedItemColumns are the columns of a feed row read by queryFeed, selected
+// from the joins in feedItemJoins against the entries e.
+const feedItemColumns = `
+	p.id, p.user_id, p.title, p.content, p.tags, p.visibility, p.created_at, p.updated_at, p.version,
+	u.id, u.username, u.display_name, u.avatar_attachment_id, u.created_at,
+	p.like_count,
+	EXISTS (SELECT 1 FROM post_likes pl WHERE pl.post_id = p.id AND pl.user_id = $1),
+	EXISTS (SELECT 1 FROM post_bookmarks pb WHERE pb.post_id = p.id AND pb.user_id = $1),
+	r.id, r.quote, r.created_at,
+	ru.id, ru.username, ru.display_name, ru.avatar_attachment_id, ru.created_at
+`
+
+const feedItemJoins = `
+	INNER JOIN posts p ON p.id = e.post_id
+	INNER JOIN users u ON p.user_id = u.id
+	LEFT JOIN reposts r ON r.id = e.repost_id
+	LEFT JOIN users ru ON ru.id = r.user_id
+`
+
+// queryFeed runs a feed query selecting feedItemColumns along with the query
+// counting all of its rows.
+func (s *PostStorage) queryFeed(ctx context.Context, countQuery string, countArgs []any, query string, args []any) ([]*models.FeedItem, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
 
-	if err = rows.Err(); err != nil {
+	// Get total count for pagination
+	var totalCount int64
+	if err := s.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&totalCount); err != nil {
+		return nil, 0, err
+	}
+
+	// Get feed items
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	feedItems, err := scanFeedItems(rows)
+	if err != nil {
 		return nil, 0, err
 	}
 
 	return feedItems, totalCount, nil
 }
+
+// scanFeedItems reads rows selecting feedItemColumns.
+func scanFeedItems(rows *sql.Rows) ([]*models.FeedItem, error) {
+	var feedItems []*models.FeedItem
+	for rows.Next() {
+		item, repost := newFeedItemRow()
+		if err := rows.Scan(feedItemDest(item, repost)...); err != nil {
+			return nil, err
+		}
+		repost.attach(item)
+
+		feedItems = append(feedItems, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return feedItems, nil
+}
+
+// newFeedItemRow returns an item to scan a feed row into, along with the
+// columns of its repost.
+func newFeedItemRow() (*models.FeedItem, *repostColumns) {
+	return &models.FeedItem{Post: &models.Post{}, Author: &models.PublicUser{}}, &repostColumns{}
+}
+
+// feedItemDest returns the scan destinations of feedItemColumns.
+func feedItemDest(item *models.FeedItem, repost *repostColumns) []any {
+	post, author := item.Post, item.Author
+	return []any{
+		&post.ID, &post.UserID, &post.Title, &post.Content, pq.Array(&post.Tags), &post.Visibility,
+		&post.CreatedAt, &post.UpdatedAt, &post.Version,
+		&author.ID, &author.Username, &author.DisplayName, &author.AvatarID, &author.CreatedAt,
+		&item.LikeCount, &item.LikedByMe, &item.BookmarkedByMe,
+		&repost.id, &repost.quote, &repost.createdAt,
+		&repost.userID, &repost.username, &repost.displayName, &repost.avatarID, &repost.userCreatedAt,
+	}
+}
+
+// repostColumns holds the nullable repost and reposter columns of a feed row.
+type repostColumns struct {
+	id            sql.NullInt64
+	quote         sql.NullString
+	createdAt     sql.NullTime
+	userID        sql.NullInt64
+	username      sql.NullString
+	displayName   sql.NullString
+	avatarID      sql.NullInt64
+	userCreatedAt sql.NullTime
+}
+
+// attach sets the repost attribution on item when the row came from a repost.
+func (c repostColumns) attach(item *models.FeedItem) {
+	if !c.id.Valid {
+		return
+	}
+
+	item.Repost = &models.Repost{
+		ID:        c.id.Int64,
+		PostID:    item.Post.ID,
+		UserID:    c.userID.Int64,
+		CreatedAt: c.createdAt.Time,
+	}
+	if c.quote.Valid {
+		item.Repost.Quote = &c.quote.String
+	}
+	item.RepostedBy = &models.PublicUser{
+		ID:          c.userID.Int64,
+		Username:    c.username.String,
+		DisplayName: c.displayName.String,
+		CreatedAt:   c.userCreatedAt.Time,
+	}
+	if c.avatarID.Valid {
+		item.RepostedBy.AvatarID = &c.avatarID.Int64
+	}
+}
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"github.com/lib/pq"
+)
+
+type RepostStorage struct {
+	db *sql.DB
+}
+
+func (s *RepostStorage) Create(ctx context.Context, repost *models.Repost) error {
+	query := `INSERT INTO reposts (post_id, user_id, quote) VALUES ($1, $2, $3) RETURNING id, created_at`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.QueryRowContext(ctx, query, repost.PostID, repost.UserID, repost.Quote).Scan(&repost.ID, &repost.CreatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return apperrors.ErrAlreadyReposted
+		}
+		return err
+	}
+
+	return nil
+}
+
+// DeletePlain removes the user's plain (non-quote) repost of a post.
+func (s *RepostStorage) DeletePlain(ctx context.Context, userID, postID int64) error {
+	query := `DELETE FROM reposts WHERE user_id = $1 AND post_id = $2 AND quote IS NULL`
+	return s.deleteOne(ctx, query, userID, postID)
+}
+
+// Delete removes a repost or quote post owned by the user.
+func (s *RepostStorage) Delete(ctx context.Context, userID, repostID int64) error {
+	query := `DELETE FROM reposts WHERE user_id = $1 AND id = $2`
+	return s.deleteOne(ctx, query, userID, repostID)
+}
+
+func (s *RepostStorage) deleteOne(ctx context.Context, query string, args ...any) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return apperrors.ErrRepostNotFound
+	}
+	return nil
+}
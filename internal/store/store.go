@@ -17,56 +17,242 @@ var (
 const QueryTimeoutDuration = 3 * time.Second
 
 type Storage struct {
-	Post    PostRepository
-	User    UserRepository
-	Comment CommentRepository
-	Follow  FollowRepository
-	Auth    AuthRepository
+	Post          PostRepository
+	User          UserRepository
+	Comment       CommentRepository
+	Follow        FollowRepository
+	Auth          AuthRepository
+	Engagement    EngagementRepository
+	Repost        RepostRepository
+	Mention       MentionRepository
+	Attachment    AttachmentRepository
+	LinkPreview   LinkPreviewRepository
+	Poll          PollRepository
+	FollowRequest FollowRequestRepository
+	Block         BlockRepository
+	Suggestion    SuggestionRepository
+	Timeline      TimelineRepository
+	Trending      TrendingRepository
+	Federation    FederationRepository
+	Notification  NotificationRepository
+	Webhook       WebhookRepository
+	Outbox        OutboxRepository
 }
 
 type PostRepository interface {
-	Create(context.Context, *models.Post) error
+	Create(context.Context, *models.Post, []int64, *models.Poll, func(*models.Post) ([]*models.OutboxMessage, error)) error
 	GetByID(context.Context, int64) (*models.Post, error)
-	Delete(context.Context, int64) error
+	Delete(context.Context, int64, []*models.OutboxMessage) error
 	Update(context.Context, *models.Post) error
-	UpdateWithOptimisticLocking(context.Context, int64, func(*models.Post) error) (*models.Post, error)
-	GetFeed(context.Context, int64, int, int) ([]*models.FeedItem, int64, error)
+	UpdateWithOptimisticLocking(context.Context, int64, func(*models.Post) error, func(*models.Post) ([]*models.OutboxMessage, error)) (*models.Post, error)
+	GetFeed(context.Context, int64, *models.FeedPosition, *models.FeedPosition, int, int) ([]*models.FeedItem, int64, error)
+	CountFeedSince(context.Context, int64, *models.FeedPosition) (int64, error)
+	GetPublic(context.Context, int64, string, int) ([]*models.FeedItem, error)
+	GetRankedFeed(context.Context, int64, models.FeedRanking, time.Time, *models.RankedFeedPosition, int) ([]*models.FeedItem, int64, error)
+	CountByUser(context.Context, int64, int64) (int64, error)
 }
 
 type UserRepository interface {
 	GetAll(context.Context) ([]models.User, error)
 	GetByID(context.Context, int64) (*models.User, error)
-	FollowUser(context.Context, int64, int64) error
-	UnfollowUser(context.Context, int64, int64) error
+	GetByUsernames(context.Context, []string) ([]models.User, error)
+	UpdateProfile(context.Context, *models.User) error
 }
 type CommentRepository interface {
-	Create(context.Context, *models.Comment) (*models.Comment, error)
+	Create(context.Context, *models.Comment, []int64, func(*models.Comment) ([]*models.OutboxMessage, error)) (*models.Comment, error)
 	GetByPostID(context.Context, int64) ([]*models.Comment, error)
+	GetCommenterIDs(context.Context, int64) ([]int64, error)
+	Delete(context.Context, int64) error
 }
 
 type FollowRepository interface {
-	FollowUser(context.Context, int64, int64) error
-	UnfollowUser(context.Context, int64, int64) error
+	Follow(context.Context, int64, int64) (bool, error)
+	Unfollow(context.Context, int64, int64) (bool, error)
 	GetFollowerCount(context.Context, int64) (int64, error)
 	GetFollowingCount(context.Context, int64) (int64, error)
 	IsFollowing(context.Context, int64, int64) (bool, error)
+	GetFollowers(context.Context, int64, int64, *time.Time, int64, int) ([]*models.FollowEntry, error)
+	GetFollowing(context.Context, int64, int64, *time.Time, int64, int) ([]*models.FollowEntry, error)
+	GetEvents(context.Context, int64, int) ([]*models.FollowEvent, error)
+}
+
+type FollowRequestRepository interface {
+	Create(context.Context, int64, int64) error
+	Delete(context.Context, int64, int64) error
+	Approve(context.Context, int64, int64) error
+	ApproveAll(context.Context, int64) error
+	Exists(context.Context, int64, int64) (bool, error)
+	GetPending(context.Context, int64, *time.Time, int64, int) ([]*models.FollowRequest, error)
+}
+
+type BlockRepository interface {
+	Block(context.Context, int64, int64) error
+	Unblock(context.Context, int64, int64) error
+	Mute(context.Context, int64, int64) error
+	Unmute(context.Context, int64, int64) error
+	IsBlocked(context.Context, int64, int64) (bool, error)
+	BlockedAmong(context.Context, int64, []int64) (map[int64]bool, error)
+	GetBlocked(context.Context, int64, *time.Time, int64, int) ([]*models.UserListEntry, error)
+	GetMuted(context.Context, int64, *time.Time, int64, int) ([]*models.UserListEntry, error)
+}
+
+type SuggestionRepository interface {
+	LastComputed(context.Context, int64) (*time.Time, error)
+	Refresh(context.Context, int64, int) error
+	Get(context.Context, int64, int) ([]*models.FollowSuggestion, error)
+}
+
+type EngagementRepository interface {
+	ToggleLike(context.Context, int64, int64) (bool, int64, error)
+	ToggleBookmark(context.Context, int64, int64) (bool, error)
+	GetBookmarks(context.Context, int64, *time.Time, int64, int) ([]*models.FeedItem, error)
+}
+
+type RepostRepository interface {
+	Create(context.Context, *models.Repost) error
+	DeletePlain(context.Context, int64, int64) error
+	Delete(context.Context, int64, int64) error
+}
+
+type MentionRepository interface {
+	GetForPosts(context.Context, []int64) (map[int64][]models.Mention, error)
+	GetForComments(context.Context, []int64) (map[int64][]models.Mention, error)
+	GetMentioningPosts(context.Context, int64, *time.Time, int64, int) ([]*models.FeedItem, error)
+}
+
+type AttachmentRepository interface {
+	Create(context.Context, *models.Attachment) error
+	GetByID(context.Context, int64) (*models.Attachment, error)
+	GetByIDs(context.Context, []int64) ([]*models.Attachment, error)
+	GetForPosts(context.Context, []int64) (map[int64][]*models.Attachment, error)
+	GetForComments(context.Context, []int64) (map[int64][]*models.Attachment, error)
+	ClaimNext(context.Context, time.Duration) (*models.Attachment, error)
+	MarkProcessed(context.Context, *models.Attachment, []*models.AttachmentVariant) error
+	MarkFailed(context.Context, int64, string, bool) error
+	GetVariants(context.Context, []int64) (map[int64][]*models.AttachmentVariant, error)
+	GetVariant(context.Context, int64, string) (*models.AttachmentVariant, error)
+}
+
+type LinkPreviewRepository interface {
+	ReplaceForPost(context.Context, int64, []string, time.Duration) error
+	ClaimNext(context.Context, time.Duration) (string, int, error)
+	Save(context.Context, *models.LinkPreview) error
+	MarkFailed(context.Context, string, string, *time.Time) error
+	GetForPosts(context.Context, []int64) (map[int64][]models.LinkPreview, error)
+}
+
+type PollRepository interface {
+	GetByPostID(context.Context, int64) (*models.Poll, error)
+	GetForPosts(context.Context, []int64, int64) (map[int64]*models.Poll, error)
+	Vote(context.Context, int64, int64, []int64) error
+	RetractVote(context.Context, int64, int64) error
+}
+
+type TimelineRepository interface {
+	FanOutNext(context.Context, int) (bool, error)
+	Trim(context.Context) (int64, error)
+	ApplyFollowEvents(context.Context, int, int) (int, error)
+}
+
+type TrendingRepository interface {
+	Refresh(context.Context, models.TrendWindow, time.Time, time.Duration, int) (bool, error)
+	LastComputed(context.Context, models.TrendWindow) (*time.Time, error)
+	GetPosts(context.Context, models.TrendWindow, int64, int) ([]*models.FeedItem, error)
+	GetTags(context.Context, models.TrendWindow, int) ([]*models.TrendingTag, error)
+}
+
+type FederationRepository interface {
+	GetKeys(context.Context, int64) (*models.ActorKeys, error)
+	CreateKeys(context.Context, *models.ActorKeys) (*models.ActorKeys, error)
+	GetRemoteActor(context.Context, int64) (*models.RemoteActor, error)
+	GetRemoteActorByURI(context.Context, string) (*models.RemoteActor, error)
+	GetRemoteActorByKeyID(context.Context, string) (*models.RemoteActor, error)
+	SaveRemoteActor(context.Context, *models.RemoteActor) error
+	DeleteRemoteActor(context.Context, int64) error
+	GetFollowerInboxes(context.Context, int64) ([]string, error)
+	HasLocalFollowers(context.Context, int64) (bool, error)
+	SaveFollowActivity(context.Context, int64, int64, string) error
+	GetFollowActivity(context.Context, int64, int64) (string, error)
+	GetFollowByActivity(context.Context, string) (int64, int64, error)
+	DeleteFollowActivity(context.Context, int64, int64) error
+	CreateRemotePost(context.Context, *models.Post, string) (bool, error)
+	DeleteRemotePost(context.Context, int64, string) error
+	SetLike(context.Context, int64, int64, bool) error
+	EnqueueDeliveries(context.Context, int64, []string, []byte) error
+	ClaimDelivery(context.Context, time.Duration) (*models.Delivery, error)
+	CompleteDelivery(context.Context, int64) error
+	FailDelivery(context.Context, int64, string, *time.Time) error
+}
+
+type NotificationRepository interface {
+	Create(context.Context, models.NotificationKind, int64, []int64, *int64, *int64) ([]*models.Notification, error)
+	ApplyFollowEvents(context.Context, int) ([]*models.Notification, int, error)
+	List(context.Context, int64, *time.Time, int64, int) ([]*models.NotificationGroup, error)
+	CountUnread(context.Context, int64) (int64, error)
+	GetSince(context.Context, int64, int64, int) ([]*models.Notification, error)
+	LatestID(context.Context, int64) (int64, error)
+	MarkRead(context.Context, int64, int64) error
+	MarkAllRead(context.Context, int64) error
+	GetActors(context.Context, []int64) (map[int64]*models.PublicUser, error)
+	GetPreferences(context.Context, int64) (*models.NotificationPreferences, error)
+	SavePreferences(context.Context, int64, *models.NotificationPreferences) error
+	ClaimEmail(context.Context) (*models.NotificationEmail, error)
+	ClaimDigest(context.Context, models.NotificationDelivery, time.Duration) (*models.NotificationEmail, error)
+	ReleaseEmail(context.Context, []int64, time.Time) error
+}
+
+type WebhookRepository interface {
+	Create(context.Context, *models.Webhook) error
+	GetByID(context.Context, int64) (*models.Webhook, error)
+	List(context.Context) ([]*models.Webhook, error)
+	Update(context.Context, *models.Webhook) error
+	Delete(context.Context, int64) error
+	Enqueue(context.Context, models.WebhookEvent, string, []byte) (int64, error)
+	EnqueueFollowEvents(context.Context, int) (int, error)
+	ClaimDelivery(context.Context, time.Duration) (*models.WebhookDelivery, error)
+	SaveAttempt(context.Context, *models.WebhookDelivery, *time.Time) error
+	ListDeliveries(context.Context, int64, *time.Time, int64, int) ([]*models.WebhookDelivery, error)
+	Redeliver(context.Context, int64, int64) (*models.WebhookDelivery, error)
+}
+
+type OutboxRepository interface {
+	Add(context.Context, ...*models.OutboxMessage) error
+	Claim(context.Context, time.Duration) (*models.OutboxMessage, error)
+	Complete(context.Context, int64) error
+	Fail(context.Context, int64, string, *time.Time) error
+	ListFailed(context.Context, *time.Time, int64, int) ([]*models.OutboxMessage, error)
+	Retry(context.Context, int64) (*models.OutboxMessage, error)
 }
 
 type AuthRepository interface {
-	CreateAndInvite(context.Context, *models.User, string, time.Duration) error
+	CreateAndInvite(context.Context, *models.User, string, time.Duration, func(*models.User) ([]*models.OutboxMessage, error)) error
 	Create(context.Context, *models.User) error
 	GetUserFromInvitationToken(context.Context, string) (*models.User, error)
-	ActivateUser(context.Context, int64) error
-	DeleteInvitationToken(context.Context, string) error
+	ActivateUser(context.Context, int64, string, []*models.OutboxMessage) error
 }
 
 func NewStorage(db *sql.DB) Storage {
 	return Storage{
-		Post:    &PostStorage{db},
-		User:    &UserStorage{db},
-		Comment: &CommentStorage{db},
-		Follow:  &FollowStorage{db},
-		Auth:    &AuthStorage{db},
+		Post:          &PostStorage{db},
+		User:          &UserStorage{db},
+		Comment:       &CommentStorage{db},
+		Follow:        &FollowStorage{db},
+		Auth:          &AuthStorage{db},
+		Engagement:    &EngagementStorage{db},
+		Repost:        &RepostStorage{db},
+		Mention:       &MentionStorage{db},
+		Attachment:    &AttachmentStorage{db},
+		LinkPreview:   &LinkPreviewStorage{db},
+		Poll:          &PollStorage{db},
+		FollowRequest: &FollowRequestStorage{db},
+		Block:         &BlockStorage{db},
+		Suggestion:    &SuggestionStorage{db},
+		Timeline:      &TimelineStorage{db},
+		Trending:      &TrendingStorage{db},
+		Federation:    &FederationStorage{db},
+		Notification:  &NotificationStorage{db},
+		Webhook:       &WebhookStorage{db},
+		Outbox:        &OutboxStorage{db},
 	}
 }
 
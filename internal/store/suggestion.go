@@ -0,0 +1,172 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+)
+
+// Weights of the signals combined into a suggestion score. Activity decays
+// from 1 for an account that just posted with a time constant of a week.
+const (
+	suggestionMutualWeight   = 3.0
+	suggestionTagWeight      = 2.0
+	suggestionActivityWeight = 1.0
+)
+
+type SuggestionStorage struct {
+	db *sql.DB
+}
+
+// LastComputed returns when the suggestions of the user were last computed,
+// or nil if they never were.
+func (s *SuggestionStorage) LastComputed(ctx context.Context, userID int64) (*time.Time, error) {
+	query := `SELECT computed_at FROM follow_suggestion_runs WHERE user_id = $1`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var computedAt time.Time
+	if err := s.db.QueryRowContext(ctx, query, userID).Scan(&computedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &computedAt, nil
+}
+
+// Refresh recomputes the top size suggestions of the user. Candidates are the
+// accounts followed by the accounts the user follows, and the authors of
+// recent public posts sharing tags with the user's recent posts, so only the
+// user's neighbourhood of the graph is read.
+func (s *SuggestionStorage) Refresh(ctx context.Context, userID int64, size int) error {
+	query := `
+		WITH following AS (
+			SELECT user_id FROM followers WHERE follower_id = $1
+		), mutuals AS (
+			SELECT f.user_id AS candidate_id, COUNT(*) AS mutual_count
+			FROM followers f
+			INNER JOIN following fl ON fl.user_id = f.follower_id
+			GROUP BY f.user_id
+		), my_tags AS (
+			SELECT COALESCE(array_agg(DISTINCT t.tag), '{}') AS tags
+			FROM posts p
+			CROSS JOIN LATERAL unnest(p.tags) AS t(tag)
+			WHERE p.user_id = $1 AND p.created_at > NOW() - INTERVAL '90 days'
+		), tag_matches AS (
+			SELECT p.user_id AS candidate_id, COUNT(DISTINCT t.tag) AS shared_tag_count
+			FROM posts p
+			CROSS JOIN my_tags mt
+			CROSS JOIN LATERAL unnest(p.tags) AS t(tag)
+			WHERE p.tags && mt.tags
+				AND t.tag = ANY(mt.tags)
+				AND p.visibility = 'public'
+				AND p.created_at > NOW() - INTERVAL '90 days'
+			GROUP BY p.user_id
+		), candidates AS (
+			SELECT
+				COALESCE(m.candidate_id, tm.candidate_id) AS candidate_id,
+				COALESCE(m.mutual_count, 0) AS mutual_count,
+				COALESCE(tm.shared_tag_count, 0) AS shared_tag_count
+			FROM mutuals m
+			FULL OUTER JOIN tag_matches tm ON tm.candidate_id = m.candidate_id
+		)
+		INSERT INTO follow_suggestions (user_id, suggested_id, score, mutual_count, shared_tag_count)
+		SELECT
+			$1, c.candidate_id,
+			c.mutual_count * $3::float8 + c.shared_tag_count * $4::float8
+				+ $5::float8 * COALESCE(EXP(-EXTRACT(EPOCH FROM NOW() - a.last_post_at) / 604800.0), 0) AS score,
+			c.mutual_count, c.shared_tag_count
+		FROM candidates c
+		LEFT JOIN LATERAL (
+			SELECT MAX(created_at) AS last_post_at FROM posts WHERE user_id = c.candidate_id
+		) a ON TRUE
+		WHERE c.candidate_id <> $1
+			AND NOT EXISTS (SELECT 1 FROM following fl WHERE fl.user_id = c.candidate_id)
+			AND NOT EXISTS (
+				SELECT 1 FROM user_blocks b
+				WHERE (b.blocker_id = $1 AND b.blocked_id = c.candidate_id)
+					OR (b.blocker_id = c.candidate_id AND b.blocked_id = $1)
+			)
+		ORDER BY score DESC
+		LIMIT $2
+		ON CONFLICT (user_id, suggested_id) DO UPDATE
+		SET score = EXCLUDED.score,
+			mutual_count = EXCLUDED.mutual_count,
+			shared_tag_count = EXCLUDED.shared_tag_count
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return withTx(ctx, s.db, func(tx *sql.Tx) error {
+		// Taking the run row first serializes concurrent refreshes of the
+		// same user.
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO follow_suggestion_runs (user_id, computed_at) VALUES ($1, NOW())
+			ON CONFLICT (user_id) DO UPDATE SET computed_at = NOW()
+		`, userID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM follow_suggestions WHERE user_id = $1`, userID); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, query, userID, size,
+			suggestionMutualWeight, suggestionTagWeight, suggestionActivityWeight)
+		return err
+	})
+}
+
+// Get returns up to limit precomputed suggestions for the user, best first.
+// Accounts the user followed, asked to follow or blocked since the
+// suggestions were computed are skipped.
+func (s *SuggestionStorage) Get(ctx context.Context, userID int64, limit int) ([]*models.FollowSuggestion, error) {
+	query := `
+		SELECT
+			u.id, u.username, u.display_name, u.avatar_attachment_id, u.created_at,
+			fs.mutual_count, fs.shared_tag_count
+		FROM follow_suggestions fs
+		INNER JOIN users u ON u.id = fs.suggested_id
+		WHERE fs.user_id = $1
+			AND NOT EXISTS (SELECT 1 FROM followers f WHERE f.user_id = fs.suggested_id AND f.follower_id = $1)
+			AND NOT EXISTS (SELECT 1 FROM follow_requests fr WHERE fr.user_id = fs.suggested_id AND fr.requester_id = $1)
+			AND NOT EXISTS (
+				SELECT 1 FROM user_blocks b
+				WHERE (b.blocker_id = $1 AND b.blocked_id = fs.suggested_id)
+					OR (b.blocker_id = fs.suggested_id AND b.blocked_id = $1)
+			)
+		ORDER BY fs.score DESC, fs.suggested_id
+		LIMIT $2
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suggestions := []*models.FollowSuggestion{}
+	for rows.Next() {
+		var fs models.FollowSuggestion
+		err := rows.Scan(
+			&fs.User.ID, &fs.User.Username, &fs.User.DisplayName, &fs.User.AvatarID, &fs.User.CreatedAt,
+			&fs.MutualCount, &fs.SharedTagCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, &fs)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
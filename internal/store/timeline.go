@@ -0,0 +1,300 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+)
+
+const (
+	// timelineWindow is how far back home timelines reach. Older entries are
+	// trimmed and never read.
+	timelineWindow = `INTERVAL '30 days'`
+	// timelineTrimBatchSize is the number of expired entries deleted in one
+	// statement.
+	timelineTrimBatchSize = 1000
+)
+
+// timelineEntriesCTE selects the home timeline entries of the viewer bound at
+// $1: the materialized entries plus the posts and reposts of followed pull
+// authors, which are not fanned out. Entries of others are only shown while
+// the viewer still follows their actor, so unfollows take effect before the
+// entries are pruned. Posts and reposts involving users muted by the viewer are left out.
+// Plain reposts share the dedupe key of the original post so each post
+// appears once, at its most recent activity; quote posts carry their own
+// commentary and are kept as separate entries. Only activity within
+// timelineWindow is read.
+const timelineEntriesCTE = `
+	WITH entries AS (
+		SELECT t.post_id, t.repost_id, t.activity_at, t.dedupe_key, t.actor_id
+		FROM timeline_entries t
+		WHERE t.user_id = $1 AND t.activity_at > NOW() - ` + timelineWindow + `
+		UNION ALL
+		SELECT p.id, NULL::BIGINT, p.created_at, 'p' || p.id, p.user_id
+		FROM timeline_pull_authors pa
+		INNER JOIN followers f ON f.user_id = pa.user_id AND f.follower_id = $1
+		INNER JOIN posts p ON p.user_id = pa.user_id
+		WHERE p.created_at > NOW() - ` + timelineWindow + `
+		UNION ALL
+		SELECT r.post_id, r.id, r.created_at,
+			CASE WHEN r.quote IS NULL THEN 'p' || r.post_id ELSE 'q' || r.id END,
+			r.user_id
+		FROM timeline_pull_authors pa
+		INNER JOIN followers f ON f.user_id = pa.user_id AND f.follower_id = $1
+		INNER JOIN reposts r ON r.user_id = pa.user_id
+		WHERE r.created_at > NOW() - ` + timelineWindow + `
+	), feed_entries AS (
+		SELECT DISTINCT ON (e.dedupe_key) e.post_id, e.repost_id, e.activity_at
+		FROM entries e
+		INNER JOIN posts op ON op.id = e.post_id
+		WHERE (e.actor_id = $1 OR EXISTS (SELECT 1 FROM followers f WHERE f.user_id = e.actor_id AND f.follower_id = $1))
+			AND NOT EXISTS (SELECT 1 FROM user_mutes um WHERE um.muter_id = $1 AND um.muted_id IN (e.actor_id, op.user_id))
+		ORDER BY e.dedupe_key, e.activity_at DESC
+	)
+`
+
+type TimelineStorage struct {
+	db *sql.DB
+}
+
+// FanOutNext fans out the oldest post, or else repost, still waiting to be
+// fanned out and reports whether there was one. Posts and reposts are
+// pending from their creation, so none is missed when the fan-out fails; a
+// fan-out is retried until it succeeds. Concurrent instances skip the rows
+// being fanned out by others.
+func (s *TimelineStorage) FanOutNext(ctx context.Context, maxFollowers int) (bool, error) {
+	var found bool
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+		defer cancel()
+
+		var entry models.TimelineEntry
+		query := `
+			SELECT id, user_id, 'p' || id, created_at FROM posts
+			WHERE timeline_pending
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		`
+		err := tx.QueryRowContext(ctx, query).Scan(&entry.PostID, &entry.ActorID, &entry.DedupeKey, &entry.ActivityAt)
+		if err == nil {
+			found = true
+			if err := fanOut(ctx, tx, &entry, maxFollowers); err != nil {
+				return err
+			}
+			_, err = tx.ExecContext(ctx, `UPDATE posts SET timeline_pending = FALSE WHERE id = $1`, entry.PostID)
+			return err
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		// Plain reposts share the dedupe key of the original post.
+		query = `
+			SELECT id, post_id, user_id,
+				CASE WHEN quote IS NULL THEN 'p' || post_id ELSE 'q' || id END, created_at
+			FROM reposts
+			WHERE timeline_pending
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		`
+		var repostID int64
+		err = tx.QueryRowContext(ctx, query).Scan(&repostID, &entry.PostID, &entry.ActorID, &entry.DedupeKey, &entry.ActivityAt)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil
+			}
+			return err
+		}
+		found = true
+		entry.RepostID = &repostID
+		if err := fanOut(ctx, tx, &entry, maxFollowers); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, `UPDATE reposts SET timeline_pending = FALSE WHERE id = $1`, repostID)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return found, nil
+}
+
+// fanOut adds the entry to the home timelines of its actor and their
+// followers. Actors with more than maxFollowers followers become pull authors
+// instead, and stay one, so their activity is merged into their followers'
+// timelines when they are read. A timeline holds one entry per dedupe key,
+// moved to the most recent activity, and none outside timelineWindow.
+func fanOut(ctx context.Context, tx *sql.Tx, entry *models.TimelineEntry, maxFollowers int) error {
+	// Count at most one follower past the limit.
+	query := `
+		SELECT EXISTS (SELECT 1 FROM timeline_pull_authors WHERE user_id = $1)
+			OR (SELECT COUNT(*) FROM (SELECT 1 FROM followers WHERE user_id = $1 LIMIT $2 + 1) f) > $2
+	`
+	var pull bool
+	if err := tx.QueryRowContext(ctx, query, entry.ActorID, maxFollowers).Scan(&pull); err != nil {
+		return err
+	}
+
+	if pull {
+		query = `INSERT INTO timeline_pull_authors (user_id) VALUES ($1) ON CONFLICT (user_id) DO NOTHING`
+		if _, err := tx.ExecContext(ctx, query, entry.ActorID); err != nil {
+			return err
+		}
+	}
+
+	query = `
+		INSERT INTO timeline_entries (user_id, post_id, repost_id, actor_id, dedupe_key, activity_at)
+		SELECT audience.user_id, $2, $3, $1, $4, $5
+		FROM (
+			SELECT $1::BIGINT AS user_id
+			UNION
+			SELECT follower_id FROM followers WHERE user_id = $1 AND NOT $6::BOOLEAN
+		) audience
+		WHERE $5::timestamptz > NOW() - ` + timelineWindow + `
+		ON CONFLICT (user_id, dedupe_key) DO UPDATE
+		SET post_id = EXCLUDED.post_id, repost_id = EXCLUDED.repost_id,
+			actor_id = EXCLUDED.actor_id, activity_at = EXCLUDED.activity_at
+		WHERE EXCLUDED.activity_at > timeline_entries.activity_at
+	`
+	_, err := tx.ExecContext(ctx, query, entry.ActorID, entry.PostID, entry.RepostID, entry.DedupeKey, entry.ActivityAt, pull)
+	return err
+}
+
+// ApplyFollowEvents brings the home timelines up to date with up to limit
+// follow events not applied yet. Followers get the latest
+// backfillSize posts and reposts of the users they started following, and
+// lose the entries of the users they stopped following, falling back to the
+// original post where its author is still followed. Only the follow state
+// at the time of the call is looked at, so events can be replayed safely. It
+// returns the number of events handled.
+func (s *TimelineStorage) ApplyFollowEvents(ctx context.Context, limit, backfillSize int) (int, error) {
+	type pair struct{ userID, followerID int64 }
+
+	var handled int
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+		defer cancel()
+
+		// Concurrent instances skip the events being applied by others.
+		query := `
+			SELECT id, user_id, follower_id FROM follow_events
+			WHERE NOT timeline_applied
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		`
+		rows, err := tx.QueryContext(ctx, query, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		var ids []int64
+		var pairs []pair
+		seen := make(map[pair]bool)
+		for rows.Next() {
+			var id int64
+			var p pair
+			if err := rows.Scan(&id, &p.userID, &p.followerID); err != nil {
+				return err
+			}
+			ids = append(ids, id)
+			handled++
+			if !seen[p] {
+				seen[p] = true
+				pairs = append(pairs, p)
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if handled == 0 {
+			return nil
+		}
+
+		// A plain repost took the place of the original post in the
+		// timeline, so put the post back while its author is followed.
+		revert := `
+			UPDATE timeline_entries t
+			SET repost_id = NULL, actor_id = p.user_id, activity_at = p.created_at
+			FROM posts p
+			WHERE t.user_id = $2 AND t.actor_id = $1 AND t.repost_id IS NOT NULL
+				AND t.dedupe_key = 'p' || t.post_id AND p.id = t.post_id
+				AND NOT EXISTS (SELECT 1 FROM followers f WHERE f.user_id = $1 AND f.follower_id = $2)
+				AND (p.user_id = $2 OR EXISTS (SELECT 1 FROM followers f WHERE f.user_id = p.user_id AND f.follower_id = $2))
+		`
+		prune := `
+			DELETE FROM timeline_entries t
+			WHERE t.user_id = $2 AND t.actor_id = $1
+				AND NOT EXISTS (SELECT 1 FROM followers f WHERE f.user_id = $1 AND f.follower_id = $2)
+		`
+		backfill := `
+			INSERT INTO timeline_entries (user_id, post_id, repost_id, actor_id, dedupe_key, activity_at)
+			SELECT DISTINCT ON (e.dedupe_key) $2, e.post_id, e.repost_id, $1, e.dedupe_key, e.activity_at
+			FROM (
+				(SELECT p.id AS post_id, NULL::BIGINT AS repost_id, 'p' || p.id AS dedupe_key, p.created_at AS activity_at
+				FROM posts p
+				WHERE p.user_id = $1
+				ORDER BY p.created_at DESC
+				LIMIT $3)
+				UNION ALL
+				(SELECT r.post_id, r.id, CASE WHEN r.quote IS NULL THEN 'p' || r.post_id ELSE 'q' || r.id END, r.created_at
+				FROM reposts r
+				WHERE r.user_id = $1
+				ORDER BY r.created_at DESC
+				LIMIT $3)
+				ORDER BY activity_at DESC
+				LIMIT $3
+			) e
+			WHERE e.activity_at > NOW() - ` + timelineWindow + `
+				AND EXISTS (SELECT 1 FROM followers f WHERE f.user_id = $1 AND f.follower_id = $2)
+				AND NOT EXISTS (SELECT 1 FROM timeline_pull_authors pa WHERE pa.user_id = $1)
+			ORDER BY e.dedupe_key, e.activity_at DESC
+			ON CONFLICT (user_id, dedupe_key) DO UPDATE
+			SET post_id = EXCLUDED.post_id, repost_id = EXCLUDED.repost_id,
+				actor_id = EXCLUDED.actor_id, activity_at = EXCLUDED.activity_at
+			WHERE EXCLUDED.activity_at > timeline_entries.activity_at
+		`
+		for _, p := range pairs {
+			if _, err := tx.ExecContext(ctx, revert, p.userID, p.followerID); err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, prune, p.userID, p.followerID); err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, backfill, p.userID, p.followerID, backfillSize); err != nil {
+				return err
+			}
+		}
+
+		return markFollowEvents(ctx, tx, "timeline_applied", ids)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return handled, nil
+}
+
+// Trim deletes up to timelineTrimBatchSize entries that fell out of
+// timelineWindow and returns the number deleted.
+func (s *TimelineStorage) Trim(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	query := `
+		DELETE FROM timeline_entries
+		WHERE ctid IN (
+			SELECT ctid FROM timeline_entries
+			WHERE activity_at <= NOW() - ` + timelineWindow + `
+			LIMIT $1
+		)
+	`
+	res, err := s.db.ExecContext(ctx, query, timelineTrimBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,194 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+)
+
+// trendingEngagementsCTE selects who engaged with which post since $2 by
+// liking, commenting on or reposting it. The UNION counts an account once per
+// post however often it interacted, and authors engaging with their own posts
+// are left out.
+const trendingEngagementsCTE = `
+	WITH engagements AS (
+		SELECT post_id, user_id FROM post_likes WHERE created_at > $2
+		UNION
+		SELECT post_id, user_id FROM comments WHERE created_at > $2
+		UNION
+		SELECT post_id, user_id FROM reposts WHERE created_at > $2
+	), post_engagers AS (
+		SELECT e.post_id, COUNT(*) AS engager_count
+		FROM engagements e
+		INNER JOIN posts p ON p.id = e.post_id
+		WHERE e.user_id <> p.user_id
+		GROUP BY e.post_id
+	), candidates AS (
+		SELECT p.id, p.user_id, p.tags, p.created_at, COALESCE(pe.engager_count, 0) AS engager_count
+		FROM posts p
+		INNER JOIN users u ON u.id = p.user_id
+		LEFT JOIN post_engagers pe ON pe.post_id = p.id
+		WHERE p.created_at > $2 AND p.visibility = 'public' AND NOT u.is_private
+	)
+`
+
+type TrendingStorage struct {
+	db *sql.DB
+}
+
+// Refresh recomputes the top size trending posts and tags of the window
+// covering the activity since since, unless another instance is already
+// doing so or it was recomputed less than interval ago. Posts are scored by
+// their engagers, decaying with age; tags by their authors plus the
+// engagers of their posts. Only public posts of public accounts are
+// considered. It reports whether the window was recomputed.
+func (s *TrendingStorage) Refresh(ctx context.Context, window models.TrendWindow, since time.Time, interval time.Duration, size int) (bool, error) {
+	var refreshed bool
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+		defer cancel()
+
+		query := `INSERT INTO trending_runs (period) VALUES ($1) ON CONFLICT (period) DO NOTHING`
+		if _, err := tx.ExecContext(ctx, query, window); err != nil {
+			return err
+		}
+
+		var computedAt time.Time
+		query = `SELECT computed_at FROM trending_runs WHERE period = $1 FOR UPDATE SKIP LOCKED`
+		if err := tx.QueryRowContext(ctx, query, window).Scan(&computedAt); err != nil {
+			if err == sql.ErrNoRows {
+				return nil
+			}
+			return err
+		}
+		if time.Since(computedAt) < interval {
+			return nil
+		}
+
+		query = `DELETE FROM trending_posts WHERE period = $1`
+		if _, err := tx.ExecContext(ctx, query, window); err != nil {
+			return err
+		}
+		query = trendingEngagementsCTE + `
+			INSERT INTO trending_posts (period, post_id, score, engager_count)
+			SELECT $1, id, engager_count / power(EXTRACT(EPOCH FROM NOW() - created_at) / 3600 + 2, 1.5), engager_count
+			FROM candidates
+			WHERE engager_count > 0
+			ORDER BY 3 DESC
+			LIMIT $3
+		`
+		if _, err := tx.ExecContext(ctx, query, window, since, size); err != nil {
+			return err
+		}
+
+		query = `DELETE FROM trending_tags WHERE period = $1`
+		if _, err := tx.ExecContext(ctx, query, window); err != nil {
+			return err
+		}
+		query = trendingEngagementsCTE + `, tagged AS (
+				SELECT DISTINCT t.tag, c.id, c.user_id, c.engager_count
+				FROM candidates c
+				CROSS JOIN LATERAL unnest(c.tags) AS t(tag)
+			)
+			INSERT INTO trending_tags (period, tag, score, post_count, author_count)
+			SELECT $1, tag, COUNT(DISTINCT user_id) + SUM(engager_count), COUNT(*), COUNT(DISTINCT user_id)
+			FROM tagged
+			GROUP BY tag
+			ORDER BY 3 DESC
+			LIMIT $3
+		`
+		if _, err := tx.ExecContext(ctx, query, window, since, size); err != nil {
+			return err
+		}
+
+		query = `UPDATE trending_runs SET computed_at = NOW() WHERE period = $1`
+		if _, err := tx.ExecContext(ctx, query, window); err != nil {
+			return err
+		}
+		refreshed = true
+		return nil
+	})
+	return refreshed, err
+}
+
+// LastComputed returns when the window was last recomputed, or nil if it
+// never was.
+func (s *TrendingStorage) LastComputed(ctx context.Context, window models.TrendWindow) (*time.Time, error) {
+	query := `SELECT computed_at FROM trending_runs WHERE period = $1 AND computed_at > 'epoch'`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	var computedAt time.Time
+	if err := s.db.QueryRowContext(ctx, query, window).Scan(&computedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &computedAt, nil
+}
+
+// GetPosts returns up to limit trending posts of the window that the viewer
+// is allowed to see, leaving out authors the viewer muted.
+func (s *TrendingStorage) GetPosts(ctx context.Context, window models.TrendWindow, viewerID int64, limit int) ([]*models.FeedItem, error) {
+	query := `
+		SELECT ` + feedItemColumns + `
+		FROM (
+			SELECT post_id, NULL::BIGINT AS repost_id, score
+			FROM trending_posts
+			WHERE period = $2
+		) e
+		` + feedItemJoins + `
+		WHERE ` + visiblePostClause("p", 1) + `
+			AND NOT EXISTS (SELECT 1 FROM user_mutes um WHERE um.muter_id = $1 AND um.muted_id = p.user_id)
+		ORDER BY e.score DESC
+		LIMIT $3
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, viewerID, window, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanFeedItems(rows)
+}
+
+// GetTags returns up to limit trending tags of the window, highest scoring
+// first.
+func (s *TrendingStorage) GetTags(ctx context.Context, window models.TrendWindow, limit int) ([]*models.TrendingTag, error) {
+	query := `
+		SELECT tag, post_count, author_count
+		FROM trending_tags
+		WHERE period = $1
+		ORDER BY score DESC, tag
+		LIMIT $2
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, window, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []*models.TrendingTag{}
+	for rows.Next() {
+		var t models.TrendingTag
+		if err := rows.Scan(&t.Tag, &t.PostCount, &t.AuthorCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, &t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
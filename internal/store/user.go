@@ -3,9 +3,10 @@ package store
 import (
 	"context"
 	"database/sql"
+	"strings"
 
 	"github.com/LikhithMar14/gopher-chat/internal/models"
-	apperrors "github.com/LikhithMar14/gopher-chat/internal/utils/errors"
+	"github.com/lib/pq"
 )
 
 type UserStorage struct {
@@ -22,7 +23,7 @@ func (s *UserStorage) Create(ctx context.Context, user *models.User) error {
 }
 
 func (s *UserStorage) GetAll(ctx context.Context) ([]models.User, error) {
-	query := `SELECT id, username, email, activated, created_at, updated_at FROM users ORDER BY created_at DESC`
+	query := `SELECT id, username, COALESCE(email, ''), activated, display_name, bio, links, avatar_attachment_id, is_private, COALESCE(domain, ''), created_at, updated_at FROM users ORDER BY created_at DESC`
 
 	rows, err := s.db.QueryContext(ctx, query)
 	if err != nil {
@@ -33,7 +34,7 @@ func (s *UserStorage) GetAll(ctx context.Context) ([]models.User, error) {
 	var users []models.User
 	for rows.Next() {
 		var user models.User
-		err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.Activated, &user.CreatedAt, &user.UpdatedAt)
+		err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.Activated, &user.DisplayName, &user.Bio, pq.Array(&user.Links), &user.AvatarID, &user.IsPrivate, &user.Domain, &user.CreatedAt, &user.UpdatedAt)
 		if err != nil {
 			return nil, err
 		}
@@ -48,13 +49,13 @@ func (s *UserStorage) GetAll(ctx context.Context) ([]models.User, error) {
 }
 
 func (s *UserStorage) GetByID(ctx context.Context, userID int64) (*models.User, error) {
-	query := `SELECT id, username, email, password_hash, activated, created_at, updated_at FROM users WHERE id = $1`
+	query := `SELECT id, username, COALESCE(email, ''), password_hash, activated, display_name, bio, links, avatar_attachment_id, is_private, COALESCE(domain, ''), created_at, updated_at FROM users WHERE id = $1`
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
 	defer cancel()
 
 	var user models.User
 	var passwordHash []byte
-	err := s.db.QueryRowContext(ctx, query, userID).Scan(&user.ID, &user.Username, &user.Email, &passwordHash, &user.Activated, &user.CreatedAt, &user.UpdatedAt)
+	err := s.db.QueryRowContext(ctx, query, userID).Scan(&user.ID, &user.Username, &user.Email, &passwordHash, &user.Activated, &user.DisplayName, &user.Bio, pq.Array(&user.Links), &user.AvatarID, &user.IsPrivate, &user.Domain, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		switch {
 		case err == sql.ErrNoRows:
@@ -69,41 +70,64 @@ func (s *UserStorage) GetByID(ctx context.Context, userID int64) (*models.User,
 	return &user, nil
 }
 
-func (s *UserStorage) FollowUser(ctx context.Context, userID int64, followerID int64) error {
-	query := `INSERT INTO followers (user_id, follower_id) VALUES ($1, $2)`
+// UpdateProfile saves the display name, bio, links, avatar and privacy of the
+// user and refreshes its updated_at.
+func (s *UserStorage) UpdateProfile(ctx context.Context, user *models.User) error {
+	query := `
+		UPDATE users
+		SET display_name = $1, bio = $2, links = $3, avatar_attachment_id = $4, is_private = $5, updated_at = NOW()
+		WHERE id = $6
+		RETURNING updated_at
+	`
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
 	defer cancel()
 
-	_, err := s.db.ExecContext(ctx, query, userID, followerID)
+	links := user.Links
+	if links == nil {
+		links = []string{}
+	}
+
+	err := s.db.QueryRowContext(ctx, query, user.DisplayName, user.Bio, pq.Array(links), user.AvatarID, user.IsPrivate, user.ID).Scan(&user.UpdatedAt)
 	if err != nil {
-		// Check for duplicate key constraint violation (PostgreSQL specific)
-		if err.Error() == "pq: duplicate key value violates unique constraint \"followers_pkey\"" ||
-			err.Error() == "UNIQUE constraint failed: followers.user_id, followers.follower_id" {
-			return apperrors.ErrConflict
+		if err == sql.ErrNoRows {
+			return ErrNotFound
 		}
 		return err
 	}
+
 	return nil
 }
 
-func (s *UserStorage) UnfollowUser(ctx context.Context, userID int64, followerID int64) error {
-	query := `DELETE FROM followers WHERE user_id = $1 AND follower_id = $2`
+// GetByUsernames returns the users whose username is in usernames, compared
+// case-insensitively. Unknown usernames are skipped.
+func (s *UserStorage) GetByUsernames(ctx context.Context, usernames []string) ([]models.User, error) {
+	query := `SELECT id, username, COALESCE(email, ''), activated, created_at, updated_at FROM users WHERE lower(username) = ANY($1)`
 	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
 	defer cancel()
 
-	result, err := s.db.ExecContext(ctx, query, userID, followerID)
-	if err != nil {
-		return err
+	lowered := make([]string, 0, len(usernames))
+	for _, u := range usernames {
+		lowered = append(lowered, strings.ToLower(u))
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	rows, err := s.db.QueryContext(ctx, query, pq.Array(lowered))
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.Activated, &user.CreatedAt, &user.UpdatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
 	}
 
-	if rowsAffected == 0 {
-		return ErrNotFound
+	if err = rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return users, nil
 }
@@ -0,0 +1,329 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/internal/models"
+	"github.com/lib/pq"
+)
+
+type WebhookStorage struct {
+	db *sql.DB
+}
+
+const webhookColumns = `id, url, events, description, active, created_at, updated_at`
+
+const webhookDeliveryColumns = `d.id, d.webhook_id, d.event_id, d.event, d.payload, d.redelivery_of, d.status, d.attempts,
+	d.next_attempt_at, d.response_status, d.response_body, d.error, d.duration_ms, d.created_at, d.completed_at`
+
+// Create stores a webhook, setting its id and timestamps.
+func (s *WebhookStorage) Create(ctx context.Context, webhook *models.Webhook) error {
+	query := `
+		INSERT INTO webhooks (url, secret, events, description, active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	return s.db.QueryRowContext(ctx, query, webhook.URL, webhook.Secret, pq.Array(webhook.Events), webhook.Description, webhook.Active).
+		Scan(&webhook.ID, &webhook.CreatedAt, &webhook.UpdatedAt)
+}
+
+// GetByID returns the webhook with the given id, without its secret.
+func (s *WebhookStorage) GetByID(ctx context.Context, id int64) (*models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	webhook, err := scanWebhook(s.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// List returns every webhook, oldest first, without their secrets.
+func (s *WebhookStorage) List(ctx context.Context) ([]*models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks ORDER BY id`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []*models.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+// Update saves the URL, events, description and active flag of a webhook.
+func (s *WebhookStorage) Update(ctx context.Context, webhook *models.Webhook) error {
+	query := `
+		UPDATE webhooks SET url = $2, events = $3, description = $4, active = $5, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	err := s.db.QueryRowContext(ctx, query, webhook.ID, webhook.URL, pq.Array(webhook.Events), webhook.Description, webhook.Active).
+		Scan(&webhook.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Delete removes a webhook with its deliveries.
+func (s *WebhookStorage) Delete(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Enqueue queues the delivery of an event to every active webhook
+// subscribed to it and returns the number of deliveries queued. Webhooks
+// the event was already queued for are skipped.
+func (s *WebhookStorage) Enqueue(ctx context.Context, event models.WebhookEvent, eventID string, payload []byte) (int64, error) {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event, payload)
+		SELECT id, $2::UUID, $1::TEXT, $3::TEXT FROM webhooks
+		WHERE active AND (events = '{}' OR $1::TEXT = ANY(events))
+		ON CONFLICT (webhook_id, event_id) WHERE redelivery_of IS NULL DO NOTHING
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	res, err := s.db.ExecContext(ctx, query, event, eventID, string(payload))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// EnqueueFollowEvents queues follow.created and follow.deleted deliveries
+// for up to limit follow events not queued yet. It returns the number of
+// events handled.
+func (s *WebhookStorage) EnqueueFollowEvents(ctx context.Context, limit int) (int, error) {
+	var handled int
+	err := withTx(ctx, s.db, func(tx *sql.Tx) error {
+		ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+		defer cancel()
+
+		ids, err := claimFollowEvents(ctx, tx, "webhooks_queued", limit)
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		handled = len(ids)
+
+		// The event id is derived from the follow event, so it is the
+		// same for every webhook.
+		query := `
+			WITH events AS (
+				SELECT md5('follow_event:' || fe.id)::UUID AS event_id,
+					CASE fe.kind WHEN 'follow' THEN 'follow.created' ELSE 'follow.deleted' END AS event,
+					fe.id, fe.user_id, fe.follower_id, fe.kind, fe.created_at
+				FROM follow_events fe
+				WHERE fe.id = ANY($1)
+			)
+			INSERT INTO webhook_deliveries (webhook_id, event_id, event, payload)
+			SELECT w.id, e.event_id, e.event, json_build_object(
+				'id', e.event_id,
+				'event', e.event,
+				'created_at', e.created_at,
+				'data', json_build_object(
+					'id', e.id, 'user_id', e.user_id, 'follower_id', e.follower_id,
+					'kind', e.kind, 'created_at', e.created_at
+				)
+			)::TEXT
+			FROM events e
+			INNER JOIN webhooks w ON w.active AND (w.events = '{}' OR e.event = ANY(w.events))
+			ORDER BY e.id, w.id
+			ON CONFLICT (webhook_id, event_id) WHERE redelivery_of IS NULL DO NOTHING
+		`
+		if _, err := tx.ExecContext(ctx, query, pq.Array(ids)); err != nil {
+			return err
+		}
+
+		return markFollowEvents(ctx, tx, "webhooks_queued", ids)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return handled, nil
+}
+
+// ClaimDelivery marks the delivery to an active webhook that is due the
+// longest as in progress and returns it with the URL and secret of its
+// webhook. Attempts counts this attempt. Deliveries left in progress for
+// longer than staleAfter are claimed again. ErrNotFound is returned when
+// nothing is due.
+func (s *WebhookStorage) ClaimDelivery(ctx context.Context, staleAfter time.Duration) (*models.WebhookDelivery, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE webhook_deliveries SET status = 'delivering', started_at = NOW(), attempts = attempts + 1
+			WHERE id = (
+				SELECT wd.id FROM webhook_deliveries wd
+				INNER JOIN webhooks w ON w.id = wd.webhook_id AND w.active
+				WHERE (wd.status = 'pending' AND wd.next_attempt_at <= NOW())
+				   OR (wd.status = 'delivering' AND wd.started_at < NOW() - make_interval(secs => $1))
+				ORDER BY wd.next_attempt_at
+				FOR UPDATE OF wd SKIP LOCKED
+				LIMIT 1
+			)
+			RETURNING *
+		)
+		SELECT ` + webhookDeliveryColumns + `, w.url, w.secret
+		FROM claimed d
+		INNER JOIN webhooks w ON w.id = d.webhook_id
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	row := s.db.QueryRowContext(ctx, query, staleAfter.Seconds())
+	d, err := scanWebhookDelivery(row, true)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+// SaveAttempt records the outcome of the latest attempt of a delivery, held
+// in its response fields. Unsuccessful deliveries are retried at retryAt,
+// or given up on when it is nil.
+func (s *WebhookStorage) SaveAttempt(ctx context.Context, d *models.WebhookDelivery, retryAt *time.Time) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = CASE WHEN $2 THEN 'succeeded' WHEN $3::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+			next_attempt_at = COALESCE($3, next_attempt_at),
+			completed_at = CASE WHEN $2 OR $3::timestamptz IS NULL THEN NOW() END,
+			response_status = $4, response_body = $5, error = $6, duration_ms = $7
+		WHERE id = $1
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	succeeded := d.Status == models.WebhookDeliverySucceeded
+	_, err := s.db.ExecContext(ctx, query, d.ID, succeeded, retryAt, d.ResponseStatus, d.ResponseBody, d.Error, d.DurationMS)
+	return err
+}
+
+// ListDeliveries returns up to limit deliveries of webhookID created before
+// the cursor (before, beforeID), latest first.
+func (s *WebhookStorage) ListDeliveries(ctx context.Context, webhookID int64, before *time.Time, beforeID int64, limit int) ([]*models.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries d
+		WHERE d.webhook_id = $1 AND ($2::timestamptz IS NULL OR (d.created_at, d.id) < ($2, $3))
+		ORDER BY d.created_at DESC, d.id DESC
+		LIMIT $4
+	`
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, webhookID, before, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []*models.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows, false)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// Redeliver queues a new delivery of the event of the delivery id of
+// webhookID and returns it. ErrNotFound is returned when webhookID has no
+// delivery id.
+func (s *WebhookStorage) Redeliver(ctx context.Context, webhookID, id int64) (*models.WebhookDelivery, error) {
+	query := `
+		INSERT INTO webhook_deliveries AS d (webhook_id, event_id, event, payload, redelivery_of)
+		SELECT webhook_id, event_id, event, payload, id FROM webhook_deliveries
+		WHERE id = $2 AND webhook_id = $1
+		RETURNING ` + webhookDeliveryColumns
+	ctx, cancel := context.WithTimeout(ctx, QueryTimeoutDuration)
+	defer cancel()
+
+	d, err := scanWebhookDelivery(s.db.QueryRowContext(ctx, query, webhookID, id), false)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+func scanWebhook(row interface{ Scan(...any) error }) (*models.Webhook, error) {
+	var w models.Webhook
+	var events []string
+	err := row.Scan(&w.ID, &w.URL, pq.Array(&events), &w.Description, &w.Active, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	w.Events = make([]models.WebhookEvent, 0, len(events))
+	for _, e := range events {
+		w.Events = append(w.Events, models.WebhookEvent(e))
+	}
+	return &w, nil
+}
+
+// scanWebhookDelivery scans a row of webhookDeliveryColumns, followed by
+// the URL and secret of the webhook when withWebhook is set.
+func scanWebhookDelivery(row interface{ Scan(...any) error }, withWebhook bool) (*models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	var payload string
+	var nextAttemptAt time.Time
+	dest := []any{&d.ID, &d.WebhookID, &d.EventID, &d.Event, &payload, &d.RedeliveryOf, &d.Status, &d.Attempts,
+		&nextAttemptAt, &d.ResponseStatus, &d.ResponseBody, &d.Error, &d.DurationMS, &d.CreatedAt, &d.CompletedAt}
+	if withWebhook {
+		dest = append(dest, &d.URL, &d.Secret)
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	d.Payload = []byte(payload)
+	if d.Status == models.WebhookDeliveryPending {
+		d.NextAttemptAt = &nextAttemptAt
+	}
+	return &d, nil
+}
@@ -0,0 +1,139 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 is a minimal in-memory stand-in for an S3-compatible server. It only
+// accepts requests carrying a SigV4 Authorization header for its access key.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=test-key/") || !strings.Contains(auth, "Signature=") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if r.Header.Get("X-Amz-Date") == "" || r.Header.Get("X-Amz-Content-Sha256") == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = body
+	case http.MethodGet:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestStores(t *testing.T) {
+	srv := httptest.NewServer(&fakeS3{objects: map[string][]byte{}})
+	defer srv.Close()
+
+	s3, err := NewS3(srv.URL, "uploads", "us-east-1", "test-key", "test-secret")
+	if err != nil {
+		t.Fatalf("NewS3() error = %v", err)
+	}
+	local, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocal() error = %v", err)
+	}
+
+	stores := map[string]Store{"s3": s3, "local": local}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			key := "attachments/2025/file name.txt"
+			content := []byte("hello gopher")
+
+			if err := store.Put(ctx, key, bytes.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+				t.Fatalf("Put() error = %v", err)
+			}
+
+			rc, err := store.Get(ctx, key)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			got, _ := io.ReadAll(rc)
+			rc.Close()
+			if !bytes.Equal(got, content) {
+				t.Errorf("Get() = %q, want %q", got, content)
+			}
+
+			if err := store.Delete(ctx, key); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Get() after Delete() error = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestLocalStoreRejectsTraversal(t *testing.T) {
+	local, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocal() error = %v", err)
+	}
+
+	if err := local.Put(context.Background(), "../escape.txt", strings.NewReader("x"), 1, "text/plain"); err == nil {
+		t.Error("Put() with a traversal key succeeded, want error")
+	}
+}
+
+func TestURLSigner(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	signer := NewURLSigner("secret", "http://localhost:8080", time.Minute)
+	signer.now = func() time.Time { return now }
+
+	signed := signer.Sign(42, "")
+	if !strings.HasPrefix(signed, "http://localhost:8080/v1/attachments/42/download?") {
+		t.Fatalf("Sign() = %q, unexpected prefix", signed)
+	}
+	if thumb := signer.Sign(42, "small"); !strings.Contains(thumb, "variant=small") {
+		t.Errorf("Sign() with a variant = %q, want variant query", thumb)
+	}
+
+	expires := "1700000060"
+	sig := signer.signature(42, "", 1_700_000_060)
+
+	if err := signer.Verify(42, "", expires, sig); err != nil {
+		t.Errorf("Verify() error = %v, want nil", err)
+	}
+	if err := signer.Verify(43, "", expires, sig); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() for another attachment error = %v, want ErrInvalidSignature", err)
+	}
+	if err := signer.Verify(42, "large", expires, sig); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Verify() for another variant error = %v, want ErrInvalidSignature", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if err := signer.Verify(42, "", expires, sig); !errors.Is(err, ErrURLExpired) {
+		t.Errorf("Verify() after expiry error = %v, want ErrURLExpired", err)
+	}
+}
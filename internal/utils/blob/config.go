@@ -0,0 +1,19 @@
+package blob
+
+import (
+	"fmt"
+
+	"github.com/LikhithMar14/gopher-chat/internal/config"
+)
+
+// New builds the blob store selected by cfg.Driver ("local" or "s3").
+func New(cfg config.MediaConfig) (Store, error) {
+	switch cfg.Driver {
+	case "", "local":
+		return NewLocal(cfg.LocalDir)
+	case "s3":
+		return NewS3(cfg.S3.Endpoint, cfg.S3.Bucket, cfg.S3.Region, cfg.S3.AccessKey, cfg.S3.SecretKey)
+	default:
+		return nil, fmt.Errorf("unknown media driver: %q", cfg.Driver)
+	}
+}
@@ -49,6 +49,74 @@ var (
 	ErrCommentTooLong         = errors.New("comment content is too long")
 )
 
+var (
+	ErrInvalidCursor = errors.New("invalid pagination cursor")
+)
+
+var (
+	ErrRepostNotFound   = errors.New("repost not found")
+	ErrAlreadyReposted  = errors.New("post already reposted")
+	ErrRepostNotAllowed = errors.New("only public posts can be reposted")
+)
+
+var (
+	ErrAttachmentNotFound   = errors.New("attachment not found")
+	ErrFileTooLarge         = errors.New("file is too large")
+	ErrUnsupportedMediaType = errors.New("unsupported file type")
+	ErrAttachmentNotReady   = errors.New("attachment is still being processed")
+)
+
+var (
+	ErrPollNotFound         = errors.New("poll not found")
+	ErrPollClosed           = errors.New("poll is closed")
+	ErrPollOptionNotFound   = errors.New("poll option not found")
+	ErrPollSingleChoice     = errors.New("poll only allows a single choice")
+	ErrInvalidPollCloseTime = errors.New("poll close time must be in the future, and is required when results are shown after close")
+)
+
+var (
+	ErrInvalidAvatar = errors.New("avatar must be an image you uploaded that is not attached to a post or comment")
+)
+
+var (
+	ErrFollowRequestNotFound = errors.New("follow request not found")
+	ErrPrivateAccount        = errors.New("this account is private")
+)
+
+var (
+	ErrUserBlocked   = errors.New("you cannot interact with this user")
+	ErrSelfRelation  = errors.New("you cannot block or mute yourself")
+	ErrBlockNotFound = errors.New("user is not blocked")
+	ErrMuteNotFound  = errors.New("user is not muted")
+)
+
+var (
+	ErrSelfFollow = errors.New("you cannot follow yourself")
+)
+
+var (
+	ErrInvalidTrendWindow = errors.New("invalid trend window")
+)
+
+var (
+	ErrInvalidActivity       = errors.New("invalid activity")
+	ErrActorMismatch         = errors.New("activity actor does not match its signature")
+	ErrRemoteAccountNotFound = errors.New("remote account not found")
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+)
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+var (
+	ErrOutboxMessageNotFound = errors.New("failed outbox message not found")
+)
+
 type AppError struct {
 	Err        error
 	StatusCode int
@@ -9,6 +9,8 @@ const (
 	FromName = "Gopher Chat"
 	maxRetries = 3
 	UserWelcomeTemplate = "user_invitation.tmpl"
+	NotificationTemplate = "notification.tmpl"
+	NotificationDigestTemplate = "notification_digest.tmpl"
 )
 
 type Client interface {
@@ -2,7 +2,10 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"github.com/go-playground/validator/v10"
 )
 
 type StandardResponse struct {
@@ -42,6 +45,12 @@ func HandleValidationError(w http.ResponseWriter, err error) error {
 	return WriteErrorResponse(w, http.StatusBadRequest, err.Error())
 }
 
+// IsValidationError reports whether err comes from validating a request.
+func IsValidationError(err error) bool {
+	var verrs validator.ValidationErrors
+	return errors.As(err, &verrs)
+}
+
 
 func HandleInternalError(w http.ResponseWriter, err error) error {
 	return WriteErrorResponse(w, http.StatusInternalServerError, "Internal server error")
@@ -5,9 +5,15 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/LikhithMar14/gopher-chat/internal/models"
 	"github.com/go-chi/chi/v5"
 )
 
+const (
+	DefaultCursorLimit = 20
+	MaxCursorLimit     = 50
+)
+
 type Envelope map[string]interface{}
 
 func WriteJSON(w http.ResponseWriter, status int, data Envelope) error {
@@ -48,3 +54,21 @@ func ReadIDParam(r *http.Request, paramName string) (int64, error) {
 func ReadStringParam(r *http.Request, paramName string) string {
 	return chi.URLParam(r, paramName)
 }
+
+// ReadCursorRequest reads the cursor and limit query parameters used by keyset
+// paginated endpoints, falling back to DefaultCursorLimit for a missing or
+// out-of-range limit.
+func ReadCursorRequest(r *http.Request) models.CursorRequest {
+	req := models.CursorRequest{
+		Cursor: r.URL.Query().Get("cursor"),
+		Limit:  DefaultCursorLimit,
+	}
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= MaxCursorLimit {
+			req.Limit = l
+		}
+	}
+
+	return req
+}
@@ -0,0 +1,223 @@
+// Package activitypub implements the parts of ActivityPub, ActivityStreams
+// 2.0, WebFinger (RFC 7033) and HTTP Signatures needed to federate accounts
+// and their posts with other servers such as Mastodon.
+package activitypub
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	// ContentType is the media type of ActivityPub documents.
+	ContentType = "application/activity+json"
+	// LDContentType is the equivalent media type some servers ask for.
+	LDContentType = `application/ld+json; profile="https://www.w3.org/ns/activitystreams"`
+
+	// Public addresses an object to everyone.
+	Public = "https://www.w3.org/ns/activitystreams#Public"
+)
+
+// Context is the JSON-LD context of the documents served, including the
+// security vocabulary of the actors' public keys.
+var Context = []string{
+	"https://www.w3.org/ns/activitystreams",
+	"https://w3id.org/security/v1",
+}
+
+// Types of actors, objects and activities.
+const (
+	TypePerson            = "Person"
+	TypeApplication       = "Application"
+	TypeService           = "Service"
+	TypeGroup             = "Group"
+	TypeOrganization      = "Organization"
+	TypeNote              = "Note"
+	TypeTombstone         = "Tombstone"
+	TypeOrderedCollection = "OrderedCollection"
+	TypeFollow            = "Follow"
+	TypeAccept            = "Accept"
+	TypeReject            = "Reject"
+	TypeUndo              = "Undo"
+	TypeCreate            = "Create"
+	TypeDelete            = "Delete"
+	TypeLike              = "Like"
+)
+
+var ErrInvalidObject = errors.New("activitypub: invalid object")
+
+// Audience is a list of addressees. Servers send a single addressee as a
+// plain string, which it accepts as well.
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*a = Audience{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// Contains reports whether id is one of the addressees.
+func (a Audience) Contains(id string) bool {
+	for _, v := range a {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Actor is an account that can send and receive activities.
+type Actor struct {
+	Context                   any        `json:"@context,omitempty"`
+	ID                        string     `json:"id"`
+	Type                      string     `json:"type"`
+	PreferredUsername         string     `json:"preferredUsername"`
+	Name                      string     `json:"name,omitempty"`
+	Summary                   string     `json:"summary,omitempty"`
+	URL                       string     `json:"url,omitempty"`
+	Inbox                     string     `json:"inbox"`
+	Outbox                    string     `json:"outbox,omitempty"`
+	Followers                 string     `json:"followers,omitempty"`
+	ManuallyApprovesFollowers bool       `json:"manuallyApprovesFollowers"`
+	Published                 *time.Time `json:"published,omitempty"`
+	Endpoints                 *Endpoints `json:"endpoints,omitempty"`
+	PublicKey                 PublicKey  `json:"publicKey"`
+}
+
+// IsActor reports whether the type is one of the actor types.
+func IsActor(typ string) bool {
+	switch typ {
+	case TypePerson, TypeApplication, TypeService, TypeGroup, TypeOrganization:
+		return true
+	}
+	return false
+}
+
+// Endpoints holds the shared inbox of the actor's server.
+type Endpoints struct {
+	SharedInbox string `json:"sharedInbox,omitempty"`
+}
+
+// PublicKey is the key that signs the actor's requests.
+type PublicKey struct {
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPem string `json:"publicKeyPem"`
+}
+
+// Note is a short post.
+type Note struct {
+	Context      any        `json:"@context,omitempty"`
+	ID           string     `json:"id"`
+	Type         string     `json:"type"`
+	AttributedTo string     `json:"attributedTo"`
+	Name         string     `json:"name,omitempty"`
+	Summary      string     `json:"summary,omitempty"`
+	Content      string     `json:"content"`
+	MediaType    string     `json:"mediaType,omitempty"`
+	URL          string     `json:"url,omitempty"`
+	InReplyTo    string     `json:"inReplyTo,omitempty"`
+	Published    time.Time  `json:"published"`
+	Updated      *time.Time `json:"updated,omitempty"`
+	To           Audience   `json:"to,omitempty"`
+	Cc           Audience   `json:"cc,omitempty"`
+	Tag          []Tag      `json:"tag,omitempty"`
+}
+
+// Tag is a hashtag or mention of a Note.
+type Tag struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	Href string `json:"href,omitempty"`
+}
+
+// Activity is an action of an actor on an object. The object is kept raw,
+// since it is either the id of an object or the object itself, depending on
+// the activity and the sending server.
+type Activity struct {
+	Context   any             `json:"@context,omitempty"`
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Actor     string          `json:"actor"`
+	Object    json.RawMessage `json:"object"`
+	To        Audience        `json:"to,omitempty"`
+	Cc        Audience        `json:"cc,omitempty"`
+	Published *time.Time      `json:"published,omitempty"`
+}
+
+// NewActivity returns an activity of actor on object, which is either the id
+// of an object or a value marshalled into the activity.
+func NewActivity(id, typ, actor string, object any) (*Activity, error) {
+	raw, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	return &Activity{
+		Context: Context,
+		ID:      id,
+		Type:    typ,
+		Actor:   actor,
+		Object:  raw,
+	}, nil
+}
+
+// ObjectID returns the id of the object of the activity, whether the object
+// is referenced or embedded.
+func (a *Activity) ObjectID() string {
+	var id string
+	if err := json.Unmarshal(a.Object, &id); err == nil {
+		return id
+	}
+	var obj struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(a.Object, &obj); err == nil {
+		return obj.ID
+	}
+	return ""
+}
+
+// ObjectType returns the type of an embedded object, or "" when the object
+// is only referenced.
+func (a *Activity) ObjectType() string {
+	var obj struct {
+		Type string `json:"type"`
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(a.Object), []byte("{")) && json.Unmarshal(a.Object, &obj) == nil {
+		return obj.Type
+	}
+	return ""
+}
+
+// DecodeObject unmarshals an embedded object into v. It returns
+// ErrInvalidObject when the object is only referenced.
+func (a *Activity) DecodeObject(v any) error {
+	if !bytes.HasPrefix(bytes.TrimSpace(a.Object), []byte("{")) {
+		return ErrInvalidObject
+	}
+	if err := json.Unmarshal(a.Object, v); err != nil {
+		return ErrInvalidObject
+	}
+	return nil
+}
+
+// OrderedCollection is a collection such as an outbox or a list of
+// followers. OrderedItems is left out for collections that only report their
+// size.
+type OrderedCollection struct {
+	Context      any    `json:"@context,omitempty"`
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	TotalItems   int64  `json:"totalItems"`
+	OrderedItems []any  `json:"orderedItems,omitempty"`
+}
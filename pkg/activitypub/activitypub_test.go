@@ -0,0 +1,83 @@
+package activitypub
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestActivityObject(t *testing.T) {
+	var referenced Activity
+	if err := json.Unmarshal([]byte(`{"type":"Like","object":"https://local.example/v1/ap/posts/1"}`), &referenced); err != nil {
+		t.Fatal(err)
+	}
+	if got := referenced.ObjectID(); got != "https://local.example/v1/ap/posts/1" {
+		t.Errorf("ObjectID() = %q", got)
+	}
+	if got := referenced.ObjectType(); got != "" {
+		t.Errorf("ObjectType() = %q, want empty for a reference", got)
+	}
+	var note Note
+	if err := referenced.DecodeObject(&note); err != ErrInvalidObject {
+		t.Errorf("DecodeObject() error = %v, want ErrInvalidObject", err)
+	}
+
+	var embedded Activity
+	if err := json.Unmarshal([]byte(`{"type":"Create","object":{"id":"https://remote.example/notes/1","type":"Note","content":"<p>Hi</p>","to":"https://www.w3.org/ns/activitystreams#Public"}}`), &embedded); err != nil {
+		t.Fatal(err)
+	}
+	if got := embedded.ObjectID(); got != "https://remote.example/notes/1" {
+		t.Errorf("ObjectID() = %q", got)
+	}
+	if got := embedded.ObjectType(); got != TypeNote {
+		t.Errorf("ObjectType() = %q, want Note", got)
+	}
+	if err := embedded.DecodeObject(&note); err != nil {
+		t.Fatalf("DecodeObject() error = %v", err)
+	}
+	if !note.To.Contains(Public) {
+		t.Errorf("To = %v, want the single addressee accepted", note.To)
+	}
+}
+
+func TestNewActivity(t *testing.T) {
+	a, err := NewActivity("https://local.example/a/1", TypeFollow, "https://local.example/u/1", "https://remote.example/u/2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, _ := json.Marshal(a)
+	var got map[string]any
+	_ = json.Unmarshal(doc, &got)
+	if got["object"] != "https://remote.example/u/2" || got["type"] != "Follow" || got["@context"] == nil {
+		t.Errorf("NewActivity() marshalled to %s", doc)
+	}
+}
+
+func TestParseAccount(t *testing.T) {
+	tests := []struct {
+		in         string
+		user, host string
+		wantErr    bool
+	}{
+		{in: "acct:alice@Example.com", user: "alice", host: "example.com"},
+		{in: "@bob@localhost:8081", user: "bob", host: "localhost:8081"},
+		{in: "carol@remote.example", user: "carol", host: "remote.example"},
+		{in: "alice", wantErr: true},
+		{in: "alice@", wantErr: true},
+		{in: "a@b@c", wantErr: true},
+		{in: "alice@example.com/path", wantErr: true},
+	}
+	for _, tt := range tests {
+		user, host, err := ParseAccount(tt.in)
+		if (err != nil) != tt.wantErr || user != tt.user || host != tt.host {
+			t.Errorf("ParseAccount(%q) = %q, %q, %v", tt.in, user, host, err)
+		}
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	got := PlainText(`<p>Hello <a href="https://example.com">@bob</a> &amp; friends<br>second line</p><p>Next</p>`)
+	want := "Hello @bob & friends\nsecond line\n\nNext"
+	if got != want {
+		t.Errorf("PlainText() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,238 @@
+package activitypub
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/pkg/linkpreview"
+)
+
+const (
+	// maxResponseBytes caps the documents read from other servers.
+	maxResponseBytes = 1 << 20
+	maxRedirects     = 3
+	userAgent        = "gopher-chat-activitypub/1.0 (+https://github.com/LikhithMar14/gopher-chat)"
+)
+
+var (
+	ErrForbiddenAddress = errors.New("activitypub: address is not publicly routable")
+	ErrUnsupportedURL   = errors.New("activitypub: only http and https URLs can be fetched")
+)
+
+// StatusError is returned for requests answered with an unsuccessful status.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("activitypub: unexpected status %d", e.StatusCode)
+}
+
+// Temporary reports whether the request may succeed when retried.
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusRequestTimeout || e.StatusCode == http.StatusTooManyRequests
+}
+
+// Client fetches documents from and delivers activities to other servers.
+// Like the link preview fetcher, it refuses to connect to non-public
+// addresses unless it is told otherwise, since the URLs come from remote
+// servers. It is safe for concurrent use.
+type Client struct {
+	client       *http.Client
+	allowPrivate bool
+	// allowAddr decides whether a resolved address may be dialled.
+	allowAddr func(netip.AddrPort) bool
+}
+
+// NewClient returns a Client giving up on requests after timeout. With
+// allowPrivate it connects to any address, which lets instances on a local
+// network or on one machine federate with each other during development.
+func NewClient(timeout time.Duration, allowPrivate bool) *Client {
+	c := &Client{allowPrivate: allowPrivate, allowAddr: linkpreview.IsPublicAddr}
+	if allowPrivate {
+		c.allowAddr = func(netip.AddrPort) bool { return true }
+	}
+
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addr, err := netip.ParseAddrPort(address)
+			if err != nil || !c.allowAddr(addr) {
+				return ErrForbiddenAddress
+			}
+			return nil
+		},
+	}
+
+	c.client = &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   timeout,
+			ResponseHeaderTimeout: timeout,
+			MaxIdleConns:          50,
+			IdleConnTimeout:       90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return errors.New("activitypub: too many redirects")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return ErrUnsupportedURL
+			}
+			return nil
+		},
+	}
+	return c
+}
+
+// FetchActor retrieves the actor with the given id. The actor must be served
+// by the host of its id and own the id of its key, so one server can't pass
+// off actors or keys of another.
+func (c *Client) FetchActor(ctx context.Context, id string) (*Actor, error) {
+	var actor Actor
+	if err := c.get(ctx, id, ContentType, &actor); err != nil {
+		return nil, err
+	}
+	if !IsActor(actor.Type) || actor.Inbox == "" || actor.PublicKey.PublicKeyPem == "" || !SameHost(actor.ID, id) ||
+		!OwnsKey(actor.ID, actor.PublicKey.ID) {
+		return nil, ErrInvalidObject
+	}
+	return &actor, nil
+}
+
+// SameHost reports whether two ids are URLs on the same host.
+func SameHost(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil || ua.Host == "" {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(ua.Host, ub.Host)
+}
+
+// OwnsKey reports whether keyID names a key of the actor actorID: the actor
+// id itself, or the actor id followed by a fragment or path, such as
+// "https://example.com/users/alice#main-key".
+func OwnsKey(actorID, keyID string) bool {
+	if !SameHost(actorID, keyID) || !strings.HasPrefix(keyID, actorID) {
+		return false
+	}
+	rest := keyID[len(actorID):]
+	return rest == "" || rest[0] == '#' || rest[0] == '/'
+}
+
+// Finger resolves an account such as "alice@example.com" to the id of its
+// actor with WebFinger.
+func (c *Client) Finger(ctx context.Context, account string) (string, error) {
+	user, host, err := ParseAccount(account)
+	if err != nil {
+		return "", err
+	}
+
+	u := url.URL{
+		Scheme:   "https",
+		Host:     host,
+		Path:     "/.well-known/webfinger",
+		RawQuery: url.Values{"resource": {"acct:" + user + "@" + host}}.Encode(),
+	}
+	var jrd JRD
+	err = c.get(ctx, u.String(), WebFingerContentType, &jrd)
+	if err != nil && c.allowPrivate {
+		// Instances on private networks are usually served without TLS.
+		u.Scheme = "http"
+		err = c.get(ctx, u.String(), WebFingerContentType, &jrd)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	id := jrd.SelfLink()
+	if id == "" {
+		return "", ErrInvalidAccount
+	}
+	return id, nil
+}
+
+// Deliver posts an activity to an inbox, signed with the key of the sending
+// actor.
+func (c *Client) Deliver(ctx context.Context, inbox string, activity []byte, keyID string, key *rsa.PrivateKey) error {
+	req, err := newRequest(ctx, http.MethodPost, inbox, bytes.NewReader(activity))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", ContentType)
+	if err := Sign(req, keyID, key, activity); err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return unwrapDialError(err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+func (c *Client) get(ctx context.Context, rawURL, accept string, v any) error {
+	req, err := newRequest(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", accept)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return unwrapDialError(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(v); err != nil {
+		return ErrInvalidObject
+	}
+	return nil
+}
+
+func newRequest(ctx context.Context, method, rawURL string, body io.Reader) (*http.Request, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return nil, ErrUnsupportedURL
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	return req, nil
+}
+
+func unwrapDialError(err error) error {
+	if errors.Is(err, ErrForbiddenAddress) {
+		return ErrForbiddenAddress
+	}
+	return err
+}
@@ -0,0 +1,164 @@
+package activitypub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestClientAgainstInstance delivers a signed activity from one instance to
+// the inbox of another, which resolves the sender with WebFinger and verifies
+// the signature against the key on its actor.
+func TestClientAgainstInstance(t *testing.T) {
+	priv, pub := mustKeys(t)
+	key, _ := ParsePrivateKey(priv)
+
+	var sender *httptest.Server
+	sender = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/webfinger":
+			if r.URL.Query().Get("resource") != "acct:alice@"+r.Host {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", WebFingerContentType)
+			_ = json.NewEncoder(w).Encode(JRD{
+				Subject: r.URL.Query().Get("resource"),
+				Links:   []JRDLink{{Rel: "self", Type: ContentType, Href: sender.URL + "/users/alice"}},
+			})
+		case "/users/alice":
+			w.Header().Set("Content-Type", ContentType)
+			_ = json.NewEncoder(w).Encode(Actor{
+				Context:           Context,
+				ID:                sender.URL + "/users/alice",
+				Type:              TypePerson,
+				PreferredUsername: "alice",
+				Inbox:             sender.URL + "/users/alice/inbox",
+				PublicKey:         PublicKey{ID: sender.URL + "/users/alice#main-key", Owner: sender.URL + "/users/alice", PublicKeyPem: pub},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer sender.Close()
+
+	client := NewClient(time.Second, true)
+	received := make(chan string, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		keyID, err := KeyID(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		actor, err := client.FetchActor(r.Context(), sender.URL+"/users/alice")
+		if err != nil || actor.PublicKey.ID != keyID {
+			http.Error(w, "unknown key", http.StatusUnauthorized)
+			return
+		}
+		pubKey, _ := ParsePublicKey(actor.PublicKey.PublicKeyPem)
+		if err := Verify(r, body, pubKey); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		var a Activity
+		_ = json.Unmarshal(body, &a)
+		received <- a.Type
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer receiver.Close()
+
+	ctx := context.Background()
+	actorID, err := client.Finger(ctx, "alice@"+sender.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Finger() error = %v", err)
+	}
+	if actorID != sender.URL+"/users/alice" {
+		t.Errorf("Finger() = %q", actorID)
+	}
+
+	a, _ := NewActivity(sender.URL+"/follows/1", TypeFollow, actorID, receiver.URL+"/users/bob")
+	doc, _ := json.Marshal(a)
+	if err := client.Deliver(ctx, receiver.URL+"/inbox", doc, sender.URL+"/users/alice#main-key", key); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+	if got := <-received; got != TypeFollow {
+		t.Errorf("received %q, want Follow", got)
+	}
+
+	err = client.Deliver(ctx, receiver.URL+"/inbox", doc, sender.URL+"/users/alice#other-key", key)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusUnauthorized || statusErr.Temporary() {
+		t.Errorf("Deliver() with unknown key error = %v, want permanent 401", err)
+	}
+}
+
+func TestClientRejectsPrivateAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached the server")
+	}))
+	defer srv.Close()
+
+	_, err := NewClient(time.Second, false).FetchActor(context.Background(), srv.URL+"/users/alice")
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("FetchActor() error = %v, want ErrForbiddenAddress", err)
+	}
+}
+
+func TestFetchActorRequiresSameHost(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(Actor{
+			ID:        "https://elsewhere.example/users/alice",
+			Type:      TypePerson,
+			Inbox:     "https://elsewhere.example/inbox",
+			PublicKey: PublicKey{PublicKeyPem: "key"},
+		})
+	}))
+	defer srv.Close()
+
+	if _, err := NewClient(time.Second, true).FetchActor(context.Background(), srv.URL+"/users/alice"); !errors.Is(err, ErrInvalidObject) {
+		t.Errorf("FetchActor() error = %v, want ErrInvalidObject", err)
+	}
+}
+
+func TestOwnsKey(t *testing.T) {
+	tests := []struct {
+		keyID string
+		want  bool
+	}{
+		{"https://example.com/users/alice#main-key", true},
+		{"https://example.com/users/alice/main-key", true},
+		{"https://example.com/users/alice", true},
+		{"https://example.com/users/alicia#main-key", false},
+		{"https://example.com/users/bob#main-key", false},
+		{"https://elsewhere.example/users/alice#main-key", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := OwnsKey("https://example.com/users/alice", tt.keyID); got != tt.want {
+			t.Errorf("OwnsKey(%q) = %v, want %v", tt.keyID, got, tt.want)
+		}
+	}
+}
+
+func TestFetchActorRequiresOwnKey(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(Actor{
+			ID:        srv.URL + "/users/mallory",
+			Type:      TypePerson,
+			Inbox:     srv.URL + "/inbox",
+			PublicKey: PublicKey{ID: srv.URL + "/users/alice#main-key", PublicKeyPem: "key"},
+		})
+	}))
+	defer srv.Close()
+
+	if _, err := NewClient(time.Second, true).FetchActor(context.Background(), srv.URL+"/users/mallory"); !errors.Is(err, ErrInvalidObject) {
+		t.Errorf("FetchActor() error = %v, want ErrInvalidObject", err)
+	}
+}
@@ -0,0 +1,30 @@
+package activitypub
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// PlainText converts the HTML content of a remote object into plain text,
+// keeping line and paragraph breaks, so it can be stored like local content.
+func PlainText(content string) string {
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(content))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return strings.TrimSpace(b.String())
+		case html.TextToken:
+			b.Write(z.Text())
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if name, _ := z.TagName(); string(name) == "br" {
+				b.WriteString("\n")
+			}
+		case html.EndTagToken:
+			if name, _ := z.TagName(); string(name) == "p" {
+				b.WriteString("\n\n")
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package activitypub
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func mustKeys(t *testing.T) (privatePEM, publicPEM string) {
+	t.Helper()
+	priv, pub, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	return priv, pub
+}
+
+// signedRequest signs a delivery of body and returns the request as a server
+// receives it.
+func signedRequest(t *testing.T, privatePEM string, body []byte) *http.Request {
+	t.Helper()
+	key, err := ParsePrivateKey(privatePEM)
+	if err != nil {
+		t.Fatalf("ParsePrivateKey() error = %v", err)
+	}
+
+	out, _ := http.NewRequest(http.MethodPost, "https://remote.example/v1/ap/inbox?x=1", bytes.NewReader(body))
+	if err := Sign(out, "https://local.example/v1/ap/users/1#main-key", key, body); err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	in := httptest.NewRequest(http.MethodPost, "https://remote.example/v1/ap/inbox?x=1", bytes.NewReader(body))
+	in.Header = out.Header.Clone()
+	return in
+}
+
+func TestSignVerify(t *testing.T) {
+	priv, pub := mustKeys(t)
+	key, err := ParsePublicKey(pub)
+	if err != nil {
+		t.Fatalf("ParsePublicKey() error = %v", err)
+	}
+	body := []byte(`{"type":"Follow"}`)
+
+	req := signedRequest(t, priv, body)
+	if id, err := KeyID(req); err != nil || id != "https://local.example/v1/ap/users/1#main-key" {
+		t.Errorf("KeyID() = %q, %v", id, err)
+	}
+	if err := Verify(req, body, key); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+
+	t.Run("tampered body", func(t *testing.T) {
+		if err := Verify(signedRequest(t, priv, body), []byte(`{"type":"Like"}`), key); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("Verify() error = %v, want ErrInvalidSignature", err)
+		}
+	})
+
+	t.Run("other target", func(t *testing.T) {
+		req := signedRequest(t, priv, body)
+		req.URL.Path = "/v1/ap/users/2/inbox"
+		if err := Verify(req, body, key); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("Verify() error = %v, want ErrInvalidSignature", err)
+		}
+	})
+
+	t.Run("other key", func(t *testing.T) {
+		_, otherPub := mustKeys(t)
+		other, _ := ParsePublicKey(otherPub)
+		if err := Verify(signedRequest(t, priv, body), body, other); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("Verify() error = %v, want ErrInvalidSignature", err)
+		}
+	})
+
+	t.Run("stale date", func(t *testing.T) {
+		req := signedRequest(t, priv, body)
+		req.Header.Set("Date", time.Now().Add(-2*MaxClockSkew).UTC().Format(http.TimeFormat))
+		if err := Verify(req, body, key); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("Verify() error = %v, want ErrInvalidSignature", err)
+		}
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/ap/inbox", bytes.NewReader(body))
+		if err := Verify(req, body, key); !errors.Is(err, ErrMissingSignature) {
+			t.Errorf("Verify() error = %v, want ErrMissingSignature", err)
+		}
+	})
+}
+
+func TestParseKeys(t *testing.T) {
+	if _, err := ParsePrivateKey("not a key"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("ParsePrivateKey() error = %v, want ErrInvalidKey", err)
+	}
+	if _, err := ParsePublicKey("-----BEGIN PUBLIC KEY-----\nAAAA\n-----END PUBLIC KEY-----\n"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("ParsePublicKey() error = %v, want ErrInvalidKey", err)
+	}
+}
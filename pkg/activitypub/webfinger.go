@@ -0,0 +1,46 @@
+package activitypub
+
+import (
+	"errors"
+	"strings"
+)
+
+// WebFingerContentType is the media type of WebFinger responses.
+const WebFingerContentType = "application/jrd+json"
+
+var ErrInvalidAccount = errors.New("activitypub: invalid account")
+
+// JRD is a WebFinger resource descriptor.
+type JRD struct {
+	Subject string    `json:"subject"`
+	Aliases []string  `json:"aliases,omitempty"`
+	Links   []JRDLink `json:"links"`
+}
+
+// JRDLink is a link of a resource descriptor.
+type JRDLink struct {
+	Rel  string `json:"rel"`
+	Type string `json:"type,omitempty"`
+	Href string `json:"href,omitempty"`
+}
+
+// SelfLink returns the link to the ActivityPub actor of the resource.
+func (j *JRD) SelfLink() string {
+	for _, l := range j.Links {
+		if l.Rel == "self" && (l.Type == ContentType || strings.HasPrefix(l.Type, "application/ld+json")) {
+			return l.Href
+		}
+	}
+	return ""
+}
+
+// ParseAccount splits an account such as "acct:alice@example.com",
+// "alice@example.com" or "@alice@example.com" into its user and host.
+func ParseAccount(account string) (user, host string, err error) {
+	account = strings.TrimPrefix(strings.TrimPrefix(account, "acct:"), "@")
+	user, host, ok := strings.Cut(account, "@")
+	if !ok || user == "" || host == "" || strings.ContainsAny(user+host, "@/?# ") {
+		return "", "", ErrInvalidAccount
+	}
+	return user, strings.ToLower(host), nil
+}
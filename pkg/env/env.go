@@ -65,3 +65,46 @@ func GetDuration(key string, fallback time.Duration) time.Duration {
 
 	return duration
 }
+
+// GetFloat retrieves a float64 value from the environment.
+// If the environment variable is not set or cannot be parsed as a float,
+// it returns the fallback value.
+//
+// Example:
+//
+//	weight := env.GetFloat("SCORE_WEIGHT", 1.5)
+func GetFloat(key string, fallback float64) float64 {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+
+	num, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return fallback
+	}
+
+	return num
+}
+
+// GetBool retrieves a boolean value from the environment, accepting the
+// values understood by strconv.ParseBool.
+// If the environment variable is not set or cannot be parsed as a boolean,
+// it returns the fallback value.
+//
+// Example:
+//
+//	debug := env.GetBool("DEBUG", false)
+func GetBool(key string, fallback bool) bool {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return fallback
+	}
+
+	return b
+}
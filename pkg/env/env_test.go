@@ -133,3 +133,107 @@ func TestGetDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestGetFloat(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		value    string
+		fallback float64
+		want     float64
+	}{
+		{
+			name:     "valid float",
+			key:      "TEST_FLOAT",
+			value:    "0.25",
+			fallback: 1,
+			want:     0.25,
+		},
+		{
+			name:     "integer value",
+			key:      "TEST_FLOAT_INT",
+			value:    "3",
+			fallback: 1,
+			want:     3,
+		},
+		{
+			name:     "invalid float",
+			key:      "TEST_INVALID_FLOAT",
+			value:    "not-a-number",
+			fallback: 1,
+			want:     1,
+		},
+		{
+			name:     "non-existing env var",
+			key:      "NON_EXISTING",
+			value:    "",
+			fallback: 1,
+			want:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.value != "" {
+				os.Setenv(tt.key, tt.value)
+				defer os.Unsetenv(tt.key)
+			}
+
+			if got := GetFloat(tt.key, tt.fallback); got != tt.want {
+				t.Errorf("GetFloat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetBool(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		value    string
+		fallback bool
+		want     bool
+	}{
+		{
+			name:     "true",
+			key:      "TEST_BOOL",
+			value:    "true",
+			fallback: false,
+			want:     true,
+		},
+		{
+			name:     "numeric false",
+			key:      "TEST_BOOL_NUM",
+			value:    "0",
+			fallback: true,
+			want:     false,
+		},
+		{
+			name:     "invalid bool",
+			key:      "TEST_INVALID_BOOL",
+			value:    "yes please",
+			fallback: true,
+			want:     true,
+		},
+		{
+			name:     "non-existing env var",
+			key:      "NON_EXISTING",
+			value:    "",
+			fallback: true,
+			want:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.value != "" {
+				os.Setenv(tt.key, tt.value)
+				defer os.Unsetenv(tt.key)
+			}
+
+			if got := GetBool(tt.key, tt.fallback); got != tt.want {
+				t.Errorf("GetBool() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package imaging
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+	"image/gif"
+)
+
+// MaxGIFFrames bounds the number of frames of an animated GIF. The frames
+// together must also stay within MaxPixels.
+const MaxGIFFrames = 500
+
+var (
+	ErrTooManyFrames = errors.New("animation has too many frames")
+	errGIFTruncated  = errors.New("gif: truncated or malformed")
+)
+
+// countGIFFrames counts the frames of a GIF by walking its blocks, without
+// decoding any pixels.
+func countGIFFrames(data []byte) (int, error) {
+	// Header and logical screen descriptor.
+	const headerLen = 13
+	if len(data) < headerLen {
+		return 0, errGIFTruncated
+	}
+	i := headerLen
+	if packed := data[10]; packed&0x80 != 0 {
+		i += 3 << ((packed & 0x07) + 1)
+	}
+
+	frames := 0
+	for i < len(data) {
+		switch data[i] {
+		case 0x21: // Extension: introducer, label and data sub-blocks.
+			i += 2
+		case 0x2C: // Image descriptor, local color table, LZW code size.
+			if i+10 > len(data) {
+				return 0, errGIFTruncated
+			}
+			frames++
+			if frames > MaxGIFFrames {
+				return frames, nil
+			}
+			packed := data[i+9]
+			i += 10
+			if packed&0x80 != 0 {
+				i += 3 << ((packed & 0x07) + 1)
+			}
+			i++
+		case 0x3B: // Trailer.
+			return frames, nil
+		default:
+			return 0, errGIFTruncated
+		}
+
+		// Data sub-blocks, ended by an empty one.
+		for {
+			if i >= len(data) {
+				return 0, errGIFTruncated
+			}
+			n := int(data[i])
+			i += n + 1
+			if n == 0 {
+				break
+			}
+		}
+	}
+	return 0, errGIFTruncated
+}
+
+// firstGIFFrame renders the first frame of g on the full canvas, at its
+// offset.
+func firstGIFFrame(g *gif.GIF) image.Image {
+	canvas := image.NewNRGBA(image.Rect(0, 0, g.Config.Width, g.Config.Height))
+	frame := g.Image[0]
+	draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+	return canvas
+}
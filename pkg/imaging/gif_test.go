@@ -0,0 +1,69 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"testing"
+)
+
+func encodeGIF(t *testing.T, w, h, frames int, first image.Rectangle) []byte {
+	t.Helper()
+
+	g := &gif.GIF{Config: image.Config{Width: w, Height: h, ColorModel: color.Palette(palette.Plan9)}}
+	for i := 0; i < frames; i++ {
+		bounds := image.Rect(0, 0, w, h)
+		if i == 0 {
+			bounds = first
+		}
+		frame := image.NewPaletted(bounds, palette.Plan9)
+		for j := range frame.Pix {
+			frame.Pix[j] = uint8(i + 1)
+		}
+		g.Image = append(g.Image, frame)
+		g.Delay = append(g.Delay, 10)
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestCountGIFFrames(t *testing.T) {
+	data := encodeGIF(t, 20, 10, 3, image.Rect(0, 0, 20, 10))
+	n, err := countGIFFrames(data)
+	if err != nil || n != 3 {
+		t.Errorf("countGIFFrames() = %d, %v, want 3, nil", n, err)
+	}
+
+	if _, err := countGIFFrames(data[:len(data)-5]); err == nil {
+		t.Error("countGIFFrames(truncated) error = nil, want an error")
+	}
+}
+
+func TestProcessGIF(t *testing.T) {
+	// The first frame only covers the bottom right of the canvas.
+	data := encodeGIF(t, 400, 200, 2, image.Rect(200, 100, 400, 200))
+	res, err := Process(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if res.Original.ContentType != "image/gif" || res.Original.Width != 400 || res.Original.Height != 200 {
+		t.Errorf("Original = %s %dx%d, want image/gif 400x200", res.Original.ContentType, res.Original.Width, res.Original.Height)
+	}
+	if len(res.Variants) != 1 || res.Variants[0].Width != 160 || res.Variants[0].Height != 80 {
+		t.Fatalf("Variants = %+v, want a single 160x80 variant", res.Variants)
+	}
+}
+
+func TestProcessGIFRejectsLongAnimations(t *testing.T) {
+	data := encodeGIF(t, 2, 2, MaxGIFFrames+1, image.Rect(0, 0, 2, 2))
+	if _, err := Process(bytes.NewReader(data)); !errors.Is(err, ErrTooManyFrames) {
+		t.Errorf("Process() error = %v, want ErrTooManyFrames", err)
+	}
+}
@@ -0,0 +1,184 @@
+// Package imaging re-encodes uploaded images and produces resized variants.
+//
+// Re-encoding from decoded pixels drops every piece of metadata carried by
+// the original file (EXIF, including GPS coordinates, XMP, ICC comments and
+// so on). The EXIF orientation of JPEGs is applied to the pixels first so
+// photos keep displaying the right way up.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// MaxPixels bounds the decoded size of an image so that small, highly
+// compressed files can't exhaust memory when decoded.
+const MaxPixels = 40_000_000
+
+// JPEGQuality is the quality used when encoding JPEGs.
+const JPEGQuality = 85
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrTooManyPixels     = errors.New("image dimensions are too large")
+)
+
+// Size is a named bounding box for a resized variant.
+type Size struct {
+	Name string
+	Max  int
+}
+
+// Sizes are the variants generated for every image. A variant is skipped when
+// the image already fits within its bounding box.
+var Sizes = []Size{
+	{Name: "small", Max: 160},
+	{Name: "medium", Max: 640},
+	{Name: "large", Max: 1280},
+}
+
+// Image is an encoded image.
+type Image struct {
+	Name        string
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// Result is the output of Process: the metadata-free original and its
+// resized variants, smallest first.
+type Result struct {
+	Original Image
+	Variants []Image
+}
+
+// IsImage reports whether contentType is one of the formats Process accepts.
+func IsImage(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+		return true
+	}
+	return false
+}
+
+// Process decodes the image read from r, strips its metadata by re-encoding
+// it and generates the variants listed in Sizes. JPEGs stay JPEGs, animated
+// GIFs keep their frames and everything else is encoded as PNG.
+func Process(r io.Reader) (*Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > MaxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	var res Result
+	var src image.Image
+
+	switch format {
+	case "gif":
+		frames, err := countGIFFrames(data)
+		if err != nil {
+			return nil, fmt.Errorf("decoding gif: %w", err)
+		}
+		if frames > MaxGIFFrames {
+			return nil, ErrTooManyFrames
+		}
+		if int64(frames)*int64(cfg.Width)*int64(cfg.Height) > MaxPixels {
+			return nil, ErrTooManyPixels
+		}
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("decoding gif: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := gif.EncodeAll(&buf, g); err != nil {
+			return nil, err
+		}
+		res.Original = Image{Name: "original", Data: buf.Bytes(), ContentType: "image/gif", Width: cfg.Width, Height: cfg.Height}
+		src = firstGIFFrame(g)
+	case "jpeg", "png", "webp":
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", format, err)
+		}
+		if format == "jpeg" {
+			img = orient(img, jpegOrientation(data))
+		}
+		src = img
+		res.Original, err = encode("original", img, format)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	for _, size := range Sizes {
+		b := src.Bounds()
+		if b.Dx() <= size.Max && b.Dy() <= size.Max {
+			continue
+		}
+		variant, err := encode(size.Name, resize(src, size.Max), format)
+		if err != nil {
+			return nil, err
+		}
+		res.Variants = append(res.Variants, variant)
+	}
+
+	return &res, nil
+}
+
+func encode(name string, img image.Image, format string) (Image, error) {
+	var buf bytes.Buffer
+	out := Image{Name: name, Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}
+
+	if format == "jpeg" {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: JPEGQuality}); err != nil {
+			return Image{}, err
+		}
+		out.ContentType = "image/jpeg"
+	} else {
+		if err := png.Encode(&buf, img); err != nil {
+			return Image{}, err
+		}
+		out.ContentType = "image/png"
+	}
+
+	out.Data = buf.Bytes()
+	return out, nil
+}
+
+// resize scales img down so that it fits within a bound×bound box, keeping
+// its aspect ratio.
+func resize(img image.Image, bound int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w >= h {
+		h = max(1, h*bound/w)
+		w = bound
+	} else {
+		w = max(1, w*bound/h)
+		h = bound
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, b, xdraw.Src, nil)
+	return dst
+}
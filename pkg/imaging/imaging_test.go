@@ -0,0 +1,148 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// withExif inserts an APP1 segment carrying an orientation tag and some GPS
+// looking bytes right after the SOI marker of a JPEG.
+func withExif(t *testing.T, data []byte, orientation uint16) []byte {
+	t.Helper()
+
+	var tiff bytes.Buffer
+	tiff.WriteString("MM\x00*")
+	binary.Write(&tiff, binary.BigEndian, uint32(8))
+	binary.Write(&tiff, binary.BigEndian, uint16(1))
+	binary.Write(&tiff, binary.BigEndian, uint16(0x0112))
+	binary.Write(&tiff, binary.BigEndian, uint16(3))
+	binary.Write(&tiff, binary.BigEndian, uint32(1))
+	binary.Write(&tiff, binary.BigEndian, orientation)
+	binary.Write(&tiff, binary.BigEndian, uint16(0))
+	binary.Write(&tiff, binary.BigEndian, uint32(0))
+	tiff.WriteString("GPSLatitude=52.3676")
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+
+	var out bytes.Buffer
+	out.Write(data[:2])
+	out.Write([]byte{0xFF, 0xE1})
+	binary.Write(&out, binary.BigEndian, uint16(len(payload)+2))
+	out.Write(payload)
+	out.Write(data[2:])
+	return out.Bytes()
+}
+
+func testImage(w, h int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+	return img
+}
+
+func TestProcessJPEGStripsExifAndAppliesOrientation(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testImage(800, 400), nil); err != nil {
+		t.Fatal(err)
+	}
+	data := withExif(t, buf.Bytes(), 6)
+	if got := jpegOrientation(data); got != 6 {
+		t.Fatalf("jpegOrientation() = %d, want 6", got)
+	}
+
+	res, err := Process(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	if bytes.Contains(res.Original.Data, []byte("Exif")) || bytes.Contains(res.Original.Data, []byte("GPSLatitude")) {
+		t.Error("Process() kept EXIF metadata")
+	}
+	if res.Original.ContentType != "image/jpeg" {
+		t.Errorf("Original.ContentType = %q, want image/jpeg", res.Original.ContentType)
+	}
+	if res.Original.Width != 400 || res.Original.Height != 800 {
+		t.Errorf("Original is %dx%d, want 400x800", res.Original.Width, res.Original.Height)
+	}
+
+	// 800px tall: small and medium are generated, large is not needed.
+	if len(res.Variants) != 2 {
+		t.Fatalf("got %d variants, want 2", len(res.Variants))
+	}
+	small := res.Variants[0]
+	if small.Name != "small" || small.Height != 160 || small.Width != 80 {
+		t.Errorf("small variant = %s %dx%d, want small 80x160", small.Name, small.Width, small.Height)
+	}
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(small.Data))
+	if err != nil || cfg.Width != 80 || cfg.Height != 160 {
+		t.Errorf("small variant decodes to %dx%d (err %v), want 80x160", cfg.Width, cfg.Height, err)
+	}
+}
+
+func TestProcessPNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage(100, 50)); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Process(&buf)
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if res.Original.ContentType != "image/png" || res.Original.Width != 100 || res.Original.Height != 50 {
+		t.Errorf("Original = %s %dx%d, want image/png 100x50", res.Original.ContentType, res.Original.Width, res.Original.Height)
+	}
+	if len(res.Variants) != 0 {
+		t.Errorf("got %d variants for an image smaller than every size, want 0", len(res.Variants))
+	}
+}
+
+func TestProcessRejectsInvalidInput(t *testing.T) {
+	if _, err := Process(bytes.NewReader([]byte("not an image"))); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("Process(text) error = %v, want ErrUnsupportedFormat", err)
+	}
+
+	// A PNG header claiming 100000x100000 pixels.
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage(1, 1)); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	binary.BigEndian.PutUint32(data[16:], 100000)
+	binary.BigEndian.PutUint32(data[20:], 100000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+	if _, err := Process(bytes.NewReader(data)); !errors.Is(err, ErrTooManyPixels) {
+		t.Errorf("Process(huge) error = %v, want ErrTooManyPixels", err)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(0, 0, color.NRGBA{255, 0, 0, 255})
+	src.Set(1, 0, color.NRGBA{0, 0, 255, 255})
+
+	// Orientation 6: the top-left pixel ends up top-right after rotating
+	// clockwise.
+	got := orient(src, 6)
+	if b := got.Bounds(); b.Dx() != 1 || b.Dy() != 2 {
+		t.Fatalf("orient(6) bounds = %v, want 1x2", b)
+	}
+	if r, _, _, _ := got.At(0, 0).RGBA(); r == 0 {
+		t.Errorf("orient(6) pixel (0,0) is not red")
+	}
+
+	got = orient(src, 8)
+	if _, _, b, _ := got.At(0, 0).RGBA(); b == 0 {
+		t.Errorf("orient(8) pixel (0,0) is not blue")
+	}
+}
@@ -0,0 +1,302 @@
+// Package linkpreview fetches web pages and extracts the OpenGraph and
+// Twitter card metadata used to render link previews.
+//
+// Fetching arbitrary user-supplied URLs from inside our network is a classic
+// SSRF vector, so the Fetcher refuses to connect to loopback, private,
+// link-local and other non-public addresses. The check runs on the address
+// actually dialled, after DNS resolution and on every redirect, so it can't
+// be bypassed with a hostname that resolves to an internal address.
+package linkpreview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	DefaultTimeout  = 5 * time.Second
+	DefaultMaxBytes = 1 << 20
+
+	maxRedirects     = 5
+	maxTitleLen      = 300
+	maxDescLen       = 1000
+	maxImageURLLen   = 2048
+	maxSiteNameLen   = 100
+	userAgent        = "gopher-chat-linkpreview/1.0 (+https://github.com/LikhithMar14/gopher-chat)"
+	acceptHTMLHeader = "text/html,application/xhtml+xml;q=0.9"
+)
+
+var (
+	ErrForbiddenAddress = errors.New("address is not publicly routable")
+	ErrUnsupportedURL   = errors.New("only http and https URLs can be previewed")
+	ErrNotHTML          = errors.New("response is not an HTML page")
+	ErrNoMetadata       = errors.New("page has no preview metadata")
+)
+
+// Preview is the metadata extracted from a page.
+type Preview struct {
+	// URL is the final URL of the page after redirects.
+	URL         string
+	Title       string
+	Description string
+	ImageURL    string
+	SiteName    string
+}
+
+// Fetcher downloads pages and extracts their previews. It is safe for
+// concurrent use.
+type Fetcher struct {
+	client   *http.Client
+	maxBytes int64
+	// allowAddr decides whether a resolved address may be dialled.
+	allowAddr func(netip.AddrPort) bool
+}
+
+// NewFetcher returns a Fetcher that gives up on a page after timeout and
+// reads at most maxBytes of it.
+func NewFetcher(timeout time.Duration, maxBytes int64) *Fetcher {
+	f := &Fetcher{
+		maxBytes:  maxBytes,
+		allowAddr: IsPublicAddr,
+	}
+
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addr, err := netip.ParseAddrPort(address)
+			if err != nil || !f.allowAddr(addr) {
+				return ErrForbiddenAddress
+			}
+			return nil
+		},
+	}
+
+	f.client = &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// An environment proxy would do the dialling for us and bypass
+			// the address check.
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   timeout,
+			ResponseHeaderTimeout: timeout,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       30 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return errors.New("too many redirects")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return ErrUnsupportedURL
+			}
+			return nil
+		},
+	}
+
+	return f
+}
+
+// IsPublicAddr reports whether addr is a publicly routable unicast address on
+// a standard web port.
+func IsPublicAddr(addr netip.AddrPort) bool {
+	if addr.Port() != 80 && addr.Port() != 443 {
+		return false
+	}
+
+	ip := addr.Addr().Unmap()
+	if !ip.IsValid() || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// reservedPrefixes are the special-purpose ranges not covered by the netip
+// predicates.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64, may embed private IPv4
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+}
+
+// Fetch downloads rawURL and extracts its preview.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*Preview, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return nil, ErrUnsupportedURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", acceptHTMLHeader)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrForbiddenAddress) {
+			return nil, ErrForbiddenAddress
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, ErrNotHTML
+	}
+
+	preview := Parse(io.LimitReader(resp.Body, f.maxBytes), resp.Request.URL)
+	if preview.Title == "" && preview.Description == "" && preview.ImageURL == "" {
+		return nil, ErrNoMetadata
+	}
+	return preview, nil
+}
+
+// Parse extracts a preview from the HTML read from r. base is the URL of the
+// page, used to resolve relative image URLs. OpenGraph properties take
+// precedence over Twitter card ones, which take precedence over the page
+// title and meta description.
+func Parse(r io.Reader, base *url.URL) *Preview {
+	meta := make(map[string]string)
+	var title string
+	var inTitle bool
+
+	z := html.NewTokenizer(r)
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			switch string(name) {
+			case "meta":
+				if hasAttr {
+					readMeta(z, meta)
+				}
+			case "title":
+				inTitle = title == ""
+			case "body":
+				// Metadata lives in the head, don't bother with the rest.
+				return buildPreview(meta, title, base)
+			}
+		case html.TextToken:
+			if inTitle {
+				title = string(z.Text())
+				inTitle = false
+			}
+		case html.EndTagToken:
+			if name, _ := z.TagName(); string(name) == "head" {
+				return buildPreview(meta, title, base)
+			}
+		}
+	}
+
+	return buildPreview(meta, title, base)
+}
+
+// readMeta records the content of a <meta property|name=... content=...> tag.
+// The first occurrence of a key wins.
+func readMeta(z *html.Tokenizer, meta map[string]string) {
+	var key, content string
+	for {
+		k, v, more := z.TagAttr()
+		switch string(k) {
+		case "property", "name":
+			if key == "" {
+				key = strings.ToLower(strings.TrimSpace(string(v)))
+			}
+		case "content":
+			content = string(v)
+		}
+		if !more {
+			break
+		}
+	}
+	if key == "" || content == "" {
+		return
+	}
+	if _, ok := meta[key]; !ok {
+		meta[key] = content
+	}
+}
+
+func buildPreview(meta map[string]string, title string, base *url.URL) *Preview {
+	first := func(keys ...string) string {
+		for _, k := range keys {
+			if v := strings.TrimSpace(meta[k]); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	p := &Preview{
+		Title:       clean(first("og:title", "twitter:title"), maxTitleLen),
+		Description: clean(first("og:description", "twitter:description", "description"), maxDescLen),
+		SiteName:    clean(first("og:site_name"), maxSiteNameLen),
+	}
+	if base != nil {
+		p.URL = base.String()
+	}
+	if p.Title == "" {
+		p.Title = clean(title, maxTitleLen)
+	}
+
+	if image := first("og:image:secure_url", "og:image", "og:image:url", "twitter:image", "twitter:image:src"); image != "" {
+		if u, err := url.Parse(image); err == nil {
+			if base != nil {
+				u = base.ResolveReference(u)
+			}
+			if (u.Scheme == "http" || u.Scheme == "https") && len(u.String()) <= maxImageURLLen {
+				p.ImageURL = u.String()
+			}
+		}
+	}
+
+	return p
+}
+
+// clean collapses whitespace, drops invalid UTF-8 and truncates s to at most
+// limit runes.
+func clean(s string, limit int) string {
+	s = strings.Join(strings.Fields(strings.ToValidUTF8(s, "")), " ")
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:limit-1])) + "…"
+}
@@ -0,0 +1,154 @@
+package linkpreview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+const page = `<!doctype html>
+<html><head>
+<title>Fallback title</title>
+<meta property="og:title" content="Gophers &amp; friends">
+<meta name="twitter:title" content="Twitter title">
+<meta name="twitter:description" content="  A   page
+ about gophers ">
+<meta property="og:image" content="/img/gopher.png">
+<meta property="og:site_name" content="Example">
+</head><body><meta property="og:title" content="ignored"></body></html>`
+
+// newTestFetcher returns a fetcher allowed to reach the httptest server on
+// loopback.
+func newTestFetcher(timeout time.Duration, maxBytes int64) *Fetcher {
+	f := NewFetcher(timeout, maxBytes)
+	f.allowAddr = func(netip.AddrPort) bool { return true }
+	return f
+}
+
+func TestFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/article", http.StatusMovedPermanently)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, page)
+	}))
+	defer srv.Close()
+
+	p, err := newTestFetcher(time.Second, DefaultMaxBytes).Fetch(context.Background(), srv.URL+"/old")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	want := Preview{
+		URL:         srv.URL + "/article",
+		Title:       "Gophers & friends",
+		Description: "A page about gophers",
+		ImageURL:    srv.URL + "/img/gopher.png",
+		SiteName:    "Example",
+	}
+	if *p != want {
+		t.Errorf("Fetch() = %+v, want %+v", *p, want)
+	}
+}
+
+func TestFetchRejectsPrivateAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, page)
+	}))
+	defer srv.Close()
+
+	// The default fetcher must not reach a server on loopback, whether it is
+	// addressed directly or through a name resolving to it.
+	f := NewFetcher(time.Second, DefaultMaxBytes)
+	for _, u := range []string{srv.URL, strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)} {
+		if _, err := f.Fetch(context.Background(), u); !errors.Is(err, ErrForbiddenAddress) {
+			t.Errorf("Fetch(%s) error = %v, want ErrForbiddenAddress", u, err)
+		}
+	}
+
+	// A public page redirecting to an internal one is caught on the redirect.
+	internal := srv.Listener.Addr().String()
+	redirector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://"+internal+"/", http.StatusFound)
+	}))
+	defer redirector.Close()
+
+	f = NewFetcher(time.Second, DefaultMaxBytes)
+	f.allowAddr = func(addr netip.AddrPort) bool { return addr.String() != internal }
+	if _, err := f.Fetch(context.Background(), redirector.URL); !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("Fetch() through a redirect error = %v, want ErrForbiddenAddress", err)
+	}
+}
+
+func TestFetchLimits(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			time.Sleep(500 * time.Millisecond)
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{}`)
+			return
+		case "/large":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, "<html><head><!--"+strings.Repeat("x", 4096)+"-->")
+			fmt.Fprint(w, `<meta property="og:title" content="too late"></head></html>`)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, page)
+	}))
+	defer srv.Close()
+
+	f := newTestFetcher(100*time.Millisecond, 1024)
+	if _, err := f.Fetch(context.Background(), srv.URL+"/slow"); err == nil {
+		t.Error("Fetch() of a slow page succeeded, want timeout")
+	}
+	if _, err := f.Fetch(context.Background(), srv.URL+"/json"); !errors.Is(err, ErrNotHTML) {
+		t.Errorf("Fetch() of JSON error = %v, want ErrNotHTML", err)
+	}
+	if _, err := f.Fetch(context.Background(), srv.URL+"/large"); !errors.Is(err, ErrNoMetadata) {
+		t.Errorf("Fetch() past the size cap error = %v, want ErrNoMetadata", err)
+	}
+	if _, err := f.Fetch(context.Background(), "file:///etc/passwd"); !errors.Is(err, ErrUnsupportedURL) {
+		t.Errorf("Fetch(file://) error = %v, want ErrUnsupportedURL", err)
+	}
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34:443", true},
+		{"93.184.216.34:80", true},
+		{"93.184.216.34:22", false},
+		{"127.0.0.1:80", false},
+		{"10.1.2.3:443", false},
+		{"172.16.0.1:443", false},
+		{"192.168.1.1:80", false},
+		{"169.254.169.254:80", false},
+		{"100.64.0.1:80", false},
+		{"0.0.0.0:80", false},
+		{"[::1]:443", false},
+		{"[fd00::1]:443", false},
+		{"[fe80::1]:443", false},
+		{"[::ffff:127.0.0.1]:80", false},
+		{"[2606:4700:4700::1111]:443", true},
+	}
+
+	for _, tt := range tests {
+		if got := IsPublicAddr(netip.MustParseAddrPort(tt.addr)); got != tt.want {
+			t.Errorf("IsPublicAddr(%s) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
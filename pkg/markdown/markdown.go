@@ -0,0 +1,59 @@
+// Package markdown renders user-supplied Markdown into HTML that is safe to
+// embed in the web frontend. Only a small subset of Markdown is supported and
+// the rendered output is always passed through a strict allowlist sanitizer.
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+var (
+	renderer = goldmark.New(
+		goldmark.WithExtensions(
+			extension.Linkify,
+			extension.Strikethrough,
+		),
+	)
+
+	policy = newPolicy()
+)
+
+// newPolicy builds the sanitizer allowlist. Anything not listed here, such as
+// raw HTML, images, inline styles or event handlers, is stripped from the
+// output.
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+
+	p.AllowElements("p", "br", "strong", "em", "del", "blockquote", "ul", "ol", "li", "pre", "code", "hr")
+
+	p.AllowAttrs("href").OnElements("a")
+	p.AllowStandardURLs()
+	p.AllowURLSchemes("http", "https", "mailto")
+	p.RequireParseableURLs(true)
+	p.RequireNoFollowOnLinks(true)
+	p.RequireNoReferrerOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+
+	// Fenced code blocks keep their language hint for syntax highlighting
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[a-zA-Z0-9_+-]+$`)).OnElements("code")
+
+	return p
+}
+
+// Render converts Markdown source into sanitized HTML. Raw HTML in the source
+// is never passed through, and URLs are autolinked.
+func Render(source string) string {
+	var buf bytes.Buffer
+	if err := renderer.Convert([]byte(source), &buf); err != nil {
+		// Fall back to escaping the source as plain text
+		return "<p>" + html.EscapeString(source) + "</p>"
+	}
+
+	return policy.Sanitize(buf.String())
+}
@@ -0,0 +1,67 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:   "emphasis",
+			source: "**bold** and *italic*",
+			want:   []string{"<strong>bold</strong>", "<em>italic</em>"},
+		},
+		{
+			name:   "autolink",
+			source: "see https://example.com",
+			want:   []string{`href="https://example.com"`, `rel="nofollow noreferrer noopener"`, `target="_blank"`},
+		},
+		{
+			name:   "fenced code block",
+			source: "```go\nfmt.Println(\"<hi>\")\n```",
+			want:   []string{`<pre><code class="language-go">`, "&lt;hi&gt;"},
+		},
+		{
+			name:    "raw script tag",
+			source:  "hello <script>alert(1)</script>",
+			notWant: []string{"<script", "alert(1)</script>"},
+		},
+		{
+			name:    "javascript link",
+			source:  "[click](javascript:alert(1))",
+			notWant: []string{"javascript:"},
+		},
+		{
+			name:    "event handler attribute",
+			source:  `<img src=x onerror="alert(1)">`,
+			notWant: []string{"<img", "onerror"},
+		},
+		{
+			name:    "image syntax",
+			source:  "![tracker](https://example.com/pixel.png)",
+			notWant: []string{"<img"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Render(tt.source)
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("Render() = %q, want it to contain %q", got, w)
+				}
+			}
+			for _, nw := range tt.notWant {
+				if strings.Contains(got, nw) {
+					t.Errorf("Render() = %q, want it not to contain %q", got, nw)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+// Package pubsub implements an in-process broker delivering messages to the
+// subscribers of a topic, such as the connections of a user waiting for
+// their notifications.
+package pubsub
+
+import "sync"
+
+// Broker fans messages published on a topic out to its subscribers. Delivery
+// never blocks the publisher: a subscriber whose buffer is full misses the
+// message, so subscribers are expected to catch up from the source of the
+// messages when that matters. It is safe for concurrent use.
+type Broker[K comparable, V any] struct {
+	mu     sync.Mutex
+	buffer int
+	subs   map[K]map[chan V]struct{}
+}
+
+// NewBroker returns a broker buffering up to buffer messages per subscriber.
+func NewBroker[K comparable, V any](buffer int) *Broker[K, V] {
+	return &Broker[K, V]{
+		buffer: max(0, buffer),
+		subs:   make(map[K]map[chan V]struct{}),
+	}
+}
+
+// Subscribe returns a channel receiving the messages published on topic
+// from now on, and a function ending the subscription, which closes the
+// channel. The function may be called more than once.
+func (b *Broker[K, V]) Subscribe(topic K) (<-chan V, func()) {
+	ch := make(chan V, b.buffer)
+
+	b.mu.Lock()
+	if b.subs[topic] == nil {
+		b.subs[topic] = make(map[chan V]struct{})
+	}
+	b.subs[topic][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs[topic], ch)
+			if len(b.subs[topic]) == 0 {
+				delete(b.subs, topic)
+			}
+			close(ch)
+		})
+	}
+}
+
+// Publish delivers v to the current subscribers of topic and returns how
+// many received it.
+func (b *Broker[K, V]) Publish(topic K, v V) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delivered := 0
+	for ch := range b.subs[topic] {
+		select {
+		case ch <- v:
+			delivered++
+		default:
+		}
+	}
+	return delivered
+}
+
+// Subscribers returns the number of subscribers of topic.
+func (b *Broker[K, V]) Subscribers(topic K) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs[topic])
+}
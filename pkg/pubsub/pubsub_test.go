@@ -0,0 +1,95 @@
+package pubsub
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestPublishReachesSubscribersOfTopic(t *testing.T) {
+	b := NewBroker[int64, string](4)
+	a1, cancelA1 := b.Subscribe(1)
+	defer cancelA1()
+	a2, cancelA2 := b.Subscribe(1)
+	defer cancelA2()
+	other, cancelOther := b.Subscribe(2)
+	defer cancelOther()
+
+	if n := b.Publish(1, "hello"); n != 2 {
+		t.Fatalf("Publish delivered to %d subscribers, want 2", n)
+	}
+	for _, ch := range []<-chan string{a1, a2} {
+		if got := <-ch; got != "hello" {
+			t.Errorf("received %q, want %q", got, "hello")
+		}
+	}
+	select {
+	case got := <-other:
+		t.Errorf("subscriber of another topic received %q", got)
+	default:
+	}
+}
+
+func TestPublishWithoutSubscribers(t *testing.T) {
+	b := NewBroker[string, int](1)
+	if n := b.Publish("nobody", 1); n != 0 {
+		t.Errorf("Publish delivered to %d subscribers, want 0", n)
+	}
+}
+
+func TestPublishSkipsFullSubscribers(t *testing.T) {
+	b := NewBroker[int, int](1)
+	ch, cancel := b.Subscribe(1)
+	defer cancel()
+
+	if n := b.Publish(1, 1); n != 1 {
+		t.Fatalf("first Publish delivered to %d subscribers, want 1", n)
+	}
+	if n := b.Publish(1, 2); n != 0 {
+		t.Fatalf("Publish to a full subscriber delivered to %d subscribers, want 0", n)
+	}
+	if got := <-ch; got != 1 {
+		t.Errorf("received %d, want 1", got)
+	}
+}
+
+func TestCancelClosesAndUnsubscribes(t *testing.T) {
+	b := NewBroker[int, int](1)
+	ch, cancel := b.Subscribe(1)
+	if n := b.Subscribers(1); n != 1 {
+		t.Fatalf("Subscribers = %d, want 1", n)
+	}
+
+	cancel()
+	cancel()
+
+	if _, ok := <-ch; ok {
+		t.Error("channel still open after cancel")
+	}
+	if n := b.Subscribers(1); n != 0 {
+		t.Errorf("Subscribers = %d after cancel, want 0", n)
+	}
+	if n := b.Publish(1, 1); n != 0 {
+		t.Errorf("Publish delivered to %d subscribers after cancel, want 0", n)
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	b := NewBroker[int, int](8)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, cancel := b.Subscribe(i % 2)
+			cancel()
+		}()
+		go func() {
+			defer wg.Done()
+			b.Publish(i%2, i)
+		}()
+	}
+	wg.Wait()
+	if n := b.Subscribers(0) + b.Subscribers(1); n != 0 {
+		t.Errorf("Subscribers = %d, want 0", n)
+	}
+}
@@ -0,0 +1,182 @@
+package syndication
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFeed() *Feed {
+	published := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	return &Feed{
+		ID:       "https://example.com/users/1",
+		Title:    "Gopher's posts",
+		Link:     "https://example.com/users/1",
+		SelfLink: "https://api.example.com/v1/users/1/posts.atom",
+		Entries: []*Entry{
+			{
+				ID:         "https://example.com/posts/2",
+				Title:      "Second & last",
+				Link:       "https://example.com/posts/2",
+				Author:     "Gopher",
+				Content:    `<p>Hello <a href="https://go.dev">Go</a></p>`,
+				Categories: []string{"golang"},
+				Published:  published.Add(time.Hour),
+				Updated:    published.Add(time.Hour),
+			},
+			{
+				ID:        "https://example.com/posts/1",
+				Title:     "First",
+				Link:      "https://example.com/posts/1",
+				Author:    "Gopher",
+				Content:   "<p>Edited</p>",
+				Published: published,
+				Updated:   published.Add(3 * time.Hour),
+			},
+		},
+	}
+}
+
+func TestAtom(t *testing.T) {
+	doc, err := Atom(testFeed())
+	if err != nil {
+		t.Fatalf("Atom() error = %v", err)
+	}
+
+	var got struct {
+		XMLName xml.Name
+		Updated string `xml:"updated"`
+		Links   []struct {
+			Rel  string `xml:"rel,attr"`
+			Href string `xml:"href,attr"`
+		} `xml:"link"`
+		Entries []struct {
+			ID        string `xml:"id"`
+			Title     string `xml:"title"`
+			Published string `xml:"published"`
+			Updated   string `xml:"updated"`
+			Content   struct {
+				Type string `xml:"type,attr"`
+				Body string `xml:",chardata"`
+			} `xml:"content"`
+			Categories []struct {
+				Term string `xml:"term,attr"`
+			} `xml:"category"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(doc, &got); err != nil {
+		t.Fatalf("Atom() produced invalid XML: %v", err)
+	}
+
+	if got.XMLName.Space != "http://www.w3.org/2005/Atom" || got.XMLName.Local != "feed" {
+		t.Errorf("root = %v, want Atom feed", got.XMLName)
+	}
+	if got.Updated != "2024-03-01T13:00:00Z" {
+		t.Errorf("feed updated = %q, want latest entry update", got.Updated)
+	}
+	if len(got.Links) != 2 || got.Links[1].Rel != "self" || got.Links[1].Href != "https://api.example.com/v1/users/1/posts.atom" {
+		t.Errorf("links = %+v, want alternate and self", got.Links)
+	}
+	if len(got.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(got.Entries))
+	}
+	first := got.Entries[0]
+	if first.Title != "Second & last" {
+		t.Errorf("title = %q", first.Title)
+	}
+	if first.Content.Type != "html" || first.Content.Body != `<p>Hello <a href="https://go.dev">Go</a></p>` {
+		t.Errorf("content = %+v, want escaped HTML", first.Content)
+	}
+	if len(first.Categories) != 1 || first.Categories[0].Term != "golang" {
+		t.Errorf("categories = %+v", first.Categories)
+	}
+	if second := got.Entries[1]; second.Published != "2024-03-01T10:00:00Z" || second.Updated != "2024-03-01T13:00:00Z" {
+		t.Errorf("second entry published/updated = %q/%q", second.Published, second.Updated)
+	}
+}
+
+func TestRSS(t *testing.T) {
+	doc, err := RSS(testFeed())
+	if err != nil {
+		t.Fatalf("RSS() error = %v", err)
+	}
+
+	var got struct {
+		XMLName xml.Name
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title         string `xml:"title"`
+			Description   string `xml:"description"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				GUID struct {
+					IsPermaLink string `xml:"isPermaLink,attr"`
+					Value       string `xml:",chardata"`
+				} `xml:"guid"`
+				PubDate     string `xml:"pubDate"`
+				Description string `xml:"description"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(doc, &got); err != nil {
+		t.Fatalf("RSS() produced invalid XML: %v", err)
+	}
+
+	if got.XMLName.Local != "rss" || got.Version != "2.0" {
+		t.Errorf("root = %v version %q, want rss 2.0", got.XMLName, got.Version)
+	}
+	if got.Channel.Description != "Gopher's posts" {
+		t.Errorf("description = %q, want title fallback", got.Channel.Description)
+	}
+	if got.Channel.LastBuildDate != "Fri, 01 Mar 2024 13:00:00 +0000" {
+		t.Errorf("lastBuildDate = %q", got.Channel.LastBuildDate)
+	}
+	if len(got.Channel.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(got.Channel.Items))
+	}
+	item := got.Channel.Items[0]
+	if item.GUID.IsPermaLink != "true" || item.GUID.Value != "https://example.com/posts/2" {
+		t.Errorf("guid = %+v", item.GUID)
+	}
+	if item.PubDate != "Fri, 01 Mar 2024 11:00:00 +0000" {
+		t.Errorf("pubDate = %q", item.PubDate)
+	}
+	if !strings.Contains(item.Description, `<a href="https://go.dev">`) {
+		t.Errorf("description = %q, want HTML content", item.Description)
+	}
+}
+
+func TestLastModified(t *testing.T) {
+	f := testFeed()
+	if got, want := f.LastModified(), time.Date(2024, 3, 1, 13, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("LastModified() = %v, want %v", got, want)
+	}
+
+	f.Updated = time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	if got := f.LastModified(); !got.Equal(f.Updated) {
+		t.Errorf("LastModified() = %v, want explicit Updated %v", got, f.Updated)
+	}
+
+	if got := (&Feed{}).LastModified(); !got.IsZero() {
+		t.Errorf("LastModified() of empty feed = %v, want zero", got)
+	}
+}
+
+func TestETag(t *testing.T) {
+	a, _ := Atom(testFeed())
+	b, _ := Atom(testFeed())
+	if ETag(a) != ETag(b) {
+		t.Error("ETag() differs for identical documents")
+	}
+
+	f := testFeed()
+	f.Entries[0].Content = "<p>Changed</p>"
+	c, _ := Atom(f)
+	if ETag(a) == ETag(c) {
+		t.Error("ETag() did not change with the content")
+	}
+	if tag := ETag(a); !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+		t.Errorf("ETag() = %s, want quoted", tag)
+	}
+}
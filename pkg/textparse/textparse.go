@@ -0,0 +1,129 @@
+// Package textparse extracts entities such as @mentions, #hashtags and links
+// from user-written text.
+package textparse
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	// A mention or hashtag must start the text or follow a character that
+	// cannot be part of a word, so e-mail addresses and URL fragments are not
+	// picked up.
+	mentionPattern = regexp.MustCompile(`(?:^|[^\w@/])@([A-Za-z0-9_]{3,20})\b`)
+	hashtagPattern = regexp.MustCompile(`(?:^|[^\w#&/])#([A-Za-z][A-Za-z0-9_]{0,49})\b`)
+	urlPattern     = regexp.MustCompile(`https?://[^\s<>"'\x60]+`)
+)
+
+// maxURLLength is the longest URL ExtractURLs returns.
+const maxURLLength = 2048
+
+// ExtractMentions returns the distinct usernames mentioned in text as
+// @username, in order of first appearance.
+func ExtractMentions(text string) []string {
+	return extract(mentionPattern, text, false)
+}
+
+// ExtractHashtags returns the distinct hashtags used in text as #tag, lower
+// cased and in order of first appearance.
+func ExtractHashtags(text string) []string {
+	return extract(hashtagPattern, text, true)
+}
+
+// ExtractURLs returns the distinct http and https URLs in text, in order of
+// first appearance. Trailing punctuation that is more likely part of the
+// sentence than of the URL is dropped.
+func ExtractURLs(text string) []string {
+	matches := urlPattern.FindAllString(text, -1)
+	seen := make(map[string]bool, len(matches))
+	out := make([]string, 0, len(matches))
+
+	for _, m := range matches {
+		m = trimURL(m)
+		if len(m) > maxURLLength || seen[m] {
+			continue
+		}
+		if u, err := url.Parse(m); err != nil || u.Hostname() == "" {
+			continue
+		}
+		seen[m] = true
+		out = append(out, m)
+	}
+
+	return out
+}
+
+func trimURL(u string) string {
+	for len(u) > 0 {
+		last := u[len(u)-1]
+		switch {
+		case strings.IndexByte(".,;:!?*_~", last) >= 0:
+		case last == ')' && strings.Count(u, "(") < strings.Count(u, ")"):
+		case last == ']' && strings.Count(u, "[") < strings.Count(u, "]"):
+		default:
+			return u
+		}
+		u = u[:len(u)-1]
+	}
+	return u
+}
+
+func extract(pattern *regexp.Regexp, text string, lower bool) []string {
+	matches := pattern.FindAllStringSubmatch(text, -1)
+	seen := make(map[string]bool, len(matches))
+	out := make([]string, 0, len(matches))
+
+	for _, m := range matches {
+		value := m[1]
+		if lower {
+			value = strings.ToLower(value)
+		}
+		key := strings.ToLower(value)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, value)
+	}
+
+	return out
+}
+
+// RemoveTags returns tags without those in remove, comparing
+// case-insensitively.
+func RemoveTags(tags []string, remove []string) []string {
+	removed := make(map[string]bool, len(remove))
+	for _, t := range remove {
+		removed[strings.ToLower(t)] = true
+	}
+
+	kept := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if !removed[strings.ToLower(t)] {
+			kept = append(kept, t)
+		}
+	}
+
+	return kept
+}
+
+// MergeTags appends the tags from extra that are not already present in tags,
+// comparing case-insensitively. The merged tags are lower cased, the form
+// they are stored and looked up in.
+func MergeTags(tags []string, extra []string) []string {
+	seen := make(map[string]bool, len(tags)+len(extra))
+	merged := make([]string, 0, len(tags)+len(extra))
+
+	for _, t := range append(append([]string{}, tags...), extra...) {
+		key := strings.ToLower(t)
+		if t == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, key)
+	}
+
+	return merged
+}
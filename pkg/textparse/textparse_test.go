@@ -0,0 +1,91 @@
+package textparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractMentions(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "single", text: "hello @gopher", want: []string{"gopher"}},
+		{name: "start of text", text: "@alice hi", want: []string{"alice"}},
+		{name: "duplicates", text: "@bob and @bob again", want: []string{"bob"}},
+		{name: "email is not a mention", text: "mail me at me@example.com", want: []string{}},
+		{name: "too short", text: "hey @ab", want: []string{}},
+		{name: "punctuation", text: "thanks (@carol), @dave!", want: []string{"carol", "dave"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractMentions(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractMentions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractHashtags(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "lower cased", text: "learning #Go today", want: []string{"go"}},
+		{name: "case-insensitive duplicates", text: "#go #GO #golang", want: []string{"go", "golang"}},
+		{name: "html entity", text: "fish &#38; chips", want: []string{}},
+		{name: "url fragment", text: "see https://example.com/page#section", want: []string{}},
+		{name: "must start with a letter", text: "#1 fan", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractHashtags(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractHashtags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractURLs(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "plain", text: "see https://example.com/a?b=c", want: []string{"https://example.com/a?b=c"}},
+		{name: "sentence punctuation", text: "read http://example.com/post.", want: []string{"http://example.com/post"}},
+		{name: "markdown link", text: "[docs](https://go.dev/doc)", want: []string{"https://go.dev/doc"}},
+		{name: "balanced parens", text: "https://en.wikipedia.org/wiki/Go_(programming_language)", want: []string{"https://en.wikipedia.org/wiki/Go_(programming_language)"}},
+		{name: "duplicates", text: "https://a.example https://a.example", want: []string{"https://a.example"}},
+		{name: "other schemes", text: "ftp://example.com javascript:alert(1)", want: []string{}},
+		{name: "no host", text: "https:// nothing", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractURLs(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractURLs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	got := MergeTags([]string{"Go", "backend"}, []string{"go", "Chat"})
+	want := []string{"go", "backend", "chat"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeTags() = %v, want %v", got, want)
+	}
+}
+
+func TestRemoveTags(t *testing.T) {
+	got := RemoveTags([]string{"Go", "backend", "chat"}, []string{"go", "CHAT", "rust"})
+	want := []string{"backend"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RemoveTags() = %v, want %v", got, want)
+	}
+}
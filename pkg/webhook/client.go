@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/LikhithMar14/gopher-chat/pkg/linkpreview"
+)
+
+const (
+	// maxResponseBytes caps the part of a response kept for the delivery
+	// log.
+	maxResponseBytes = 4 << 10
+	userAgent        = "gopher-chat-webhooks/1.0 (+https://github.com/LikhithMar14/gopher-chat)"
+)
+
+var (
+	ErrUnsupportedURL   = errors.New("webhook: only http and https URLs can be delivered to")
+	ErrForbiddenAddress = errors.New("webhook: address is not publicly routable")
+)
+
+// Delivery is a payload to deliver to a webhook.
+type Delivery struct {
+	URL    string
+	Secret string
+	Event  string
+	ID     int64
+	Body   []byte
+}
+
+// Response is the answer of a webhook to a delivery.
+type Response struct {
+	StatusCode int
+	// Body holds the start of the response body.
+	Body     string
+	Duration time.Duration
+}
+
+// OK reports whether the webhook accepted the delivery.
+func (r *Response) OK() bool {
+	return r.StatusCode >= 200 && r.StatusCode <= 299
+}
+
+// Temporary reports whether a delivery answered with r may succeed when
+// retried. Requests the webhook rejects as malformed are not retried.
+func (r *Response) Temporary() bool {
+	return r.StatusCode >= 500 || r.StatusCode == http.StatusRequestTimeout ||
+		r.StatusCode == http.StatusTooManyRequests || r.StatusCode < 400
+}
+
+// Client sends webhook deliveries. Redirects are not followed, so a webhook
+// must be registered with its final URL. Like the link preview fetcher, it
+// refuses to connect to non-public addresses unless it is told otherwise. It
+// is safe for concurrent use.
+type Client struct {
+	client *http.Client
+	// allowAddr decides whether a resolved address may be dialled.
+	allowAddr func(netip.AddrPort) bool
+}
+
+// NewClient returns a Client giving up on deliveries after timeout. With
+// allowPrivate it delivers to any address, such as receivers on the local
+// network or machine.
+func NewClient(timeout time.Duration, allowPrivate bool) *Client {
+	c := &Client{allowAddr: linkpreview.IsPublicAddr}
+	if allowPrivate {
+		c.allowAddr = func(netip.AddrPort) bool { return true }
+	}
+
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addr, err := netip.ParseAddrPort(address)
+			if err != nil || !c.allowAddr(addr) {
+				return ErrForbiddenAddress
+			}
+			return nil
+		},
+	}
+
+	c.client = &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   timeout,
+			ResponseHeaderTimeout: timeout,
+			MaxIdleConns:          50,
+			IdleConnTimeout:       90 * time.Second,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return c
+}
+
+// Send POSTs a delivery, signed with its secret at the current time. An
+// error is returned only when no response was received; the Response tells
+// whether the webhook accepted the delivery.
+func (c *Client) Send(ctx context.Context, d Delivery) (*Response, error) {
+	u, err := url.Parse(d.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return nil, ErrUnsupportedURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(d.Body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(EventHeader, d.Event)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(SignatureHeader, Sign(d.Secret, time.Now(), d.Body))
+
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrForbiddenAddress) {
+			return nil, ErrForbiddenAddress
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+		Duration:   time.Since(start),
+	}, nil
+}
@@ -0,0 +1,114 @@
+// Package webhook signs and sends webhook deliveries: JSON payloads POSTed
+// to a subscriber's URL with an HMAC-SHA256 signature over the payload and
+// the time it was sent, which receivers verify with the secret they share
+// with the sender.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SignatureHeader carries the signature of a delivery, in the form
+	// "t=<unix seconds>,v1=<hex HMAC-SHA256>".
+	SignatureHeader = "X-Gopher-Chat-Signature"
+	// EventHeader carries the name of the event delivered.
+	EventHeader = "X-Gopher-Chat-Event"
+	// DeliveryHeader carries the id of the delivery, which stays the same
+	// across the attempts to deliver it.
+	DeliveryHeader = "X-Gopher-Chat-Delivery"
+
+	// DefaultTolerance is how old a signature receivers should accept, which
+	// limits the replay of captured deliveries.
+	DefaultTolerance = 5 * time.Minute
+
+	// secretPrefix marks webhook secrets, so they are recognised when they
+	// leak.
+	secretPrefix = "whsec_"
+	secretBytes  = 32
+)
+
+var (
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+	ErrExpiredSignature = errors.New("webhook: signature timestamp is outside the tolerance")
+)
+
+// NewSecret returns a random secret to sign the deliveries of a webhook
+// with.
+func NewSecret() (string, error) {
+	b := make([]byte, secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return secretPrefix + hex.EncodeToString(b), nil
+}
+
+// Sign returns the value of the SignatureHeader of body sent at t. The
+// HMAC covers "<unix seconds>.<body>", so a signature can't be reused with
+// another timestamp.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac(secret, ts, body))
+}
+
+// Verify checks the SignatureHeader value header of body received at now
+// against secret. Signatures made more than tolerance before or after now
+// are rejected with ErrExpiredSignature. Any of several v1 signatures may
+// match, which lets senders sign with an old and a new secret while the
+// secret is rotated.
+func Verify(secret, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var ts string
+	var sigs [][]byte
+	for _, part := range strings.Split(header, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return ErrInvalidSignature
+		}
+		switch name {
+		case "t":
+			ts = value
+		case "v1":
+			sig, err := hex.DecodeString(value)
+			if err != nil {
+				return ErrInvalidSignature
+			}
+			sigs = append(sigs, sig)
+		}
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || len(sigs) == 0 {
+		return ErrInvalidSignature
+	}
+
+	want := mac(secret, ts, body)
+	matched := false
+	for _, sig := range sigs {
+		if hmac.Equal(sig, want) {
+			matched = true
+		}
+	}
+	if !matched {
+		return ErrInvalidSignature
+	}
+
+	if skew := now.Sub(time.Unix(unix, 0)); skew > tolerance || skew < -tolerance {
+		return ErrExpiredSignature
+	}
+	return nil
+}
+
+func mac(secret, ts string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(h, "%s.", ts)
+	h.Write(body)
+	return h.Sum(nil)
+}
@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	secret, err := NewSecret()
+	if err != nil {
+		t.Fatalf("NewSecret() error = %v", err)
+	}
+	if !strings.HasPrefix(secret, secretPrefix) {
+		t.Errorf("NewSecret() = %q, want prefix %q", secret, secretPrefix)
+	}
+
+	body := []byte(`{"event":"post.created"}`)
+	now := time.Unix(1700000000, 0)
+	header := Sign(secret, now, body)
+	if !strings.HasPrefix(header, "t=1700000000,v1=") {
+		t.Errorf("Sign() = %q", header)
+	}
+
+	tests := []struct {
+		name   string
+		secret string
+		header string
+		body   string
+		now    time.Time
+		want   error
+	}{
+		{"valid", secret, header, string(body), now, nil},
+		{"within tolerance", secret, header, string(body), now.Add(DefaultTolerance), nil},
+		{"rotated secret", secret, "t=1700000000,v1=00ff," + strings.TrimPrefix(header, "t=1700000000,"), string(body), now, nil},
+		{"tampered body", secret, header, `{"event":"post.deleted"}`, now, ErrInvalidSignature},
+		{"other secret", "whsec_other", header, string(body), now, ErrInvalidSignature},
+		{"other timestamp", secret, strings.Replace(header, "t=1700000000", "t=1700000001", 1), string(body), now, ErrInvalidSignature},
+		{"expired", secret, header, string(body), now.Add(DefaultTolerance + time.Second), ErrExpiredSignature},
+		{"from the future", secret, header, string(body), now.Add(-DefaultTolerance - time.Second), ErrExpiredSignature},
+		{"no signature", secret, "t=1700000000", string(body), now, ErrInvalidSignature},
+		{"malformed", secret, "garbage", string(body), now, ErrInvalidSignature},
+		{"empty", secret, "", string(body), now, ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.header, []byte(tt.body), DefaultTolerance, tt.now)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientSend(t *testing.T) {
+	const secret = "whsec_test"
+	body := []byte(`{"event":"user.registered"}`)
+
+	var got *http.Request
+	var gotBody []byte
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(strings.Repeat("x", maxResponseBytes+10)))
+	}))
+	defer srv.Close()
+
+	c := NewClient(5*time.Second, true)
+	resp, err := c.Send(context.Background(), Delivery{URL: srv.URL + "/hook", Secret: secret, Event: "user.registered", ID: 42, Body: body})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if !resp.OK() || resp.StatusCode != http.StatusOK {
+		t.Errorf("Send() status = %d, OK() = %v", resp.StatusCode, resp.OK())
+	}
+	if len(resp.Body) != maxResponseBytes {
+		t.Errorf("len(Response.Body) = %d, want %d", len(resp.Body), maxResponseBytes)
+	}
+
+	if got.Method != http.MethodPost || got.URL.Path != "/hook" {
+		t.Errorf("request = %s %s", got.Method, got.URL.Path)
+	}
+	if got.Header.Get(EventHeader) != "user.registered" || got.Header.Get(DeliveryHeader) != "42" {
+		t.Errorf("event = %q, delivery = %q", got.Header.Get(EventHeader), got.Header.Get(DeliveryHeader))
+	}
+	if err := Verify(secret, got.Header.Get(SignatureHeader), gotBody, DefaultTolerance, time.Now()); err != nil {
+		t.Errorf("Verify() of the delivered request error = %v", err)
+	}
+
+	for _, tt := range []struct {
+		status    int
+		temporary bool
+	}{
+		{http.StatusInternalServerError, true},
+		{http.StatusTooManyRequests, true},
+		{http.StatusFound, true},
+		{http.StatusBadRequest, false},
+		{http.StatusGone, false},
+	} {
+		status = tt.status
+		resp, err := c.Send(context.Background(), Delivery{URL: srv.URL, Secret: secret, Event: "e", Body: body})
+		if err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+		if resp.OK() || resp.Temporary() != tt.temporary {
+			t.Errorf("status %d: OK() = %v, Temporary() = %v, want false, %v", tt.status, resp.OK(), resp.Temporary(), tt.temporary)
+		}
+	}
+
+	if _, err := c.Send(context.Background(), Delivery{URL: "ftp://example.com/hook"}); !errors.Is(err, ErrUnsupportedURL) {
+		t.Errorf("Send() to ftp URL error = %v, want ErrUnsupportedURL", err)
+	}
+}
+
+func TestClientRejectsPrivateAddresses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached the server")
+	}))
+	defer srv.Close()
+
+	_, err := NewClient(time.Second, false).Send(context.Background(), Delivery{URL: srv.URL + "/hook", Secret: "s", Event: "e", Body: []byte(`{}`)})
+	if !errors.Is(err, ErrForbiddenAddress) {
+		t.Errorf("Send() error = %v, want ErrForbiddenAddress", err)
+	}
+}